@@ -0,0 +1,30 @@
+// Package api holds the definitions of the service APIs, the OpenAPI document of the REST API and
+// the protobuf definitions of the gRPC API
+package api
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/pkg/errors"
+)
+
+// openAPI is the OpenAPI 3 document of the REST API
+//
+//go:embed openapi.yaml
+var openAPI []byte
+
+// LoadOpenAPI parses and validates the OpenAPI document of the REST API
+func LoadOpenAPI() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPI)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse openapi document")
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, errors.Wrap(err, "invalid openapi document")
+	}
+
+	return doc, nil
+}
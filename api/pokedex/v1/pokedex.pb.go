@@ -0,0 +1,805 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        v4.25.3
+// source: api/pokedex/v1/pokedex.proto
+
+package pokedexv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Pokemon struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Number      int32    `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	Description string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Habitat     string   `protobuf:"bytes,4,opt,name=habitat,proto3" json:"habitat,omitempty"`
+	Generation  string   `protobuf:"bytes,5,opt,name=generation,proto3" json:"generation,omitempty"`
+	Types       []string `protobuf:"bytes,6,rep,name=types,proto3" json:"types,omitempty"`
+	// stats maps PokeAPI stat names to base stats
+	Stats       map[string]int32 `protobuf:"bytes,7,rep,name=stats,proto3" json:"stats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	IsLegendary bool             `protobuf:"varint,8,opt,name=is_legendary,json=isLegendary,proto3" json:"is_legendary,omitempty"`
+	IsMythical  bool             `protobuf:"varint,9,opt,name=is_mythical,json=isMythical,proto3" json:"is_mythical,omitempty"`
+	EvolvesFrom string           `protobuf:"bytes,10,opt,name=evolves_from,json=evolvesFrom,proto3" json:"evolves_from,omitempty"`
+}
+
+func (x *Pokemon) Reset() {
+	*x = Pokemon{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Pokemon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pokemon) ProtoMessage() {}
+
+func (x *Pokemon) ProtoReflect() protoreflect.Message {
+	mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pokemon.ProtoReflect.Descriptor instead.
+func (*Pokemon) Descriptor() ([]byte, []int) {
+	return file_api_pokedex_v1_pokedex_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pokemon) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Pokemon) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Pokemon) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Pokemon) GetHabitat() string {
+	if x != nil {
+		return x.Habitat
+	}
+	return ""
+}
+
+func (x *Pokemon) GetGeneration() string {
+	if x != nil {
+		return x.Generation
+	}
+	return ""
+}
+
+func (x *Pokemon) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *Pokemon) GetStats() map[string]int32 {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *Pokemon) GetIsLegendary() bool {
+	if x != nil {
+		return x.IsLegendary
+	}
+	return false
+}
+
+func (x *Pokemon) GetIsMythical() bool {
+	if x != nil {
+		return x.IsMythical
+	}
+	return false
+}
+
+func (x *Pokemon) GetEvolvesFrom() string {
+	if x != nil {
+		return x.EvolvesFrom
+	}
+	return ""
+}
+
+// Translation describes how a description was translated
+type Translation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Style     string `protobuf:"bytes,1,opt,name=style,proto3" json:"style,omitempty"`
+	Succeeded bool   `protobuf:"varint,2,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	// fallback_reason is set when the original description was returned
+	FallbackReason string `protobuf:"bytes,3,opt,name=fallback_reason,json=fallbackReason,proto3" json:"fallback_reason,omitempty"`
+	Cached         bool   `protobuf:"varint,4,opt,name=cached,proto3" json:"cached,omitempty"`
+}
+
+func (x *Translation) Reset() {
+	*x = Translation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Translation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Translation) ProtoMessage() {}
+
+func (x *Translation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Translation.ProtoReflect.Descriptor instead.
+func (*Translation) Descriptor() ([]byte, []int) {
+	return file_api_pokedex_v1_pokedex_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Translation) GetStyle() string {
+	if x != nil {
+		return x.Style
+	}
+	return ""
+}
+
+func (x *Translation) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *Translation) GetFallbackReason() string {
+	if x != nil {
+		return x.FallbackReason
+	}
+	return ""
+}
+
+func (x *Translation) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+type TranslatedPokemon struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// pokemon has the translated description
+	Pokemon     *Pokemon     `protobuf:"bytes,1,opt,name=pokemon,proto3" json:"pokemon,omitempty"`
+	Translation *Translation `protobuf:"bytes,2,opt,name=translation,proto3" json:"translation,omitempty"`
+}
+
+func (x *TranslatedPokemon) Reset() {
+	*x = TranslatedPokemon{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TranslatedPokemon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TranslatedPokemon) ProtoMessage() {}
+
+func (x *TranslatedPokemon) ProtoReflect() protoreflect.Message {
+	mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TranslatedPokemon.ProtoReflect.Descriptor instead.
+func (*TranslatedPokemon) Descriptor() ([]byte, []int) {
+	return file_api_pokedex_v1_pokedex_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TranslatedPokemon) GetPokemon() *Pokemon {
+	if x != nil {
+		return x.Pokemon
+	}
+	return nil
+}
+
+func (x *TranslatedPokemon) GetTranslation() *Translation {
+	if x != nil {
+		return x.Translation
+	}
+	return nil
+}
+
+type GetPokemonRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetPokemonRequest) Reset() {
+	*x = GetPokemonRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPokemonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPokemonRequest) ProtoMessage() {}
+
+func (x *GetPokemonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPokemonRequest.ProtoReflect.Descriptor instead.
+func (*GetPokemonRequest) Descriptor() ([]byte, []int) {
+	return file_api_pokedex_v1_pokedex_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPokemonRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetTranslatedPokemonRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetTranslatedPokemonRequest) Reset() {
+	*x = GetTranslatedPokemonRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTranslatedPokemonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTranslatedPokemonRequest) ProtoMessage() {}
+
+func (x *GetTranslatedPokemonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTranslatedPokemonRequest.ProtoReflect.Descriptor instead.
+func (*GetTranslatedPokemonRequest) Descriptor() ([]byte, []int) {
+	return file_api_pokedex_v1_pokedex_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTranslatedPokemonRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type BatchGetPokemonRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// names are at most 50 names, duplicates are fetched once
+	Names []string `protobuf:"bytes,1,rep,name=names,proto3" json:"names,omitempty"`
+}
+
+func (x *BatchGetPokemonRequest) Reset() {
+	*x = BatchGetPokemonRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetPokemonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetPokemonRequest) ProtoMessage() {}
+
+func (x *BatchGetPokemonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetPokemonRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetPokemonRequest) Descriptor() ([]byte, []int) {
+	return file_api_pokedex_v1_pokedex_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BatchGetPokemonRequest) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+type BatchGetPokemonResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// pokemons are in the order of the requested names
+	Pokemons []*Pokemon `protobuf:"bytes,1,rep,name=pokemons,proto3" json:"pokemons,omitempty"`
+}
+
+func (x *BatchGetPokemonResponse) Reset() {
+	*x = BatchGetPokemonResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetPokemonResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetPokemonResponse) ProtoMessage() {}
+
+func (x *BatchGetPokemonResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetPokemonResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetPokemonResponse) Descriptor() ([]byte, []int) {
+	return file_api_pokedex_v1_pokedex_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BatchGetPokemonResponse) GetPokemons() []*Pokemon {
+	if x != nil {
+		return x.Pokemons
+	}
+	return nil
+}
+
+type ListPokemonRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Habitat    string `protobuf:"bytes,1,opt,name=habitat,proto3" json:"habitat,omitempty"`
+	Type       string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Generation string `protobuf:"bytes,3,opt,name=generation,proto3" json:"generation,omitempty"`
+	Legendary  *bool  `protobuf:"varint,4,opt,name=legendary,proto3,oneof" json:"legendary,omitempty"`
+	Mythical   *bool  `protobuf:"varint,5,opt,name=mythical,proto3,oneof" json:"mythical,omitempty"`
+}
+
+func (x *ListPokemonRequest) Reset() {
+	*x = ListPokemonRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPokemonRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPokemonRequest) ProtoMessage() {}
+
+func (x *ListPokemonRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_pokedex_v1_pokedex_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPokemonRequest.ProtoReflect.Descriptor instead.
+func (*ListPokemonRequest) Descriptor() ([]byte, []int) {
+	return file_api_pokedex_v1_pokedex_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListPokemonRequest) GetHabitat() string {
+	if x != nil {
+		return x.Habitat
+	}
+	return ""
+}
+
+func (x *ListPokemonRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListPokemonRequest) GetGeneration() string {
+	if x != nil {
+		return x.Generation
+	}
+	return ""
+}
+
+func (x *ListPokemonRequest) GetLegendary() bool {
+	if x != nil && x.Legendary != nil {
+		return *x.Legendary
+	}
+	return false
+}
+
+func (x *ListPokemonRequest) GetMythical() bool {
+	if x != nil && x.Mythical != nil {
+		return *x.Mythical
+	}
+	return false
+}
+
+var File_api_pokedex_v1_pokedex_proto protoreflect.FileDescriptor
+
+var file_api_pokedex_v1_pokedex_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2f, 0x76, 0x31,
+	0x2f, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a,
+	0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x22, 0xfe, 0x02, 0x0a, 0x07, 0x50,
+	0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62,
+	0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x61, 0x62, 0x69, 0x74, 0x61, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x68, 0x61, 0x62, 0x69, 0x74, 0x61, 0x74, 0x12, 0x1e,
+	0x0a, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x07, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x73,
+	0x5f, 0x6c, 0x65, 0x67, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x69, 0x73, 0x4c, 0x65, 0x67, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x12, 0x1f, 0x0a,
+	0x0b, 0x69, 0x73, 0x5f, 0x6d, 0x79, 0x74, 0x68, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0a, 0x69, 0x73, 0x4d, 0x79, 0x74, 0x68, 0x69, 0x63, 0x61, 0x6c, 0x12, 0x21,
+	0x0a, 0x0c, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x73, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x76, 0x6f, 0x6c, 0x76, 0x65, 0x73, 0x46, 0x72, 0x6f,
+	0x6d, 0x1a, 0x38, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x82, 0x01, 0x0a, 0x0b,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x79, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x79, 0x6c,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x12,
+	0x27, 0x0a, 0x0f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61,
+	0x63, 0x6b, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64,
+	0x22, 0x7d, 0x0a, 0x11, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x50, 0x6f,
+	0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x2d, 0x0a, 0x07, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x07, 0x70, 0x6f, 0x6b,
+	0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x39, 0x0a, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6b, 0x65,
+	0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0x27, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x31, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x2e, 0x0a, 0x16, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x22, 0x4a, 0x0a, 0x17, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x70, 0x6f, 0x6b, 0x65, 0x6d, 0x6f,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x6f, 0x6b, 0x65, 0x64,
+	0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x08, 0x70,
+	0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x73, 0x22, 0xc1, 0x01, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74,
+	0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x68, 0x61, 0x62, 0x69, 0x74, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x68, 0x61, 0x62, 0x69, 0x74, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1e, 0x0a, 0x0a,
+	0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x09,
+	0x6c, 0x65, 0x67, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x00, 0x52, 0x09, 0x6c, 0x65, 0x67, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x88, 0x01, 0x01, 0x12,
+	0x1f, 0x0a, 0x08, 0x6d, 0x79, 0x74, 0x68, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x01, 0x52, 0x08, 0x6d, 0x79, 0x74, 0x68, 0x69, 0x63, 0x61, 0x6c, 0x88, 0x01, 0x01,
+	0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6c, 0x65, 0x67, 0x65, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x6d, 0x79, 0x74, 0x68, 0x69, 0x63, 0x61, 0x6c, 0x32, 0xd4, 0x02, 0x0a, 0x0e,
+	0x50, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40,
+	0x0a, 0x0a, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x1d, 0x2e, 0x70,
+	0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6b,
+	0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x6f,
+	0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x12, 0x5e, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x65,
+	0x64, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x27, 0x2e, 0x70, 0x6f, 0x6b, 0x65, 0x64,
+	0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61,
+	0x74, 0x65, 0x64, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74, 0x65, 0x64, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x12, 0x5a, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6b, 0x65,
+	0x6d, 0x6f, 0x6e, 0x12, 0x22, 0x2e, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31,
+	0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65,
+	0x78, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x50, 0x6f, 0x6b,
+	0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0b,
+	0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x1e, 0x2e, 0x70, 0x6f,
+	0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6b,
+	0x65, 0x6d, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x70, 0x6f,
+	0x6b, 0x65, 0x64, 0x65, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6b, 0x65, 0x6d, 0x6f, 0x6e,
+	0x30, 0x01, 0x42, 0x22, 0x5a, 0x20, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2f, 0x61, 0x70,
+	0x69, 0x2f, 0x70, 0x6f, 0x6b, 0x65, 0x64, 0x65, 0x78, 0x2f, 0x76, 0x31, 0x3b, 0x70, 0x6f, 0x6b,
+	0x65, 0x64, 0x65, 0x78, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_pokedex_v1_pokedex_proto_rawDescOnce sync.Once
+	file_api_pokedex_v1_pokedex_proto_rawDescData = file_api_pokedex_v1_pokedex_proto_rawDesc
+)
+
+func file_api_pokedex_v1_pokedex_proto_rawDescGZIP() []byte {
+	file_api_pokedex_v1_pokedex_proto_rawDescOnce.Do(func() {
+		file_api_pokedex_v1_pokedex_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_pokedex_v1_pokedex_proto_rawDescData)
+	})
+	return file_api_pokedex_v1_pokedex_proto_rawDescData
+}
+
+var file_api_pokedex_v1_pokedex_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_api_pokedex_v1_pokedex_proto_goTypes = []interface{}{
+	(*Pokemon)(nil),                     // 0: pokedex.v1.Pokemon
+	(*Translation)(nil),                 // 1: pokedex.v1.Translation
+	(*TranslatedPokemon)(nil),           // 2: pokedex.v1.TranslatedPokemon
+	(*GetPokemonRequest)(nil),           // 3: pokedex.v1.GetPokemonRequest
+	(*GetTranslatedPokemonRequest)(nil), // 4: pokedex.v1.GetTranslatedPokemonRequest
+	(*BatchGetPokemonRequest)(nil),      // 5: pokedex.v1.BatchGetPokemonRequest
+	(*BatchGetPokemonResponse)(nil),     // 6: pokedex.v1.BatchGetPokemonResponse
+	(*ListPokemonRequest)(nil),          // 7: pokedex.v1.ListPokemonRequest
+	nil,                                 // 8: pokedex.v1.Pokemon.StatsEntry
+}
+var file_api_pokedex_v1_pokedex_proto_depIdxs = []int32{
+	8, // 0: pokedex.v1.Pokemon.stats:type_name -> pokedex.v1.Pokemon.StatsEntry
+	0, // 1: pokedex.v1.TranslatedPokemon.pokemon:type_name -> pokedex.v1.Pokemon
+	1, // 2: pokedex.v1.TranslatedPokemon.translation:type_name -> pokedex.v1.Translation
+	0, // 3: pokedex.v1.BatchGetPokemonResponse.pokemons:type_name -> pokedex.v1.Pokemon
+	3, // 4: pokedex.v1.PokedexService.GetPokemon:input_type -> pokedex.v1.GetPokemonRequest
+	4, // 5: pokedex.v1.PokedexService.GetTranslatedPokemon:input_type -> pokedex.v1.GetTranslatedPokemonRequest
+	5, // 6: pokedex.v1.PokedexService.BatchGetPokemon:input_type -> pokedex.v1.BatchGetPokemonRequest
+	7, // 7: pokedex.v1.PokedexService.ListPokemon:input_type -> pokedex.v1.ListPokemonRequest
+	0, // 8: pokedex.v1.PokedexService.GetPokemon:output_type -> pokedex.v1.Pokemon
+	2, // 9: pokedex.v1.PokedexService.GetTranslatedPokemon:output_type -> pokedex.v1.TranslatedPokemon
+	6, // 10: pokedex.v1.PokedexService.BatchGetPokemon:output_type -> pokedex.v1.BatchGetPokemonResponse
+	0, // 11: pokedex.v1.PokedexService.ListPokemon:output_type -> pokedex.v1.Pokemon
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_api_pokedex_v1_pokedex_proto_init() }
+func file_api_pokedex_v1_pokedex_proto_init() {
+	if File_api_pokedex_v1_pokedex_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_pokedex_v1_pokedex_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Pokemon); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_pokedex_v1_pokedex_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Translation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_pokedex_v1_pokedex_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TranslatedPokemon); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_pokedex_v1_pokedex_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPokemonRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_pokedex_v1_pokedex_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTranslatedPokemonRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_pokedex_v1_pokedex_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetPokemonRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_pokedex_v1_pokedex_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetPokemonResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_pokedex_v1_pokedex_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPokemonRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_api_pokedex_v1_pokedex_proto_msgTypes[7].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_pokedex_v1_pokedex_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_pokedex_v1_pokedex_proto_goTypes,
+		DependencyIndexes: file_api_pokedex_v1_pokedex_proto_depIdxs,
+		MessageInfos:      file_api_pokedex_v1_pokedex_proto_msgTypes,
+	}.Build()
+	File_api_pokedex_v1_pokedex_proto = out.File
+	file_api_pokedex_v1_pokedex_proto_rawDesc = nil
+	file_api_pokedex_v1_pokedex_proto_goTypes = nil
+	file_api_pokedex_v1_pokedex_proto_depIdxs = nil
+}
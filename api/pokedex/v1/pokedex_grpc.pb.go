@@ -0,0 +1,258 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: api/pokedex/v1/pokedex.proto
+
+package pokedexv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PokedexService_GetPokemon_FullMethodName           = "/pokedex.v1.PokedexService/GetPokemon"
+	PokedexService_GetTranslatedPokemon_FullMethodName = "/pokedex.v1.PokedexService/GetTranslatedPokemon"
+	PokedexService_BatchGetPokemon_FullMethodName      = "/pokedex.v1.PokedexService/BatchGetPokemon"
+	PokedexService_ListPokemon_FullMethodName          = "/pokedex.v1.PokedexService/ListPokemon"
+)
+
+// PokedexServiceClient is the client API for PokedexService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PokedexServiceClient interface {
+	// GetPokemon fetches a Pokemon for a given name
+	GetPokemon(ctx context.Context, in *GetPokemonRequest, opts ...grpc.CallOption) (*Pokemon, error)
+	// GetTranslatedPokemon fetches a Pokemon with a translated description for a given name
+	GetTranslatedPokemon(ctx context.Context, in *GetTranslatedPokemonRequest, opts ...grpc.CallOption) (*TranslatedPokemon, error)
+	// BatchGetPokemon fetches several Pokemons at once, it fails when any of them is not found
+	BatchGetPokemon(ctx context.Context, in *BatchGetPokemonRequest, opts ...grpc.CallOption) (*BatchGetPokemonResponse, error)
+	// ListPokemon streams the Pokemons matching the filters ordered by number, it needs the local
+	// Pokemon store
+	ListPokemon(ctx context.Context, in *ListPokemonRequest, opts ...grpc.CallOption) (PokedexService_ListPokemonClient, error)
+}
+
+type pokedexServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPokedexServiceClient(cc grpc.ClientConnInterface) PokedexServiceClient {
+	return &pokedexServiceClient{cc}
+}
+
+func (c *pokedexServiceClient) GetPokemon(ctx context.Context, in *GetPokemonRequest, opts ...grpc.CallOption) (*Pokemon, error) {
+	out := new(Pokemon)
+	err := c.cc.Invoke(ctx, PokedexService_GetPokemon_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pokedexServiceClient) GetTranslatedPokemon(ctx context.Context, in *GetTranslatedPokemonRequest, opts ...grpc.CallOption) (*TranslatedPokemon, error) {
+	out := new(TranslatedPokemon)
+	err := c.cc.Invoke(ctx, PokedexService_GetTranslatedPokemon_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pokedexServiceClient) BatchGetPokemon(ctx context.Context, in *BatchGetPokemonRequest, opts ...grpc.CallOption) (*BatchGetPokemonResponse, error) {
+	out := new(BatchGetPokemonResponse)
+	err := c.cc.Invoke(ctx, PokedexService_BatchGetPokemon_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pokedexServiceClient) ListPokemon(ctx context.Context, in *ListPokemonRequest, opts ...grpc.CallOption) (PokedexService_ListPokemonClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PokedexService_ServiceDesc.Streams[0], PokedexService_ListPokemon_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &pokedexServiceListPokemonClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PokedexService_ListPokemonClient interface {
+	Recv() (*Pokemon, error)
+	grpc.ClientStream
+}
+
+type pokedexServiceListPokemonClient struct {
+	grpc.ClientStream
+}
+
+func (x *pokedexServiceListPokemonClient) Recv() (*Pokemon, error) {
+	m := new(Pokemon)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PokedexServiceServer is the server API for PokedexService service.
+// All implementations must embed UnimplementedPokedexServiceServer
+// for forward compatibility
+type PokedexServiceServer interface {
+	// GetPokemon fetches a Pokemon for a given name
+	GetPokemon(context.Context, *GetPokemonRequest) (*Pokemon, error)
+	// GetTranslatedPokemon fetches a Pokemon with a translated description for a given name
+	GetTranslatedPokemon(context.Context, *GetTranslatedPokemonRequest) (*TranslatedPokemon, error)
+	// BatchGetPokemon fetches several Pokemons at once, it fails when any of them is not found
+	BatchGetPokemon(context.Context, *BatchGetPokemonRequest) (*BatchGetPokemonResponse, error)
+	// ListPokemon streams the Pokemons matching the filters ordered by number, it needs the local
+	// Pokemon store
+	ListPokemon(*ListPokemonRequest, PokedexService_ListPokemonServer) error
+	mustEmbedUnimplementedPokedexServiceServer()
+}
+
+// UnimplementedPokedexServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPokedexServiceServer struct {
+}
+
+func (UnimplementedPokedexServiceServer) GetPokemon(context.Context, *GetPokemonRequest) (*Pokemon, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPokemon not implemented")
+}
+func (UnimplementedPokedexServiceServer) GetTranslatedPokemon(context.Context, *GetTranslatedPokemonRequest) (*TranslatedPokemon, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTranslatedPokemon not implemented")
+}
+func (UnimplementedPokedexServiceServer) BatchGetPokemon(context.Context, *BatchGetPokemonRequest) (*BatchGetPokemonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGetPokemon not implemented")
+}
+func (UnimplementedPokedexServiceServer) ListPokemon(*ListPokemonRequest, PokedexService_ListPokemonServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPokemon not implemented")
+}
+func (UnimplementedPokedexServiceServer) mustEmbedUnimplementedPokedexServiceServer() {}
+
+// UnsafePokedexServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PokedexServiceServer will
+// result in compilation errors.
+type UnsafePokedexServiceServer interface {
+	mustEmbedUnimplementedPokedexServiceServer()
+}
+
+func RegisterPokedexServiceServer(s grpc.ServiceRegistrar, srv PokedexServiceServer) {
+	s.RegisterService(&PokedexService_ServiceDesc, srv)
+}
+
+func _PokedexService_GetPokemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPokemonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PokedexServiceServer).GetPokemon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PokedexService_GetPokemon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PokedexServiceServer).GetPokemon(ctx, req.(*GetPokemonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PokedexService_GetTranslatedPokemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTranslatedPokemonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PokedexServiceServer).GetTranslatedPokemon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PokedexService_GetTranslatedPokemon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PokedexServiceServer).GetTranslatedPokemon(ctx, req.(*GetTranslatedPokemonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PokedexService_BatchGetPokemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetPokemonRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PokedexServiceServer).BatchGetPokemon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PokedexService_BatchGetPokemon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PokedexServiceServer).BatchGetPokemon(ctx, req.(*BatchGetPokemonRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PokedexService_ListPokemon_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPokemonRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PokedexServiceServer).ListPokemon(m, &pokedexServiceListPokemonServer{stream})
+}
+
+type PokedexService_ListPokemonServer interface {
+	Send(*Pokemon) error
+	grpc.ServerStream
+}
+
+type pokedexServiceListPokemonServer struct {
+	grpc.ServerStream
+}
+
+func (x *pokedexServiceListPokemonServer) Send(m *Pokemon) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PokedexService_ServiceDesc is the grpc.ServiceDesc for PokedexService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PokedexService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pokedex.v1.PokedexService",
+	HandlerType: (*PokedexServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPokemon",
+			Handler:    _PokedexService_GetPokemon_Handler,
+		},
+		{
+			MethodName: "GetTranslatedPokemon",
+			Handler:    _PokedexService_GetTranslatedPokemon_Handler,
+		},
+		{
+			MethodName: "BatchGetPokemon",
+			Handler:    _PokedexService_BatchGetPokemon_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPokemon",
+			Handler:       _PokedexService_ListPokemon_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/pokedex/v1/pokedex.proto",
+}
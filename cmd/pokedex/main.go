@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"pokedex/internal/cli"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/funtranslations"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/client"
+	httpclient "pokedex/pkg/http/client"
+	"pokedex/pkg/secret"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	modeService = "service"
+	modeDirect  = "direct"
+
+	// secretEnv is the variable the service reads the Funtranslations API secret from
+	secretEnv = "POKEDEX_THIRD_PARTY_FUNTRANSLATIONS_API_SECRET"
+)
+
+func main() {
+	if err := run(); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "pokedex:", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	fs := flag.NewFlagSet("pokedex", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: pokedex [flags] [name ...]")
+		fmt.Fprintln(fs.Output(), "Looks Pokemons up, names are read from stdin, one per line, when none are given or the name is -")
+		fs.PrintDefaults()
+	}
+
+	var (
+		mode               = fs.String("mode", modeService, "look up through the running service or directly through the upstreams: service or direct")
+		url                = fs.String("url", "http://localhost:5050", "pokedex service base url, service mode only")
+		pokeapiURL         = fs.String("pokeapi-url", "https://pokeapi.co", "PokeAPI base url, direct mode only")
+		funtranslationsURL = fs.String("funtranslations-url", "https://api.funtranslations.com", "Funtranslations base url, direct mode only, the API secret is read from "+secretEnv)
+		translate          = fs.Bool("translate", false, "translate the descriptions")
+		output             = fs.String("output", cli.FormatTable, "output format: "+strings.Join(cli.Formats, ", "))
+		timing             = fs.Bool("timing", false, "show the duration and translation cache status of every lookup")
+		concurrency        = fs.Int("concurrency", 4, "number of concurrent lookups")
+		timeout            = fs.Duration("timeout", 10*time.Second, "request timeout")
+		verbose            = fs.Bool("verbose", false, "log upstream requests")
+	)
+
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return err
+	}
+
+	if !validFormat(*output) {
+		return errors.Errorf("unknown output format %q", *output)
+	}
+
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+
+	if *verbose {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+
+	names := fs.Args()
+	if len(names) == 0 || (len(names) == 1 && names[0] == "-") {
+		var err error
+		if names, err = cli.ReadNames(os.Stdin); err != nil {
+			return err
+		}
+	}
+
+	for i, name := range names {
+		names[i] = strings.ToLower(name)
+	}
+
+	if len(names) == 0 {
+		return errors.New("no names given")
+	}
+
+	var (
+		src cli.Source
+		err error
+	)
+
+	switch *mode {
+	case modeService:
+		src, err = serviceSource(*url, *timeout)
+	case modeDirect:
+		src, err = directSource(*pokeapiURL, *funtranslationsURL, *timeout)
+	default:
+		err = errors.Errorf("unknown mode %q", *mode)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	results, err := cli.Lookup(ctx, src, names, *translate, *concurrency)
+	if err != nil {
+		return err
+	}
+
+	if err := cli.Write(os.Stdout, results, *output, *timing); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, res := range results {
+		if res.Err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d lookups failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func validFormat(format string) bool {
+	for _, f := range cli.Formats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+func serviceSource(url string, timeout time.Duration) (cli.Source, error) {
+	c, err := client.New(
+		url,
+		client.WithHTTPClient(&http.Client{Timeout: timeout}),
+		client.WithMiddleware(httpclient.Logging(&log.Logger, "pokedex")),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create pokedex client")
+	}
+
+	return cli.ServiceSource(c), nil
+}
+
+func directSource(pokeapiURL string, funtranslationsURL string, timeout time.Duration) (cli.Source, error) {
+	pokeClient, err := pokeapi.New(
+		pokeapiURL,
+		httpclient.WithTimeout(timeout),
+		httpclient.WithMiddleware(
+			httpclient.Logging(&log.Logger, "pokeapi"),
+			httpclient.Retry(3, 100*time.Millisecond),
+		),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new pokeapi client")
+	}
+
+	funtranslationsClient, err := funtranslations.New(
+		funtranslationsURL,
+		httpclient.WithTimeout(timeout),
+		httpclient.WithMiddleware(httpclient.Logging(&log.Logger, "funtranslations")),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new funtranslations client")
+	}
+
+	if value, ok := os.LookupEnv(secretEnv); ok && value != "" {
+		funtranslationsClient.SetSecret(secret.Static(value))
+	}
+
+	translateService, err := pokemon.NewTranslateService(funtranslationsClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create new pokemon service")
+	}
+
+	return cli.DirectSource(pokeClient, translateService), nil
+}
@@ -1,16 +1,30 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"pokedex/api"
 	"pokedex/config"
+	"pokedex/internal/graphql"
+	"pokedex/internal/grpc"
 	"pokedex/internal/handler"
 	"pokedex/internal/router"
 	"pokedex/internal/service/pokemon"
 	"pokedex/pkg/adapter/funtranslations"
 	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/store"
+	"pokedex/pkg/cache"
+	"pokedex/pkg/health"
+	"pokedex/pkg/http/client"
+	"pokedex/pkg/queue"
+	"pokedex/pkg/secret"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
@@ -22,34 +36,527 @@ func main() {
 }
 
 func setup() error {
-	cfg, err := config.Load()
+	loader, err := config.NewLoader(os.Args[1:], os.LookupEnv)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse flags")
+	}
+
+	cfg, err := loader.Load()
 	if err != nil {
 		return errors.Wrap(err, "failed to load config")
 	}
 
-	pokeClient, err := pokeapi.New(cfg.ThirdParty.PokeAPI.Url)
+	if loader.PrintConfig {
+		return cfg.Print(os.Stdout)
+	}
+
+	pokeClient, err := pokeapi.New(
+		cfg.ThirdParty.PokeAPI.Url,
+		client.WithTimeout(cfg.ThirdParty.PokeAPI.Timeout),
+		client.WithMaxBodySize(cfg.ThirdParty.PokeAPI.MaxResponseSize),
+		client.WithStrict(cfg.ThirdParty.PokeAPI.Strict),
+		client.WithMiddleware(
+			client.Tracing(),
+			client.Logging(&log.Logger, "pokeapi"),
+			client.Retry(3, 100*time.Millisecond),
+		),
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to create new pokeapi client")
 	}
-	funtranslationsClient, err := funtranslations.New(cfg.ThirdParty.Funtranslations.Url)
+	funtranslationsClient, err := funtranslations.New(
+		cfg.ThirdParty.Funtranslations.Url,
+		client.WithTimeout(cfg.ThirdParty.Funtranslations.Timeout),
+		client.WithMaxBodySize(cfg.ThirdParty.Funtranslations.MaxResponseSize),
+		client.WithStrict(cfg.ThirdParty.Funtranslations.Strict),
+		client.WithMiddleware(
+			client.Tracing(),
+			client.Logging(&log.Logger, "funtranslations"),
+		),
+	)
 	if err != nil {
 		return errors.Wrap(err, "failed to create new funtranslations client")
 	}
+	funtranslationsClient.SetSecret(secretSource(cfg.ThirdParty.Funtranslations))
+
+	var snapshot *pokeapi.Snapshot
+	if cfg.Snapshot.Enabled {
+		snapshot, err = pokeapi.NewSnapshot(cfg.Snapshot.Path)
+		if err != nil {
+			return errors.Wrap(err, "failed to open pokeapi snapshot")
+		}
+
+		log.Info().Str("path", cfg.Snapshot.Path).Int("species", snapshot.Len()).Bool("fallback", cfg.Snapshot.Fallback).Msg("using pokeapi snapshot")
+	}
+
+	fetcher, err := pokemonFetcher(cfg.Snapshot, snapshot, pokeClient)
+	if err != nil {
+		return errors.Wrap(err, "failed to create pokemon fetcher")
+	}
+
+	var (
+		handlers router.Handlers
+		repo     pokeapi.PokemonRepository
+	)
+
+	if cfg.Store.Enabled {
+		db, err := store.Open(cfg.Store.Path)
+		if err != nil {
+			return errors.Wrap(err, "failed to open pokemon store")
+		}
+		defer db.Close()
+
+		if snapshot != nil {
+			n, err := pokeapi.Populate(context.Background(), db, snapshot)
+			if err != nil {
+				return errors.Wrap(err, "failed to populate pokemon store")
+			}
+
+			log.Info().Str("path", cfg.Store.Path).Int("pokemons", n).Msg("populated pokemon store from snapshot")
+		}
+
+		fetcher = pokeapi.ReadThrough(db, fetcher)
+		repo = db
+		handlers.QueryPokemon = handler.QueryPokemon(db)
+	}
 
-	translateService, err := pokemon.NewTranslateService(funtranslationsClient)
+	var remote *cache.RESP
+
+	if cfg.Cache.Backend == config.CacheBackendRESP {
+		remote, err = cache.NewRESP(cache.RESPOptions{
+			Address:     cfg.Cache.RESP.Address,
+			Password:    cfg.Cache.RESP.Password,
+			DB:          cfg.Cache.RESP.DB,
+			Prefix:      cfg.Cache.RESP.KeyPrefix,
+			DialTimeout: cfg.Cache.RESP.DialTimeout,
+			Timeout:     cfg.Cache.RESP.Timeout,
+			PoolSize:    cfg.Cache.RESP.PoolSize,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to create resp cache backend")
+		}
+		defer remote.Close()
+	}
+
+	var (
+		textTranslator funtranslations.Translator = funtranslationsClient
+		budget         *funtranslations.Budget
+	)
+
+	if cfg.TranslationBudget.Enabled {
+		// only the current hourly and daily windows are counted, older ones expire
+		usage, err := sharedBackend(cfg.Cache, remote, 4)
+		if err != nil {
+			return errors.Wrap(err, "failed to create translation budget usage")
+		}
+
+		budget, err = funtranslations.NewBudget(funtranslationsClient, usage, budgetOptions(cfg.TranslationBudget, funtranslationsClient))
+		if err != nil {
+			return errors.Wrap(err, "failed to create translation budget")
+		}
+
+		textTranslator = budget
+		handlers.Quota = handler.TranslationQuota(budget)
+	}
+
+	// the breaker is in front of the budget so translations skipped while it is open are not counted
+	if cfg.TranslationBreaker.Enabled {
+		breaker, err := funtranslations.NewBreaker(textTranslator, funtranslations.BreakerOptions{
+			Failures: cfg.TranslationBreaker.Failures,
+			Cooldown: cfg.TranslationBreaker.Cooldown,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to create translation circuit breaker")
+		}
+
+		textTranslator = breaker
+	}
+
+	translateService, err := pokemon.NewTranslateService(textTranslator)
 	if err != nil {
 		return errors.Wrap(err, "failed to create new pokemon service")
 	}
 
-	router := router.New(router.Handlers{
-		HealthCheck: handler.HealthCheck,
+	var (
+		pokemons      *pokeapi.Cache
+		translations  *pokemon.CachedTranslator
+		warmer        *pokemon.Warmer
+		translator    pokemon.DescriptionTranslator = translateService
+		checker                                     = health.NewChecker()
+		upstreamCheck                               = health.Options{Timeout: cfg.Health.Timeout, TTL: cfg.Health.TTL}
+		localCheck                                  = health.Options{Timeout: cfg.Health.Timeout}
+	)
+
+	if snapshot == nil || cfg.Snapshot.Fallback {
+		err := checker.Register("pokeapi", func(ctx context.Context) (interface{}, error) {
+			return nil, pokeClient.Ping(ctx)
+		}, upstreamCheck)
+		if err != nil {
+			return errors.Wrap(err, "failed to register pokeapi check")
+		}
+	}
+
+	// translations fall back to the original description, Funtranslations is not required
+	err = checker.Register("funtranslations", funtranslationsClient.Check, health.Options{
+		Timeout:  cfg.Health.Timeout,
+		TTL:      cfg.Health.TTL,
+		Optional: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to register funtranslations check")
+	}
+
+	if cfg.Cache.Enabled {
+		var requests *cache.Counter
+
+		pokemons, translations, requests, err = caches(cfg.Cache, remote, fetcher, translateService)
+		if err != nil {
+			return errors.Wrap(err, "failed to create caches")
+		}
+
+		warmer, err = pokemon.NewWarmer(pokemons.Uncounted(), translations, pokemon.WarmerOptions{
+			Concurrency: cfg.Cache.Warmup.Concurrency,
+			Rate:        cfg.Cache.Warmup.Rate,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to create cache warmer")
+		}
+
+		if cfg.Cache.Warmup.HistoryPath != "" {
+			history, err := cache.NewHistory(cfg.Cache.Warmup.HistoryPath, requests)
+			if err != nil {
+				return errors.Wrap(err, "failed to create request history")
+			}
+
+			if err := history.Load(); err != nil {
+				log.Error().Err(err).Msg("failed to load request history, warming up without it")
+			}
+
+			go history.Run(context.Background(), cfg.Cache.Warmup.HistoryInterval)
+		}
+
+		// both caches share the backend, while a remote one is down the in-process caches are used
+		err = checker.Register("cache", func(ctx context.Context) (interface{}, error) {
+			return map[string]string{"backend": cfg.Cache.Backend}, pokemons.Ping(ctx)
+		}, health.Options{
+			Timeout:  cfg.Health.Timeout,
+			TTL:      cfg.Health.TTL,
+			Optional: remote != nil,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to register cache check")
+		}
+
+		if cfg.Cache.Warmup.Enabled {
+			warmup, err := pokemon.NewWarmupProgress(cfg.Cache.Warmup.ReadyThreshold)
+			if err != nil {
+				return errors.Wrap(err, "failed to create cache warm-up")
+			}
+
+			if err := checker.Register("warmup", warmup.Check, localCheck); err != nil {
+				return errors.Wrap(err, "failed to register warm-up check")
+			}
+
+			go warmUp(cfg.Cache.Warmup, warmer, warmup, requests)
+		}
+
+		fetcher = pokemons
+		translator = translations
+		handlers.Admin = router.AdminHandlers{
+			CacheStats:  handler.GetCacheStats(pokemons, translations, requests),
+			CacheLookup: handler.LookupCache(pokemons, translations),
+			CachePurge:  handler.PurgeCache(pokemons, translations),
+			CacheWarmup: handler.WarmupCache(warmer, requests),
+		}
+	}
+
+	if cfg.GraphQL.Enabled {
+		handlers.GraphQL, err = graphql.NewHandler(fetcher, translator, graphql.Options{
+			Limits: graphql.Limits{
+				MaxDepth:      cfg.GraphQL.MaxDepth,
+				MaxComplexity: cfg.GraphQL.MaxComplexity,
+			},
+			GraphiQL: cfg.GraphQL.GraphiQL,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to create graphql handler")
+		}
+	}
+
+	var adminSource secret.Var
+	adminSource.Set(adminToken(cfg.Admin))
+
+	watcher := config.NewWatcher(loader, cfg)
+	watcher.OnReload(func(cfg *config.Config) {
+		if err := pokeClient.Reconfigure(cfg.ThirdParty.PokeAPI.Url, cfg.ThirdParty.PokeAPI.Timeout); err != nil {
+			log.Error().Err(err).Msg("failed to reconfigure pokeapi client")
+		}
+		if err := pokeClient.SetDecoding(cfg.ThirdParty.PokeAPI.MaxResponseSize, cfg.ThirdParty.PokeAPI.Strict); err != nil {
+			log.Error().Err(err).Msg("failed to reconfigure pokeapi client")
+		}
+		if err := funtranslationsClient.Reconfigure(cfg.ThirdParty.Funtranslations.Url, cfg.ThirdParty.Funtranslations.Timeout); err != nil {
+			log.Error().Err(err).Msg("failed to reconfigure funtranslations client")
+		}
+		if err := funtranslationsClient.SetDecoding(cfg.ThirdParty.Funtranslations.MaxResponseSize, cfg.ThirdParty.Funtranslations.Strict); err != nil {
+			log.Error().Err(err).Msg("failed to reconfigure funtranslations client")
+		}
+		funtranslationsClient.SetSecret(secretSource(cfg.ThirdParty.Funtranslations))
+		adminSource.Set(adminToken(cfg.Admin))
+		setLogLevel(cfg.Service.LogLevel)
+
+		if pokemons != nil {
+			reconfigureCaches(cfg.Cache, pokemons, translations, warmer)
+		}
+		if budget != nil {
+			if err := budget.SetOptions(budgetOptions(cfg.TranslationBudget, funtranslationsClient)); err != nil {
+				log.Error().Err(err).Msg("failed to reconfigure translation budget")
+			}
+		}
+	})
+
+	go watcher.Run(context.Background())
+
+	// a rejected reload keeps the current configuration, the service still works
+	err = checker.Register("config", func(ctx context.Context) (interface{}, error) {
+		return nil, watcher.Err()
+	}, health.Options{Timeout: cfg.Health.Timeout, Optional: true})
+	if err != nil {
+		return errors.Wrap(err, "failed to register config check")
+	}
+
+	doc, err := api.LoadOpenAPI()
+	if err != nil {
+		return errors.Wrap(err, "failed to load openapi document")
+	}
+
+	handlers.HealthCheck = handler.HealthCheck
+	handlers.Live = handler.Live
+	handlers.Ready = handler.Ready(checker)
+	handlers.Admin.Ready = handler.ReadyReport(checker)
+	handlers.OpenAPI = handler.OpenAPI(doc)
+	handlers.Docs = handler.Docs("/docs", "/openapi.json")
+	handlers.GetPokemonByName = handler.GetPokemonByName(fetcher)
+	handlers.GetPokemonByNameTranslated = handler.GetPokemonByNameTranslated(fetcher, translator)
 
-		GetPokemonByName:           handler.GetPokemonByName(pokeClient),
-		GetPokemonByNameTranslated: handler.GetPokemonByNameTranslated(pokeClient, translateService),
+	if cfg.Jobs.Enabled {
+		q, err := queue.Open(cfg.Jobs.Path)
+		if err != nil {
+			return errors.Wrap(err, "failed to open translation job queue")
+		}
+		defer q.Close()
+
+		jobs, err := pokemon.NewJobs(q, translator, pokemon.JobOptions{
+			Workers:         cfg.Jobs.Workers,
+			MaxPending:      cfg.Jobs.MaxPending,
+			MaxAttempts:     cfg.Jobs.MaxAttempts,
+			RetryDelay:      cfg.Jobs.RetryDelay,
+			Retention:       cfg.Jobs.Retention,
+			CallbackHosts:   cfg.Jobs.CallbackHosts,
+			CallbackTimeout: cfg.Jobs.CallbackTimeout,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to create translation jobs")
+		}
+
+		go jobs.Run(context.Background())
+
+		handlers.GetPokemonByNameTranslated = handler.TranslateAsync(fetcher, jobs, handlers.GetPokemonByNameTranslated)
+		handlers.GetJob = handler.GetJob(jobs)
+	}
+
+	admin := router.NewAdmin(handlers, router.AdminOptions{
+		Token: adminSource.Get,
 	})
 
+	routerOpts := router.Options{
+		PokemonMaxAge: func() time.Duration {
+			return watcher.Config().Service.CacheControl.Pokemon
+		},
+		PokemonTranslatedMaxAge: func() time.Duration {
+			return watcher.Config().Service.CacheControl.PokemonTranslated
+		},
+	}
+
+	// without fallback every Pokemon comes from the snapshot, they last changed with it
+	if snapshot != nil && !cfg.Snapshot.Fallback {
+		routerOpts.PokemonLastModified = snapshot.ModTime
+	}
+
+	router := router.New(handlers, routerOpts)
+
+	setLogLevel(cfg.Service.LogLevel)
+
+	if cfg.GRPC.Enabled {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPC.Port))
+		if err != nil {
+			return errors.Wrap(err, "failed to listen for grpc")
+		}
+
+		grpcServer := grpc.NewServer(fetcher, translator, repo)
+		defer grpcServer.GracefulStop()
+
+		go func() {
+			log.Info().Str("port", cfg.GRPC.Port).Msg("starting grpc service")
+
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Error().Err(err).Msg("grpc service stopped")
+			}
+		}()
+	}
+
+	if cfg.Admin.Enabled {
+		go func() {
+			log.Info().Str("port", cfg.Admin.Port).Msg("starting admin service")
+
+			if err := http.ListenAndServe(fmt.Sprintf(":%s", cfg.Admin.Port), admin); err != nil {
+				log.Error().Err(err).Msg("admin service stopped")
+			}
+		}()
+	}
+
+	log.Info().Strs("checks", checker.Names()).Msg("registered readiness checks")
 	log.Info().Str("port", cfg.Service.Port).Msg("starting service")
 	defer log.Info().Msg("stopped service")
 
 	return http.ListenAndServe(fmt.Sprintf(":%s", cfg.Service.Port), router)
 }
+
+func setLogLevel(level string) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid log level")
+		return
+	}
+
+	zerolog.SetGlobalLevel(lvl)
+}
+
+// secretSource returns the source of the API secret for an external API, nil when none is configured
+func secretSource(api config.API) secret.Source {
+	switch {
+	case api.APISecretFile != "":
+		return secret.NewFile(api.APISecretFile)
+	case api.APISecret != "":
+		return secret.Static(api.APISecret)
+	}
+
+	return nil
+}
+
+// adminToken returns the source of the admin token, nil when none is configured
+func adminToken(admin config.Admin) secret.Source {
+	switch {
+	case admin.TokenFile != "":
+		return secret.NewFile(admin.TokenFile)
+	case admin.Token != "":
+		return secret.Static(admin.Token)
+	}
+
+	return nil
+}
+
+// budgetOptions returns the translation budgets, also skipping translations while the quota last
+// reported by Funtranslations is exhausted
+func budgetOptions(cfg config.TranslationBudget, client *funtranslations.Client) funtranslations.BudgetOptions {
+	return funtranslations.BudgetOptions{
+		Hourly:  cfg.Hourly,
+		Daily:   cfg.Daily,
+		Reserve: cfg.Reserve,
+		Quota:   client.Quota,
+	}
+}
+
+// reconfigureCaches applies the reloaded cache TTLs and warm-up limits
+func reconfigureCaches(cfg config.Cache, pokemons *pokeapi.Cache, translations *pokemon.CachedTranslator, warmer *pokemon.Warmer) {
+	if err := pokemons.SetTTL(cfg.Pokemon.TTL); err != nil {
+		log.Error().Err(err).Msg("failed to reconfigure pokemon cache")
+	}
+	if err := translations.SetTTL(cfg.Translation.TTL); err != nil {
+		log.Error().Err(err).Msg("failed to reconfigure translation cache")
+	}
+	err := warmer.SetOptions(pokemon.WarmerOptions{
+		Concurrency: cfg.Warmup.Concurrency,
+		Rate:        cfg.Warmup.Rate,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to reconfigure cache warmer")
+	}
+}
+
+// warmUp warms up the configured names followed by the most requested ones, reporting to progress
+func warmUp(cfg config.Warmup, warmer *pokemon.Warmer, progress *pokemon.WarmupProgress, requests *cache.Counter) {
+	names := append([]string{}, cfg.Names...)
+	for _, nc := range requests.Top(cfg.Top) {
+		names = append(names, nc.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	log.Info().Int("names", len(names)).Msg("warming up caches")
+
+	start := time.Now()
+	res := warmer.WarmTracked(ctx, names, progress)
+
+	log.Info().
+		Int("requested", res.Requested).
+		Int("warmed", res.Warmed).
+		Int("failed", len(res.Failed)).
+		Dur("took", time.Since(start)).
+		Msg("warmed up caches")
+}
+
+// caches puts the Pokemon and translation caches in front of fetcher and translator. They are kept
+// in remote when given, falling back to in-process caches while it is down
+func caches(cfg config.Cache, remote *cache.RESP, fetcher pokeapi.PokemonFetcher, translator pokemon.DescriptionTranslator) (*pokeapi.Cache, *pokemon.CachedTranslator, *cache.Counter, error) {
+	requests, err := cache.NewCounter(cfg.TrackedNames)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pokemonBackend, err := sharedBackend(cfg, remote, cfg.Pokemon.MaxEntries)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pokemons, err := pokeapi.NewCache(fetcher, pokemonBackend, cfg.Pokemon.TTL, requests)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	translationBackend, err := sharedBackend(cfg, remote, cfg.Translation.MaxEntries)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	translations, err := pokemon.NewCachedTranslator(translator, translationBackend, cfg.Translation.TTL)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return pokemons, translations, requests, nil
+}
+
+// sharedBackend returns remote, falling back to an in-process backend of maxEntries while it is down,
+// or only the in-process backend without remote
+func sharedBackend(cfg config.Cache, remote *cache.RESP, maxEntries int) (cache.Backend, error) {
+	local, err := cache.NewMemory(maxEntries)
+	if err != nil || remote == nil {
+		return local, err
+	}
+
+	return cache.NewFallback(remote, local, cfg.RESP.RetryInterval)
+}
+
+// pokemonFetcher returns the live PokeAPI client unless a local snapshot is enabled
+func pokemonFetcher(cfg config.Snapshot, snapshot *pokeapi.Snapshot, live pokeapi.PokemonFetcher) (pokeapi.PokemonFetcher, error) {
+	if snapshot == nil {
+		return live, nil
+	}
+
+	if !cfg.Fallback {
+		return snapshot, nil
+	}
+
+	return pokeapi.NewFallback(snapshot, live)
+}
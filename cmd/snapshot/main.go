@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"pokedex/internal/snapshot"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/http/client"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal().Err(err).Msg("failed to build the snapshot")
+	}
+}
+
+func run() error {
+	fs := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+
+	var (
+		dir         = fs.String("out", "snapshot", "dataset directory, an existing dataset is resumed")
+		url         = fs.String("url", "https://pokeapi.co", "PokeAPI base url")
+		resources   = fs.String("resources", strings.Join(snapshot.DefaultResources, ","), "comma separated resources to mirror")
+		concurrency = fs.Int("concurrency", 4, "number of concurrent requests")
+		rate        = fs.Float64("rate", 10, "maximum requests per second, 0 for unlimited")
+		refresh     = fs.Bool("refresh", false, "revalidate the dataset and only download changed resources")
+		timeout     = fs.Duration("timeout", 30*time.Second, "request timeout")
+	)
+
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return err
+	}
+
+	pokeClient, err := pokeapi.New(
+		*url,
+		client.WithTimeout(*timeout),
+		client.WithMaxBodySize(32<<20),
+		client.WithMiddleware(
+			client.Logging(&log.Logger, "pokeapi"),
+			client.Retry(3, time.Second),
+		),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create new pokeapi client")
+	}
+
+	builder, err := snapshot.NewBuilder(pokeClient, snapshot.Options{
+		Dir:         *dir,
+		BaseURL:     *url,
+		Resources:   strings.Split(*resources, ","),
+		Concurrency: *concurrency,
+		Rate:        *rate,
+		Refresh:     *refresh,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create snapshot builder")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stats, err := builder.Run(ctx)
+
+	log.Info().
+		Int("fetched", stats.Fetched).
+		Int("unchanged", stats.Unchanged).
+		Int("skipped", stats.Skipped).
+		Int("removed", stats.Removed).
+		Int("failed", stats.Failed).
+		Str("dir", *dir).
+		Msg("snapshot run finished")
+
+	return err
+}
@@ -1,25 +1,55 @@
 package config
 
 import (
+	"io"
 	"os"
+	"reflect"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
 const (
-	defaultConfigPath = "../../config.yaml"
+	defaultConfigPath = "config.yaml"
+
+	envPrefix = "POKEDEX"
+
+	redacted = "[REDACTED]"
+
+	// CacheBackendMemory caches in-process, each replica has its own caches
+	CacheBackendMemory = "memory"
+	// CacheBackendRESP caches in a Redis protocol server shared by the replicas
+	CacheBackendRESP = "resp"
 )
 
 type (
 	// Config variables for the application
 	Config struct {
-		Service    Service    `yaml:"service"`
-		ThirdParty ThirdParty `yaml:"third_party"`
+		Service            Service            `yaml:"service"`
+		ThirdParty         ThirdParty         `yaml:"third_party"`
+		Snapshot           Snapshot           `yaml:"snapshot"`
+		Store              Store              `yaml:"store"`
+		GraphQL            GraphQL            `yaml:"graphql"`
+		GRPC               GRPC               `yaml:"grpc"`
+		Cache              Cache              `yaml:"cache"`
+		Admin              Admin              `yaml:"admin"`
+		Health             Health             `yaml:"health"`
+		TranslationBudget  TranslationBudget  `yaml:"translation_budget"`
+		TranslationBreaker TranslationBreaker `yaml:"translation_breaker"`
+		Jobs               Jobs               `yaml:"jobs"`
 	}
 
 	// Service represents service configuration
 	Service struct {
-		Port string `yaml:"port"`
+		Port         string       `yaml:"port"`
+		LogLevel     string       `yaml:"log_level"`
+		CacheControl CacheControl `yaml:"cache_control"`
+	}
+
+	// CacheControl represents Cache-Control max-age values per route
+	CacheControl struct {
+		Pokemon           time.Duration `yaml:"pokemon"`
+		PokemonTranslated time.Duration `yaml:"pokemon_translated"`
 	}
 
 	// ThirdParty represents configuration for third party services
@@ -28,26 +58,275 @@ type (
 		PokeAPI         API `yaml:"pokeapi"`
 	}
 
-	// API variables for an external API
+	// Snapshot represents a local PokeAPI data snapshot, a directory, .zip or .tar.gz in the api-data
+	// layout, used instead of the live API. With Fallback the live API is only used on a snapshot miss
+	Snapshot struct {
+		Enabled  bool   `yaml:"enabled"`
+		Path     string `yaml:"path"`
+		Fallback bool   `yaml:"fallback"`
+	}
+
+	// Store represents the embedded Pokemon database. When enabled Pokemons are served from it first,
+	// Pokemons fetched on a miss are saved to it and it is populated from the snapshot on start
+	Store struct {
+		Enabled bool   `yaml:"enabled"`
+		Path    string `yaml:"path"`
+	}
+
+	// GraphQL represents the /graphql endpoint. Queries deeper or more complex than the limits are
+	// rejected, GraphiQL is meant for development only
+	GraphQL struct {
+		Enabled       bool `yaml:"enabled"`
+		GraphiQL      bool `yaml:"graphiql"`
+		MaxDepth      int  `yaml:"max_depth"`
+		MaxComplexity int  `yaml:"max_complexity"`
+	}
+
+	// GRPC represents the gRPC API, served on its own port alongside the REST API
+	GRPC struct {
+		Enabled bool   `yaml:"enabled"`
+		Port    string `yaml:"port"`
+	}
+
+	// Cache represents the Pokemon and translation caches, kept in-process or in a RESP server shared
+	// by the replicas depending on Backend. The most requested Pokemons are tracked, up to
+	// TrackedNames names, so they can be warmed up
+	Cache struct {
+		Enabled      bool       `yaml:"enabled"`
+		Backend      string     `yaml:"backend"`
+		RESP         RESP       `yaml:"resp"`
+		Pokemon      CacheEntry `yaml:"pokemon"`
+		Translation  CacheEntry `yaml:"translation"`
+		TrackedNames int        `yaml:"tracked_names"`
+		Warmup       Warmup     `yaml:"warmup"`
+	}
+
+	// CacheEntry represents how long and how many entries of a kind are cached, MaxEntries only
+	// bounds the in-process caches
+	CacheEntry struct {
+		TTL        time.Duration `yaml:"ttl"`
+		MaxEntries int           `yaml:"max_entries"`
+	}
+
+	// RESP represents the connection to a Redis protocol server. Keys are prefixed with KeyPrefix,
+	// while the server is unreachable the in-process caches are used and it is retried every
+	// RetryInterval
+	RESP struct {
+		Address       string        `yaml:"address"`
+		Password      string        `yaml:"password,omitempty" secret:"true"`
+		DB            int           `yaml:"db"`
+		KeyPrefix     string        `yaml:"key_prefix"`
+		DialTimeout   time.Duration `yaml:"dial_timeout"`
+		Timeout       time.Duration `yaml:"timeout"`
+		PoolSize      int           `yaml:"pool_size"`
+		RetryInterval time.Duration `yaml:"retry_interval"`
+	}
+
+	// Warmup represents the warm-up of the caches. When enabled, Names and the Top most requested
+	// Pokemons recorded in HistoryPath are warmed up on start, the service is ready once
+	// ReadyThreshold of them are processed or Timeout elapsed. Concurrency and Rate, in names per
+	// second, also apply to warm-ups triggered through the admin API
+	Warmup struct {
+		Enabled         bool          `yaml:"enabled"`
+		Names           []string      `yaml:"names"`
+		Top             int           `yaml:"top"`
+		HistoryPath     string        `yaml:"history_path"`
+		HistoryInterval time.Duration `yaml:"history_interval"`
+		Concurrency     int           `yaml:"concurrency"`
+		Rate            float64       `yaml:"rate"`
+		Timeout         time.Duration `yaml:"timeout"`
+		ReadyThreshold  float64       `yaml:"ready_threshold"`
+	}
+
+	// Admin represents the admin API, served on its own port and requiring a bearer token. As for
+	// API secrets the token is either given directly or read from a mounted file
+	Admin struct {
+		Enabled   bool   `yaml:"enabled"`
+		Port      string `yaml:"port"`
+		Token     string `yaml:"token,omitempty" secret:"true"`
+		TokenFile string `yaml:"token_file,omitempty"`
+	}
+
+	// TranslationBudget represents the hourly and daily Funtranslations budgets, 0 leaves one
+	// unlimited. Reserve is the share of each budget left unused. Usage is counted in the RESP cache
+	// backend, which is required, so the replicas share it
+	TranslationBudget struct {
+		Enabled bool    `yaml:"enabled"`
+		Hourly  int     `yaml:"hourly"`
+		Daily   int     `yaml:"daily"`
+		Reserve float64 `yaml:"reserve"`
+	}
+
+	// TranslationBreaker represents the circuit breaker in front of Funtranslations, it opens after
+	// Failures consecutive failed translations and lets one through again after Cooldown
+	TranslationBreaker struct {
+		Enabled  bool          `yaml:"enabled"`
+		Failures int           `yaml:"failures"`
+		Cooldown time.Duration `yaml:"cooldown"`
+	}
+
+	// Jobs represents the asynchronous translations requested with ?async. Jobs are persisted in the
+	// database at Path and translated by Workers, jobs are rejected while MaxPending are queued. A
+	// rate limited translation is attempted MaxAttempts times RetryDelay apart. Completed jobs are
+	// kept for Retention, callbacks are only sent to CallbackHosts
+	Jobs struct {
+		Enabled         bool          `yaml:"enabled"`
+		Path            string        `yaml:"path"`
+		Workers         int           `yaml:"workers"`
+		MaxPending      int           `yaml:"max_pending"`
+		MaxAttempts     int           `yaml:"max_attempts"`
+		RetryDelay      time.Duration `yaml:"retry_delay"`
+		Retention       time.Duration `yaml:"retention"`
+		CallbackHosts   []string      `yaml:"callback_hosts"`
+		CallbackTimeout time.Duration `yaml:"callback_timeout"`
+	}
+
+	// Health represents the readiness checks of the upstream APIs, each is bounded by Timeout and
+	// its result reused for TTL so probes do not load the APIs
+	Health struct {
+		Timeout time.Duration `yaml:"timeout"`
+		TTL     time.Duration `yaml:"ttl"`
+	}
+
+	// API variables for an external API. The API secret is either given directly, usually through
+	// an environment variable, or read from a mounted file which may be rotated at runtime.
+	// MaxResponseSize limits response bodies in bytes, Strict rejects responses whose schema changed: a
+	// field with an unexpected type or a required field missing. Unknown fields are ignored
 	API struct {
-		Url string `yaml:"url"`
+		Url             string        `yaml:"url"`
+		Timeout         time.Duration `yaml:"timeout"`
+		MaxResponseSize int64         `yaml:"max_response_size"`
+		Strict          bool          `yaml:"strict"`
+		APISecret       string        `yaml:"api_secret,omitempty" secret:"true"`
+		APISecretFile   string        `yaml:"api_secret_file,omitempty"`
 	}
 )
 
-// Load loads the configuration for the application
-func Load() (*Config, error) {
-	config := &Config{}
+// Default returns the configuration used for any value not set by a file, environment variable or flag
+func Default() *Config {
+	return &Config{
+		Service: Service{
+			Port:     "5050",
+			LogLevel: "info",
+			CacheControl: CacheControl{
+				Pokemon:           24 * time.Hour,
+				PokemonTranslated: time.Hour,
+			},
+		},
+		ThirdParty: ThirdParty{
+			Funtranslations: API{
+				Url:             "https://api.funtranslations.com",
+				Timeout:         10 * time.Second,
+				MaxResponseSize: 64 << 10,
+			},
+			PokeAPI: API{
+				Url:             "https://pokeapi.co",
+				Timeout:         10 * time.Second,
+				MaxResponseSize: 1 << 20,
+			},
+		},
+		Snapshot: Snapshot{
+			Fallback: true,
+		},
+		Store: Store{
+			Path: "pokedex.db",
+		},
+		GraphQL: GraphQL{
+			MaxDepth:      10,
+			MaxComplexity: 500,
+		},
+		GRPC: GRPC{
+			Port: "5051",
+		},
+		Cache: Cache{
+			Enabled: true,
+			Backend: CacheBackendMemory,
+			RESP: RESP{
+				Address:       "localhost:6379",
+				KeyPrefix:     "pokedex",
+				DialTimeout:   time.Second,
+				Timeout:       500 * time.Millisecond,
+				PoolSize:      10,
+				RetryInterval: 30 * time.Second,
+			},
+			Pokemon: CacheEntry{
+				TTL:        24 * time.Hour,
+				MaxEntries: 10000,
+			},
+			Translation: CacheEntry{
+				TTL:        24 * time.Hour,
+				MaxEntries: 10000,
+			},
+			TrackedNames: 10000,
+			Warmup: Warmup{
+				HistoryInterval: time.Minute,
+				Concurrency:     4,
+				Rate:            10,
+				Timeout:         5 * time.Minute,
+				ReadyThreshold:  0.9,
+			},
+		},
+		Admin: Admin{
+			Port: "5052",
+		},
+		Health: Health{
+			Timeout: 2 * time.Second,
+			TTL:     10 * time.Second,
+		},
+		TranslationBudget: TranslationBudget{
+			Hourly:  5,
+			Daily:   60,
+			Reserve: 0.1,
+		},
+		TranslationBreaker: TranslationBreaker{
+			Enabled:  true,
+			Failures: 5,
+			Cooldown: 30 * time.Second,
+		},
+		Jobs: Jobs{
+			Path:            "jobs.db",
+			Workers:         2,
+			MaxPending:      1000,
+			MaxAttempts:     4,
+			RetryDelay:      15 * time.Minute,
+			Retention:       24 * time.Hour,
+			CallbackTimeout: 5 * time.Second,
+		},
+	}
+}
 
-	file, err := os.Open(defaultConfigPath)
+// Load loads the configuration for the application from the command line arguments and the
+// environment, see Loader for details
+func Load(args []string) (*Config, error) {
+	loader, err := NewLoader(args, os.LookupEnv)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	d := yaml.NewDecoder(file)
-	if err := d.Decode(&config); err != nil {
-		return nil, err
+	return loader.Load()
+}
+
+// Redacted returns a copy of the configuration with every field tagged secret:"true" masked
+func (c *Config) Redacted() *Config {
+	cp := *c
+
+	for _, f := range fields(&cp) {
+		if f.secret && f.value.Kind() == reflect.String && f.value.String() != "" {
+			f.value.SetString(redacted)
+		}
+	}
+
+	return &cp
+}
+
+// Print writes the configuration as yaml with secrets redacted
+func (c *Config) Print(w io.Writer) error {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err
 	}
 
-	return config, nil
+	_, err = w.Write(data)
+
+	return err
 }
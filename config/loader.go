@@ -0,0 +1,244 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LookupEnv returns the value of an environment variable and whether it was set
+type LookupEnv func(key string) (string, bool)
+
+// Loader layers the configuration as defaults, then files, then POKEDEX_* environment variables and
+// finally command line flags. Files are given with repeatable --config flags, or the comma separated
+// POKEDEX_CONFIG variable, and are applied in order; config.yaml in the working directory is used
+// when none is given. Every field can be overridden by an environment variable named after its yaml
+// path, e.g. POKEDEX_THIRD_PARTY_POKEAPI_URL, or a flag, e.g. --third_party.pokeapi.url
+type Loader struct {
+	// Files are the configuration files applied in order
+	Files []string
+	// PrintConfig is set when the configuration should be printed instead of starting the service
+	PrintConfig bool
+
+	lookupEnv LookupEnv
+	flags     map[string]string
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+
+	return nil
+}
+
+// NewLoader parses the command line arguments and creates a new Loader
+func NewLoader(args []string, lookupEnv LookupEnv) (*Loader, error) {
+	l := &Loader{
+		lookupEnv: lookupEnv,
+		flags:     map[string]string{},
+	}
+
+	fs := flag.NewFlagSet("pokedex", flag.ContinueOnError)
+
+	var files stringList
+	fs.Var(&files, "config", "path to a configuration file, can be repeated to layer files")
+	fs.BoolVar(&l.PrintConfig, "print-config", false, "print the resolved configuration with secrets redacted and exit")
+
+	values := map[string]*string{}
+	for _, f := range fields(Default()) {
+		values[f.flagName()] = fs.String(f.flagName(), "", "overrides "+f.envName())
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		if v, ok := values[fl.Name]; ok {
+			l.flags[fl.Name] = *v
+		}
+	})
+
+	l.Files = files
+	if len(l.Files) == 0 {
+		if v, ok := lookupEnv(envPrefix + "_CONFIG"); ok && v != "" {
+			l.Files = strings.Split(v, ",")
+		}
+	}
+
+	return l, nil
+}
+
+// Load resolves and validates the configuration, it can be called again to pick up changes
+func (l *Loader) Load() (*Config, error) {
+	cfg := Default()
+
+	for _, path := range l.files() {
+		if err := applyFile(cfg, path); err != nil {
+			return nil, errors.Wrapf(err, "failed to apply config file %s", path)
+		}
+	}
+
+	for _, f := range fields(cfg) {
+		if v, ok := l.lookupEnv(f.envName()); ok {
+			if err := f.set(v); err != nil {
+				return nil, errors.Wrapf(err, "invalid value for %s", f.envName())
+			}
+		}
+	}
+
+	for _, f := range fields(cfg) {
+		if v, ok := l.flags[f.flagName()]; ok {
+			if err := f.set(v); err != nil {
+				return nil, errors.Wrapf(err, "invalid value for --%s", f.flagName())
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// files returns the configuration files to apply, falling back to config.yaml when present
+func (l *Loader) files() []string {
+	if len(l.Files) > 0 {
+		return l.Files
+	}
+
+	if _, err := os.Stat(defaultConfigPath); err == nil {
+		return []string{defaultConfigPath}
+	}
+
+	return nil
+}
+
+func applyFile(cfg *Config, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return yaml.UnmarshalStrict(data, cfg)
+}
+
+// field is a leaf configuration value addressed by its yaml path
+type field struct {
+	path   []string
+	value  reflect.Value
+	secret bool
+}
+
+func (f field) envName() string {
+	return envPrefix + "_" + strings.ToUpper(strings.Join(f.path, "_"))
+}
+
+func (f field) flagName() string {
+	return strings.Join(f.path, ".")
+}
+
+func (f field) set(raw string) error {
+	if f.value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		f.value.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		f.value.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		f.value.SetInt(i)
+	case reflect.Float64:
+		fl, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+
+		f.value.SetFloat(fl)
+	case reflect.Slice:
+		if f.value.Type().Elem().Kind() != reflect.String {
+			return errors.Errorf("unsupported type %s", f.value.Type())
+		}
+
+		var items []string
+		if raw != "" {
+			items = strings.Split(raw, ",")
+		}
+
+		f.value.Set(reflect.ValueOf(items))
+	default:
+		return errors.Errorf("unsupported type %s", f.value.Type())
+	}
+
+	return nil
+}
+
+// fields returns every leaf value of the configuration, nested structs are walked using their yaml names
+func fields(cfg *Config) []field {
+	return walk(reflect.ValueOf(cfg).Elem(), nil)
+}
+
+func walk(v reflect.Value, path []string) []field {
+	var out []field
+
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+
+		name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		p := append(append([]string{}, path...), name)
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct && fv.Type() != durationType {
+			out = append(out, walk(fv, p)...)
+
+			continue
+		}
+
+		out = append(out, field{
+			path:   p,
+			value:  fv,
+			secret: sf.Tag.Get("secret") == "true",
+		})
+	}
+
+	return out
+}
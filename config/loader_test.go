@@ -0,0 +1,256 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func env(vars map[string]string) LookupEnv {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+
+		return v, ok
+	}
+}
+
+func TestLoad_Success(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		args      func(t *testing.T) []string
+		env       map[string]string
+		checkConf func(t *testing.T, cfg *Config)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsDefaultsWhenNothingSet": {
+			args: func(t *testing.T) []string { return nil },
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, Default(), cfg)
+			},
+		},
+		"LayersFilesInOrder": {
+			args: func(t *testing.T) []string {
+				return []string{
+					"--config", writeFile(t, "service:\n  port: \"6000\"\nthird_party:\n  pokeapi:\n    url: http://first\n"),
+					"--config", writeFile(t, "third_party:\n  pokeapi:\n    url: http://second\n"),
+				}
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "6000", cfg.Service.Port)
+				require.Equal(t, "http://second", cfg.ThirdParty.PokeAPI.Url)
+				require.Equal(t, Default().ThirdParty.PokeAPI.Timeout, cfg.ThirdParty.PokeAPI.Timeout)
+			},
+		},
+		"EnvOverridesFiles": {
+			args: func(t *testing.T) []string {
+				return []string{"--config", writeFile(t, "service:\n  port: \"6000\"\n")}
+			},
+			env: map[string]string{
+				"POKEDEX_SERVICE_PORT":                          "7000",
+				"POKEDEX_SERVICE_CACHE_CONTROL_POKEMON":         "5m",
+				"POKEDEX_THIRD_PARTY_FUNTRANSLATIONS_TIMEOUT":   "3s",
+				"POKEDEX_THIRD_PARTY_FUNTRANSLATIONS_URL":       "http://translations",
+				"POKEDEX_SERVICE_CACHE_CONTROL_POKEMON_UNKNOWN": "ignored",
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "7000", cfg.Service.Port)
+				require.Equal(t, 5*time.Minute, cfg.Service.CacheControl.Pokemon)
+				require.Equal(t, 3*time.Second, cfg.ThirdParty.Funtranslations.Timeout)
+				require.Equal(t, "http://translations", cfg.ThirdParty.Funtranslations.Url)
+			},
+		},
+		"FlagsOverrideEnv": {
+			args: func(t *testing.T) []string {
+				return []string{"--service.port", "8000"}
+			},
+			env: map[string]string{
+				"POKEDEX_SERVICE_PORT": "7000",
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "8000", cfg.Service.Port)
+			},
+		},
+		"ReadsFilesFromEnvWhenNoConfigFlag": {
+			args: func(t *testing.T) []string { return nil },
+			env: map[string]string{
+				"POKEDEX_CONFIG": writeFile(t, "service:\n  port: \"6000\"\n"),
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "6000", cfg.Service.Port)
+			},
+		},
+		"ReadsWarmupNamesAsList": {
+			args: func(t *testing.T) []string { return nil },
+			env: map[string]string{
+				"POKEDEX_CACHE_WARMUP_ENABLED":         "true",
+				"POKEDEX_CACHE_WARMUP_NAMES":           "mewtwo,ditto",
+				"POKEDEX_CACHE_WARMUP_READY_THRESHOLD": "0.5",
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, []string{"mewtwo", "ditto"}, cfg.Cache.Warmup.Names)
+				require.Equal(t, 0.5, cfg.Cache.Warmup.ReadyThreshold)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			l, err := NewLoader(tc.args(t), env(tc.env))
+			require.NoError(t, err)
+
+			cfg, err := l.Load()
+			require.NoError(t, err)
+
+			tc.checkConf(t, cfg)
+		})
+	}
+}
+
+func TestLoad_Error(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		args func(t *testing.T) []string
+		env  map[string]string
+		err  string
+	}
+
+	tests := map[string]testcase{
+		"ReturnsAggregatedValidationErrors": {
+			args: func(t *testing.T) []string {
+				return []string{"--service.port", "0", "--third_party.pokeapi.url", "pokeapi.co"}
+			},
+			env: map[string]string{
+				"POKEDEX_THIRD_PARTY_FUNTRANSLATIONS_TIMEOUT": "0s",
+			},
+			err: "invalid configuration: service.port must be a number between 1 and 65535; " +
+				"third_party.funtranslations.timeout must be positive; " +
+				"third_party.pokeapi.url must be an absolute http(s) url",
+		},
+		"ReturnsErrorWhenAdminMisconfigured": {
+			args: func(t *testing.T) []string {
+				return []string{"--admin.enabled", "true", "--admin.port", "5050", "--cache.enabled", "false"}
+			},
+			err: "invalid configuration: admin.port must differ from service.port and grpc.port; " +
+				"admin.token or admin.token_file is required when the admin API is enabled; " +
+				"cache.enabled is required when the admin API is enabled",
+		},
+		"ReturnsErrorWhenWarmupMisconfigured": {
+			args: func(t *testing.T) []string {
+				return []string{"--cache.warmup.enabled", "true", "--cache.warmup.top", "100", "--cache.warmup.ready_threshold", "2"}
+			},
+			err: "invalid configuration: cache.warmup.ready_threshold must be between 0 and 1; " +
+				"cache.warmup.history_path is required to warm up the most requested pokemons",
+		},
+		"ReturnsErrorWhenRESPMisconfigured": {
+			args: func(t *testing.T) []string {
+				return []string{"--cache.backend", "resp", "--cache.resp.address", "localhost", "--cache.resp.pool_size", "0"}
+			},
+			err: "invalid configuration: cache.resp.address must be a host:port; " +
+				"cache.resp.pool_size must be positive",
+		},
+		"ReturnsErrorWhenCacheBackendUnknown": {
+			args: func(t *testing.T) []string { return nil },
+			env: map[string]string{
+				"POKEDEX_CACHE_BACKEND": "memcached",
+			},
+			err: "invalid configuration: cache.backend must be memory or resp",
+		},
+		"ReturnsErrorWhenTranslationBudgetMisconfigured": {
+			args: func(t *testing.T) []string {
+				return []string{"--translation_budget.enabled", "true", "--translation_budget.hourly", "0", "--translation_budget.daily", "0", "--translation_budget.reserve", "1"}
+			},
+			err: "invalid configuration: translation_budget.reserve must be at least 0 and less than 1; " +
+				"translation_budget.hourly or translation_budget.daily is required when the budget is enabled; " +
+				"translation_budget requires cache.backend resp so the replicas share it",
+		},
+		"ReturnsErrorWhenTranslationBreakerMisconfigured": {
+			args: func(t *testing.T) []string {
+				return []string{"--translation_breaker.failures", "0", "--translation_breaker.cooldown", "0s"}
+			},
+			err: "invalid configuration: translation_breaker.failures must be positive; translation_breaker.cooldown must be positive",
+		},
+		"ReturnsErrorWhenJobsMisconfigured": {
+			args: func(t *testing.T) []string {
+				return []string{"--store.enabled", "true", "--jobs.enabled", "true", "--jobs.path", "pokedex.db", "--jobs.workers", "0", "--jobs.max_pending", "0"}
+			},
+			env: map[string]string{
+				"POKEDEX_JOBS_CALLBACK_HOSTS": "hooks.example.com,https://example.com",
+			},
+			err: "invalid configuration: jobs.path must differ from store.path; jobs.workers must be positive; jobs.max_pending must be positive; " +
+				`jobs.callback_hosts must be host names without scheme or port, got "https://example.com"`,
+		},
+		"ReturnsErrorWhenEnvValueInvalid": {
+			args: func(t *testing.T) []string { return nil },
+			env: map[string]string{
+				"POKEDEX_THIRD_PARTY_POKEAPI_TIMEOUT": "ten seconds",
+			},
+			err: `invalid value for POKEDEX_THIRD_PARTY_POKEAPI_TIMEOUT: time: invalid duration "ten seconds"`,
+		},
+		"ReturnsErrorWhenFileHasUnknownField": {
+			args: func(t *testing.T) []string {
+				return []string{"--config", writeFile(t, "service:\n  prot: \"6000\"\n")}
+			},
+			err: "field prot not found",
+		},
+		"ReturnsErrorWhenFileMissing": {
+			args: func(t *testing.T) []string {
+				return []string{"--config", "does-not-exist.yaml"}
+			},
+			err: "no such file or directory",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			l, err := NewLoader(tc.args(t), env(tc.env))
+			require.NoError(t, err)
+
+			cfg, err := l.Load()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+			require.Nil(t, cfg)
+		})
+	}
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	t.Parallel()
+
+	cfg := Default()
+	cfg.ThirdParty.Funtranslations.APISecret = "s3cr3t"
+
+	redacted := cfg.Redacted()
+
+	require.Equal(t, "[REDACTED]", redacted.ThirdParty.Funtranslations.APISecret)
+	require.Equal(t, "", redacted.ThirdParty.PokeAPI.APISecret)
+	require.Equal(t, "s3cr3t", cfg.ThirdParty.Funtranslations.APISecret)
+
+	cfg.Admin.Token = "t0k3n"
+	require.Equal(t, "[REDACTED]", cfg.Redacted().Admin.Token)
+
+	cfg.Cache.RESP.Password = "p4ss"
+	require.Equal(t, "[REDACTED]", cfg.Redacted().Cache.RESP.Password)
+}
@@ -0,0 +1,329 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// ValidationError aggregates every problem found in a configuration
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e, "; ")
+}
+
+// Validate checks the configuration, all problems are reported at once
+func (c *Config) Validate() error {
+	var errs ValidationError
+
+	if port, err := strconv.Atoi(c.Service.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, "service.port must be a number between 1 and 65535")
+	}
+
+	if _, err := zerolog.ParseLevel(c.Service.LogLevel); err != nil || c.Service.LogLevel == "" {
+		errs = append(errs, "service.log_level must be one of trace, debug, info, warn, error, fatal, panic or disabled")
+	}
+
+	if c.Service.CacheControl.Pokemon < 0 {
+		errs = append(errs, "service.cache_control.pokemon must not be negative")
+	}
+
+	if c.Service.CacheControl.PokemonTranslated < 0 {
+		errs = append(errs, "service.cache_control.pokemon_translated must not be negative")
+	}
+
+	errs = append(errs, c.ThirdParty.Funtranslations.validate("third_party.funtranslations")...)
+	errs = append(errs, c.ThirdParty.PokeAPI.validate("third_party.pokeapi")...)
+
+	if c.Snapshot.Enabled && c.Snapshot.Path == "" {
+		errs = append(errs, "snapshot.path is required when the snapshot is enabled")
+	}
+
+	if c.Store.Enabled && c.Store.Path == "" {
+		errs = append(errs, "store.path is required when the store is enabled")
+	}
+
+	if c.GraphQL.MaxDepth < 1 {
+		errs = append(errs, "graphql.max_depth must be positive")
+	}
+
+	if c.GraphQL.MaxComplexity < 1 {
+		errs = append(errs, "graphql.max_complexity must be positive")
+	}
+
+	if c.GRPC.Enabled {
+		if port, err := strconv.Atoi(c.GRPC.Port); err != nil || port < 1 || port > 65535 {
+			errs = append(errs, "grpc.port must be a number between 1 and 65535")
+		} else if c.GRPC.Port == c.Service.Port {
+			errs = append(errs, "grpc.port must differ from service.port")
+		}
+	}
+
+	switch c.Cache.Backend {
+	case CacheBackendMemory:
+	case CacheBackendRESP:
+		errs = append(errs, c.Cache.RESP.validate()...)
+	default:
+		errs = append(errs, "cache.backend must be "+CacheBackendMemory+" or "+CacheBackendRESP)
+	}
+
+	errs = append(errs, c.Cache.Pokemon.validate("cache.pokemon")...)
+	errs = append(errs, c.Cache.Translation.validate("cache.translation")...)
+
+	if c.Cache.TrackedNames < 2 {
+		errs = append(errs, "cache.tracked_names must be at least 2")
+	}
+
+	errs = append(errs, c.Cache.Warmup.validate(c.Cache)...)
+
+	if c.Health.Timeout <= 0 {
+		errs = append(errs, "health.timeout must be positive")
+	}
+
+	if c.Health.TTL < 0 {
+		errs = append(errs, "health.ttl must not be negative")
+	}
+
+	if c.Admin.Enabled {
+		errs = append(errs, c.Admin.validate(c)...)
+	}
+
+	errs = append(errs, c.TranslationBudget.validate(c)...)
+
+	if c.TranslationBreaker.Enabled {
+		errs = append(errs, c.TranslationBreaker.validate()...)
+	}
+
+	if c.Jobs.Enabled {
+		errs = append(errs, c.Jobs.validate(c)...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (a API) validate(name string) []string {
+	var errs []string
+
+	if u, err := url.Parse(a.Url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, name+".url must be an absolute http(s) url")
+	}
+
+	if a.Timeout <= 0 {
+		errs = append(errs, name+".timeout must be positive")
+	}
+
+	if a.MaxResponseSize <= 0 {
+		errs = append(errs, name+".max_response_size must be positive")
+	}
+
+	if a.APISecret != "" && a.APISecretFile != "" {
+		errs = append(errs, name+".api_secret and "+name+".api_secret_file are mutually exclusive")
+	}
+
+	return errs
+}
+
+func (e CacheEntry) validate(name string) []string {
+	var errs []string
+
+	if e.TTL <= 0 {
+		errs = append(errs, name+".ttl must be positive")
+	}
+
+	if e.MaxEntries < 1 {
+		errs = append(errs, name+".max_entries must be positive")
+	}
+
+	return errs
+}
+
+func (r RESP) validate() []string {
+	var errs []string
+
+	if _, _, err := net.SplitHostPort(r.Address); err != nil {
+		errs = append(errs, "cache.resp.address must be a host:port")
+	}
+
+	if r.DB < 0 {
+		errs = append(errs, "cache.resp.db must not be negative")
+	}
+
+	if r.DialTimeout <= 0 {
+		errs = append(errs, "cache.resp.dial_timeout must be positive")
+	}
+
+	if r.Timeout <= 0 {
+		errs = append(errs, "cache.resp.timeout must be positive")
+	}
+
+	if r.PoolSize < 1 {
+		errs = append(errs, "cache.resp.pool_size must be positive")
+	}
+
+	if r.RetryInterval <= 0 {
+		errs = append(errs, "cache.resp.retry_interval must be positive")
+	}
+
+	return errs
+}
+
+func (w Warmup) validate(cache Cache) []string {
+	var errs []string
+
+	if w.Concurrency < 1 {
+		errs = append(errs, "cache.warmup.concurrency must be positive")
+	}
+
+	if w.Rate < 0 {
+		errs = append(errs, "cache.warmup.rate must not be negative")
+	}
+
+	if w.Top < 0 {
+		errs = append(errs, "cache.warmup.top must not be negative")
+	}
+
+	if w.HistoryPath != "" && w.HistoryInterval <= 0 {
+		errs = append(errs, "cache.warmup.history_interval must be positive")
+	}
+
+	if !w.Enabled {
+		return errs
+	}
+
+	if w.Timeout <= 0 {
+		errs = append(errs, "cache.warmup.timeout must be positive")
+	}
+
+	if w.ReadyThreshold < 0 || w.ReadyThreshold > 1 {
+		errs = append(errs, "cache.warmup.ready_threshold must be between 0 and 1")
+	}
+
+	if w.Top > 0 && w.HistoryPath == "" {
+		errs = append(errs, "cache.warmup.history_path is required to warm up the most requested pokemons")
+	}
+
+	if len(w.Names) == 0 && w.Top == 0 {
+		errs = append(errs, "cache.warmup.names or cache.warmup.top is required when the warm-up is enabled")
+	}
+
+	if !cache.Enabled {
+		errs = append(errs, "cache.enabled is required when the warm-up is enabled")
+	}
+
+	return errs
+}
+
+func (b TranslationBudget) validate(c *Config) []string {
+	var errs []string
+
+	if b.Hourly < 0 {
+		errs = append(errs, "translation_budget.hourly must not be negative")
+	}
+
+	if b.Daily < 0 {
+		errs = append(errs, "translation_budget.daily must not be negative")
+	}
+
+	if b.Reserve < 0 || b.Reserve >= 1 {
+		errs = append(errs, "translation_budget.reserve must be at least 0 and less than 1")
+	}
+
+	if b.Enabled && b.Hourly == 0 && b.Daily == 0 {
+		errs = append(errs, "translation_budget.hourly or translation_budget.daily is required when the budget is enabled")
+	}
+
+	// counted in memory every replica would spend the whole budget
+	if b.Enabled && c.Cache.Backend != CacheBackendRESP {
+		errs = append(errs, "translation_budget requires cache.backend resp so the replicas share it")
+	}
+
+	return errs
+}
+
+func (b TranslationBreaker) validate() []string {
+	var errs []string
+
+	if b.Failures < 1 {
+		errs = append(errs, "translation_breaker.failures must be positive")
+	}
+
+	if b.Cooldown <= 0 {
+		errs = append(errs, "translation_breaker.cooldown must be positive")
+	}
+
+	return errs
+}
+
+func (j Jobs) validate(c *Config) []string {
+	var errs []string
+
+	if j.Path == "" {
+		errs = append(errs, "jobs.path is required when jobs are enabled")
+	} else if c.Store.Enabled && j.Path == c.Store.Path {
+		errs = append(errs, "jobs.path must differ from store.path")
+	}
+
+	if j.Workers < 1 {
+		errs = append(errs, "jobs.workers must be positive")
+	}
+
+	if j.MaxPending < 1 {
+		errs = append(errs, "jobs.max_pending must be positive")
+	}
+
+	if j.MaxAttempts < 1 {
+		errs = append(errs, "jobs.max_attempts must be positive")
+	}
+
+	if j.RetryDelay <= 0 {
+		errs = append(errs, "jobs.retry_delay must be positive")
+	}
+
+	if j.Retention <= 0 {
+		errs = append(errs, "jobs.retention must be positive")
+	}
+
+	if j.CallbackTimeout <= 0 {
+		errs = append(errs, "jobs.callback_timeout must be positive")
+	}
+
+	for _, host := range j.CallbackHosts {
+		if host == "" || strings.ContainsAny(host, ":/") {
+			errs = append(errs, "jobs.callback_hosts must be host names without scheme or port, got "+strconv.Quote(host))
+		}
+	}
+
+	return errs
+}
+
+func (a Admin) validate(c *Config) []string {
+	var errs []string
+
+	if port, err := strconv.Atoi(a.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, "admin.port must be a number between 1 and 65535")
+	} else if a.Port == c.Service.Port || (c.GRPC.Enabled && a.Port == c.GRPC.Port) {
+		errs = append(errs, "admin.port must differ from service.port and grpc.port")
+	}
+
+	if a.Token == "" && a.TokenFile == "" {
+		errs = append(errs, "admin.token or admin.token_file is required when the admin API is enabled")
+	}
+
+	if a.Token != "" && a.TokenFile != "" {
+		errs = append(errs, "admin.token and admin.token_file are mutually exclusive")
+	}
+
+	if !c.Cache.Enabled {
+		errs = append(errs, "cache.enabled is required when the admin API is enabled")
+	}
+
+	return errs
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+)
+
+// restartOnly lists the settings only applied on start, a reload keeps their current value
+var restartOnly = []restartOnlySetting{
+	restartOnlyField("service.port", func(cfg *Config) *string { return &cfg.Service.Port }),
+	restartOnlyField("snapshot", func(cfg *Config) *Snapshot { return &cfg.Snapshot }),
+	restartOnlyField("store", func(cfg *Config) *Store { return &cfg.Store }),
+	restartOnlyField("graphql", func(cfg *Config) *GraphQL { return &cfg.GraphQL }),
+	restartOnlyField("grpc", func(cfg *Config) *GRPC { return &cfg.GRPC }),
+	restartOnlyField("cache.enabled", func(cfg *Config) *bool { return &cfg.Cache.Enabled }),
+	restartOnlyField("cache.backend", func(cfg *Config) *string { return &cfg.Cache.Backend }),
+	restartOnlyField("cache.resp", func(cfg *Config) *RESP { return &cfg.Cache.RESP }),
+	restartOnlyField("cache.pokemon.max_entries", func(cfg *Config) *int { return &cfg.Cache.Pokemon.MaxEntries }),
+	restartOnlyField("cache.translation.max_entries", func(cfg *Config) *int { return &cfg.Cache.Translation.MaxEntries }),
+	restartOnlyField("cache.tracked_names", func(cfg *Config) *int { return &cfg.Cache.TrackedNames }),
+	restartOnlyField("cache.warmup.enabled", func(cfg *Config) *bool { return &cfg.Cache.Warmup.Enabled }),
+	restartOnlyField("cache.warmup.names", func(cfg *Config) *[]string { return &cfg.Cache.Warmup.Names }),
+	restartOnlyField("cache.warmup.top", func(cfg *Config) *int { return &cfg.Cache.Warmup.Top }),
+	restartOnlyField("cache.warmup.history_path", func(cfg *Config) *string { return &cfg.Cache.Warmup.HistoryPath }),
+	restartOnlyField("cache.warmup.history_interval", func(cfg *Config) *time.Duration { return &cfg.Cache.Warmup.HistoryInterval }),
+	restartOnlyField("cache.warmup.timeout", func(cfg *Config) *time.Duration { return &cfg.Cache.Warmup.Timeout }),
+	restartOnlyField("cache.warmup.ready_threshold", func(cfg *Config) *float64 { return &cfg.Cache.Warmup.ReadyThreshold }),
+	restartOnlyField("admin.enabled", func(cfg *Config) *bool { return &cfg.Admin.Enabled }),
+	restartOnlyField("admin.port", func(cfg *Config) *string { return &cfg.Admin.Port }),
+	restartOnlyField("health", func(cfg *Config) *Health { return &cfg.Health }),
+	restartOnlyField("translation_budget.enabled", func(cfg *Config) *bool { return &cfg.TranslationBudget.Enabled }),
+	restartOnlyField("translation_breaker", func(cfg *Config) *TranslationBreaker { return &cfg.TranslationBreaker }),
+	restartOnlyField("jobs", func(cfg *Config) *Jobs { return &cfg.Jobs }),
+}
+
+// restartOnlySetting is a setting, or a whole section, only applied on start
+type restartOnlySetting struct {
+	name string
+	// keep sets the value of old in cfg, it reports whether they differed
+	keep func(old *Config, cfg *Config) bool
+}
+
+// restartOnlyField returns the restartOnlySetting of the value returned by field
+func restartOnlyField[T any](name string, field func(cfg *Config) *T) restartOnlySetting {
+	return restartOnlySetting{
+		name: name,
+		keep: func(old *Config, cfg *Config) bool {
+			current, value := field(old), field(cfg)
+			if reflect.DeepEqual(*current, *value) {
+				return false
+			}
+
+			*value = *current
+
+			return true
+		},
+	}
+}
+
+// Watcher keeps the current configuration and reloads it when a configuration file changes or the
+// process receives SIGHUP. Settings only applied on start, e.g. ports, feature flags and database
+// paths, keep their current value until a restart. Invalid configurations are rejected and the
+// current one is kept
+type Watcher struct {
+	loader   *Loader
+	current  atomic.Value
+	interval time.Duration
+
+	mu          sync.Mutex
+	subscribers []func(cfg *Config)
+	modTimes    map[string]time.Time
+	reloadErr   error
+}
+
+// NewWatcher creates a new Watcher starting from the given configuration
+func NewWatcher(loader *Loader, cfg *Config) *Watcher {
+	w := &Watcher{
+		loader:   loader,
+		interval: defaultPollInterval,
+		modTimes: map[string]time.Time{},
+	}
+
+	w.current.Store(cfg)
+	w.changed()
+
+	return w
+}
+
+// Config returns the current configuration, it must not be modified
+func (w *Watcher) Config() *Config {
+	return w.current.Load().(*Config)
+}
+
+// Err returns why the last reload was rejected, nil once a reload succeeds
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.reloadErr
+}
+
+// OnReload registers fn to be called with the new configuration after each successful reload
+func (w *Watcher) OnReload(fn func(cfg *Config)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Run watches for changes until the context is done
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Info().Msg("received SIGHUP, reloading config")
+		case <-ticker.C:
+			if !w.changed() {
+				continue
+			}
+
+			log.Info().Msg("config file changed, reloading config")
+		}
+
+		if err := w.Reload(); err != nil {
+			log.Error().Err(err).Msg("rejected config reload, keeping current config")
+		}
+	}
+}
+
+// Reload loads and validates the configuration, then swaps in its reloadable sections
+func (w *Watcher) Reload() error {
+	cfg, err := w.loader.Load()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.reloadErr = err
+	if err != nil {
+		return err
+	}
+
+	old := w.Config()
+
+	// values are not logged, sections may hold secrets
+	for _, setting := range restartOnly {
+		if setting.keep(old, cfg) {
+			log.Warn().Str("setting", setting.name).Msg("setting can not be reloaded, restart to apply")
+		}
+	}
+
+	w.current.Store(cfg)
+
+	for _, fn := range w.subscribers {
+		fn(cfg)
+	}
+
+	log.Info().Msg("reloaded config")
+
+	return nil
+}
+
+// changed reports whether any configuration file was modified since the last check
+func (w *Watcher) changed() bool {
+	changed := false
+
+	for _, path := range w.loader.files() {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if prev, ok := w.modTimes[path]; !ok || !prev.Equal(info.ModTime()) {
+			w.modTimes[path] = info.ModTime()
+			changed = changed || ok
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		reloaded  string
+		checkErr  func(t *testing.T, err error)
+		checkConf func(t *testing.T, cfg *Config)
+	}
+
+	tests := map[string]testcase{
+		"SwapsReloadableSections": {
+			reloaded: "third_party:\n  pokeapi:\n    url: http://reloaded\n    timeout: 2s\nservice:\n  log_level: debug\n",
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "http://reloaded", cfg.ThirdParty.PokeAPI.Url)
+				require.Equal(t, 2*time.Second, cfg.ThirdParty.PokeAPI.Timeout)
+				require.Equal(t, "debug", cfg.Service.LogLevel)
+			},
+		},
+		"KeepsPortOnReload": {
+			reloaded: "service:\n  port: \"9999\"\n",
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "6000", cfg.Service.Port)
+			},
+		},
+		"KeepsRestartOnlySettingsOnReload": {
+			reloaded: "cache:\n  backend: resp\n  resp:\n    address: redis:6379\n    pool_size: 20\n  warmup:\n    enabled: true\n    names: [mewtwo]\n" +
+				"store:\n  path: reloaded.db\njobs:\n  path: reloaded-jobs.db\n  workers: 8\ngraphql:\n  max_depth: 3\n" +
+				"health:\n  timeout: 1s\ntranslation_breaker:\n  enabled: false\n",
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, CacheBackendMemory, cfg.Cache.Backend)
+				require.Equal(t, Default().Cache.RESP, cfg.Cache.RESP)
+				require.False(t, cfg.Cache.Warmup.Enabled)
+				require.Equal(t, Default().Store.Path, cfg.Store.Path)
+				require.Equal(t, Default().Jobs, cfg.Jobs)
+				require.Equal(t, Default().GraphQL, cfg.GraphQL)
+				require.Equal(t, Default().Health, cfg.Health)
+				require.True(t, cfg.TranslationBreaker.Enabled)
+			},
+		},
+		"AppliesReloadableSettingsOfRestartOnlySections": {
+			reloaded: "cache:\n  pokemon:\n    ttl: 1m\n  warmup:\n    concurrency: 2\ntranslation_budget:\n  hourly: 3\n",
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, time.Minute, cfg.Cache.Pokemon.TTL)
+				require.Equal(t, 2, cfg.Cache.Warmup.Concurrency)
+				require.Equal(t, 3, cfg.TranslationBudget.Hourly)
+			},
+		},
+		"KeepsCurrentConfigWhenReloadInvalid": {
+			reloaded: "third_party:\n  pokeapi:\n    url: not-a-url\n",
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+			},
+			checkConf: func(t *testing.T, cfg *Config) {
+				require.Equal(t, "http://initial", cfg.ThirdParty.PokeAPI.Url)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			path := writeFile(t, "service:\n  port: \"6000\"\nthird_party:\n  pokeapi:\n    url: http://initial\n")
+
+			l, err := NewLoader([]string{"--config", path}, env(nil))
+			require.NoError(t, err)
+
+			cfg, err := l.Load()
+			require.NoError(t, err)
+
+			w := NewWatcher(l, cfg)
+
+			var notified *Config
+			w.OnReload(func(cfg *Config) {
+				notified = cfg
+			})
+
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.reloaded), 0600))
+
+			err = w.Reload()
+			tc.checkErr(t, err)
+			tc.checkConf(t, w.Config())
+			require.Equal(t, err, w.Err())
+
+			if err == nil {
+				require.Equal(t, w.Config(), notified)
+			} else {
+				require.Nil(t, notified)
+			}
+		})
+	}
+}
+
+func TestWatcher_RunReloadsWhenFileChanges(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "third_party:\n  pokeapi:\n    url: http://initial\n")
+
+	l, err := NewLoader([]string{"--config", path}, env(nil))
+	require.NoError(t, err)
+
+	cfg, err := l.Load()
+	require.NoError(t, err)
+
+	w := NewWatcher(l, cfg)
+	w.interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go w.Run(ctx)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("third_party:\n  pokeapi:\n    url: http://changed\n"), 0600))
+	modified := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modified, modified))
+
+	require.Eventually(t, func() bool {
+		return w.Config().ThirdParty.PokeAPI.Url == "http://changed"
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,155 @@
+// Package cli looks up Pokemons for the pokedex command, through a running service or directly
+// through the upstream adapters, and prints the results
+package cli
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/client"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidParam = errors.New("invalid parameter")
+
+type (
+	// Source looks up a Pokemon by name, translation is nil unless translate is set
+	Source interface {
+		Lookup(ctx context.Context, name string, translate bool) (*pokeapi.Pokemon, *client.Translation, error)
+	}
+
+	// Result is the outcome of the lookup of a name
+	Result struct {
+		Name        string
+		Pokemon     *pokeapi.Pokemon
+		Translation *client.Translation
+		Duration    time.Duration
+		Err         error
+	}
+
+	serviceSource struct {
+		client *client.Client
+	}
+
+	directSource struct {
+		fetcher    pokeapi.PokemonFetcher
+		translator pokemon.DescriptionTranslator
+	}
+)
+
+// ServiceSource looks Pokemons up through a running pokedex service
+func ServiceSource(c *client.Client) Source {
+	return &serviceSource{client: c}
+}
+
+// DirectSource looks Pokemons up through the upstream adapters, the way the service does
+func DirectSource(fetcher pokeapi.PokemonFetcher, translator pokemon.DescriptionTranslator) Source {
+	return &directSource{fetcher: fetcher, translator: translator}
+}
+
+func (s *serviceSource) Lookup(ctx context.Context, name string, translate bool) (*pokeapi.Pokemon, *client.Translation, error) {
+	if !translate {
+		pok, err := s.client.GetPokemon(ctx, name)
+
+		return pok, nil, err
+	}
+
+	pok, err := s.client.GetTranslatedPokemon(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &pok.Pokemon, &pok.Translation, nil
+}
+
+func (s *directSource) Lookup(ctx context.Context, name string, translate bool) (*pokeapi.Pokemon, *client.Translation, error) {
+	pok, err := s.fetcher.FetchByName(ctx, name)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to fetch pokemon: %s", name)
+	}
+
+	if pok == nil {
+		return nil, nil, client.ErrPokemonNotFound
+	}
+
+	if !translate {
+		return pok, nil, nil
+	}
+
+	res := s.translator.TranslateDescription(ctx, pok)
+
+	translated := *pok
+	translated.Description = res.Description
+
+	return &translated, &client.Translation{
+		Style:          res.Translation.Style,
+		Succeeded:      res.Translation.Succeeded,
+		FallbackReason: res.Translation.FallbackReason,
+		Cached:         res.Translation.Cached,
+	}, nil
+}
+
+// Lookup looks the names up with at most concurrency lookups in flight, results are in the order
+// of the names and a failed lookup does not stop the others
+func Lookup(ctx context.Context, src Source, names []string, translate bool, concurrency int) ([]Result, error) {
+	if concurrency < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "concurrency")
+	}
+
+	results := make([]Result, len(names))
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(res *Result, name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			start := time.Now()
+
+			res.Name = name
+			res.Pokemon, res.Translation, res.Err = src.Lookup(ctx, name, translate)
+			res.Duration = time.Since(start)
+		}(&results[i], name)
+	}
+
+	wg.Wait()
+
+	return results, nil
+}
+
+// ReadNames reads one name per line, blank lines and lines starting with # are skipped. Names are
+// lower cased as PokeAPI names are
+func ReadNames(r io.Reader) ([]string, error) {
+	var names []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+
+		names = append(names, strings.ToLower(name))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read names")
+	}
+
+	return names, nil
+}
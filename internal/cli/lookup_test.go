@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"pokedex/internal/handler/mocks"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
+	"pokedex/pkg/client"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var mewtwo = &pokeapi.Pokemon{
+	Name:        "mewtwo",
+	Number:      150,
+	Description: "It was created by\na scientist.",
+	Habitat:     "rare",
+	Types:       []string{"psychic"},
+	IsLegendary: true,
+}
+
+type sourceFunc func(ctx context.Context, name string, translate bool) (*pokeapi.Pokemon, *client.Translation, error)
+
+func (fn sourceFunc) Lookup(ctx context.Context, name string, translate bool) (*pokeapi.Pokemon, *client.Translation, error) {
+	return fn(ctx, name, translate)
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight int32
+
+	src := sourceFunc(func(ctx context.Context, name string, translate bool) (*pokeapi.Pokemon, *client.Translation, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		if name == "missingno" {
+			return nil, nil, client.ErrPokemonNotFound
+		}
+
+		return &pokeapi.Pokemon{Name: name}, nil, nil
+	})
+
+	names := []string{"bulbasaur", "missingno", "ivysaur", "venusaur", "charmander"}
+
+	results, err := Lookup(context.Background(), src, names, false, 2)
+	require.NoError(t, err)
+	require.Len(t, results, len(names))
+
+	for i, res := range results {
+		require.Equal(t, names[i], res.Name)
+		require.NotZero(t, res.Duration)
+
+		if res.Name == "missingno" {
+			require.True(t, errors.Is(res.Err, client.ErrPokemonNotFound))
+			require.Nil(t, res.Pokemon)
+
+			continue
+		}
+
+		require.NoError(t, res.Err)
+		require.Equal(t, names[i], res.Pokemon.Name)
+	}
+
+	require.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+
+	_, err = Lookup(context.Background(), src, names, false, 0)
+	require.True(t, errors.Is(err, ErrInvalidParam))
+}
+
+func TestDirectSource(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		name                string
+		translate           bool
+		expect              func(f *mocks.MockPokemonFetcher, tr *mocks.MockDescriptionTranslator)
+		expectedPokemon     *pokeapi.Pokemon
+		expectedTranslation *client.Translation
+		err                 error
+	}
+
+	translated := *mewtwo
+	translated.Description = "Created by a scientist, it was."
+
+	tests := map[string]testcase{
+		"ReturnsPokemon": {
+			name: "mewtwo",
+			expect: func(f *mocks.MockPokemonFetcher, tr *mocks.MockDescriptionTranslator) {
+				f.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+			},
+			expectedPokemon: mewtwo,
+		},
+		"ReturnsTranslatedPokemon": {
+			name:      "mewtwo",
+			translate: true,
+			expect: func(f *mocks.MockPokemonFetcher, tr *mocks.MockDescriptionTranslator) {
+				f.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+				tr.EXPECT().TranslateDescription(gomock.Any(), mewtwo).Return(pokemon.TranslationResult{
+					Description: translated.Description,
+					Translation: pokemon.Translation{Style: pokemon.StyleYoda, Succeeded: true},
+				})
+			},
+			expectedPokemon:     &translated,
+			expectedTranslation: &client.Translation{Style: pokemon.StyleYoda, Succeeded: true},
+		},
+		"ReturnsNotFound": {
+			name: "missingno",
+			expect: func(f *mocks.MockPokemonFetcher, tr *mocks.MockDescriptionTranslator) {
+				f.EXPECT().FetchByName(gomock.Any(), "missingno").Return(nil, nil)
+			},
+			err: client.ErrPokemonNotFound,
+		},
+		"ReturnsUpstreamError": {
+			name:      "mewtwo",
+			translate: true,
+			expect: func(f *mocks.MockPokemonFetcher, tr *mocks.MockDescriptionTranslator) {
+				f.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrTimeout)
+			},
+			err: upstream.ErrTimeout,
+		},
+	}
+
+	for name, testCase := range tests {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fetcher := mocks.NewMockPokemonFetcher(ctrl)
+			translator := mocks.NewMockDescriptionTranslator(ctrl)
+			tc.expect(fetcher, translator)
+
+			pok, translation, err := DirectSource(fetcher, translator).Lookup(context.Background(), tc.name, tc.translate)
+			if tc.err != nil {
+				require.True(t, errors.Is(err, tc.err), err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPokemon, pok)
+			require.Equal(t, tc.expectedTranslation, translation)
+		})
+	}
+}
+
+func TestReadNames(t *testing.T) {
+	t.Parallel()
+
+	names, err := ReadNames(strings.NewReader("Mewtwo\n\n  # starters\nbulbasaur  \r\nmr-mime\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"mewtwo", "bulbasaur", "mr-mime"}, names)
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/client"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// Output formats
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
+// Formats lists the supported output formats
+var Formats = []string{FormatTable, FormatJSON, FormatYAML}
+
+// record is the json and yaml representation of a result, timing fields are only set when asked for
+type record struct {
+	Name        string              `json:"name"`
+	Pokemon     *pokeapi.Pokemon    `json:"pokemon,omitempty"`
+	Translation *client.Translation `json:"translation,omitempty"`
+	Error       string              `json:"error,omitempty"`
+	TookMS      *float64            `json:"took_ms,omitempty"`
+	Cache       string              `json:"cache,omitempty"`
+}
+
+// Write prints the results in the given format. With timing, the duration of every lookup and
+// whether its translation was cached are included
+func Write(w io.Writer, results []Result, format string, timing bool) error {
+	switch format {
+	case FormatTable:
+		return writeTable(w, results, timing)
+	case FormatJSON, FormatYAML:
+		return writeRecords(w, results, format, timing)
+	}
+
+	return errors.Wrapf(ErrInvalidParam, "format %q", format)
+}
+
+func writeRecords(w io.Writer, results []Result, format string, timing bool) error {
+	records := make([]record, 0, len(results))
+
+	for _, res := range results {
+		rec := record{
+			Name:        res.Name,
+			Pokemon:     res.Pokemon,
+			Translation: res.Translation,
+		}
+
+		if res.Err != nil {
+			rec.Error = errorMessage(res.Err)
+		}
+
+		if timing {
+			took := float64(res.Duration.Microseconds()) / 1000
+			rec.TookMS = &took
+			rec.Cache = cacheStatus(res)
+		}
+
+		records = append(records, rec)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode results")
+	}
+
+	if format == FormatYAML {
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return errors.Wrap(err, "failed to encode results")
+		}
+	} else {
+		data = append(data, '\n')
+	}
+
+	_, err = w.Write(data)
+
+	return errors.Wrap(err, "failed to write results")
+}
+
+func writeTable(w io.Writer, results []Result, timing bool) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	translated := false
+	for _, res := range results {
+		translated = translated || res.Translation != nil
+	}
+
+	header := []string{"NAME", "NUMBER", "HABITAT", "TYPES", "LEGENDARY", "DESCRIPTION"}
+	if translated {
+		header = append(header, "TRANSLATION")
+	}
+
+	if timing {
+		header = append(header, "TOOK", "CACHE")
+	}
+
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, res := range results {
+		var row []string
+
+		if res.Err != nil {
+			row = []string{res.Name, "-", "-", "-", "-", "error: " + errorMessage(res.Err)}
+		} else {
+			row = []string{
+				res.Pokemon.Name,
+				orDash(res.Pokemon.Number),
+				orDash(res.Pokemon.Habitat),
+				orDash(strings.Join(res.Pokemon.Types, ",")),
+				strconv.FormatBool(res.Pokemon.IsLegendary),
+				strings.Join(strings.Fields(res.Pokemon.Description), " "),
+			}
+		}
+
+		if translated {
+			row = append(row, translationStatus(res.Translation))
+		}
+
+		if timing {
+			row = append(row, res.Duration.Round(time.Millisecond).String(), cacheStatus(res))
+		}
+
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return errors.Wrap(tw.Flush(), "failed to write results")
+}
+
+// translationStatus describes how the description was translated, e.g. "yoda" or
+// "shakespeare (rate_limited)" when the original description was kept
+func translationStatus(t *client.Translation) string {
+	switch {
+	case t == nil:
+		return "-"
+	case !t.Succeeded && t.FallbackReason != "":
+		return fmt.Sprintf("%s (%s)", t.Style, t.FallbackReason)
+	}
+
+	return t.Style
+}
+
+// cacheStatus reports whether the translation was served from cache, only translations are cached
+func cacheStatus(res Result) string {
+	switch {
+	case res.Translation == nil:
+		return "-"
+	case res.Translation.Cached:
+		return "hit"
+	}
+
+	return "miss"
+}
+
+// errorMessage keeps the problem detail of service errors, other errors are printed in full
+func errorMessage(err error) string {
+	var respErr *client.ResponseError
+	if errors.As(err, &respErr) && respErr.Code != "" {
+		return fmt.Sprintf("%s (%s)", respErr.Detail, respErr.Code)
+	}
+
+	return err.Error()
+}
+
+func orDash(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || s == "0" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/client"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	results := []Result{
+		{
+			Name:        "mewtwo",
+			Pokemon:     mewtwo,
+			Translation: &client.Translation{Style: "yoda", Succeeded: true, Cached: true},
+			Duration:    1500 * time.Microsecond,
+		},
+		{
+			Name:        "ivysaur",
+			Pokemon:     &pokeapi.Pokemon{Name: "ivysaur", Description: "A bulb.", Habitat: "grassland"},
+			Translation: &client.Translation{Style: "shakespeare", FallbackReason: "rate_limited"},
+			Duration:    20 * time.Millisecond,
+		},
+		{
+			Name: "missingno",
+			Err: errors.Wrap(&client.ResponseError{
+				Problem:    client.Problem{Title: "Not Found", Status: http.StatusNotFound, Detail: "pokemon not found", Code: "pokemon_not_found"},
+				StatusCode: http.StatusNotFound,
+			}, "unable to get translated pokemon: missingno"),
+			Duration: time.Millisecond,
+		},
+	}
+
+	type testcase struct {
+		format string
+		timing bool
+		check  func(t *testing.T, out string)
+	}
+
+	tests := map[string]testcase{
+		"Table": {
+			format: FormatTable,
+			check: func(t *testing.T, out string) {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				require.Len(t, lines, 4)
+				require.Equal(t, []string{"NAME", "NUMBER", "HABITAT", "TYPES", "LEGENDARY", "DESCRIPTION", "TRANSLATION"}, strings.Fields(lines[0]))
+				require.Regexp(t, `^mewtwo\s+150\s+rare\s+psychic\s+true\s+It was created by a scientist\.\s+yoda$`, lines[1])
+				require.Regexp(t, `^ivysaur\s+-\s+grassland\s+-\s+false\s+A bulb\.\s+shakespeare \(rate_limited\)$`, lines[2])
+				require.Regexp(t, `^missingno\s+-\s+-\s+-\s+-\s+error: pokemon not found \(pokemon_not_found\)\s+-$`, lines[3])
+			},
+		},
+		"TableWithTiming": {
+			format: FormatTable,
+			timing: true,
+			check: func(t *testing.T, out string) {
+				lines := strings.Split(strings.TrimSpace(out), "\n")
+				require.Len(t, lines, 4)
+				require.Equal(t, []string{"TRANSLATION", "TOOK", "CACHE"}, strings.Fields(lines[0])[6:])
+				require.Regexp(t, `yoda\s+2ms\s+hit$`, lines[1])
+				require.Regexp(t, `\(rate_limited\)\s+20ms\s+miss$`, lines[2])
+				require.Regexp(t, `1ms\s+-$`, lines[3])
+			},
+		},
+		"JSON": {
+			format: FormatJSON,
+			check: func(t *testing.T, out string) {
+				var records []map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(out), &records))
+				require.Len(t, records, 3)
+				require.Equal(t, "mewtwo", records[0]["name"])
+				require.Equal(t, "It was created by\na scientist.", records[0]["pokemon"].(map[string]interface{})["Description"])
+				require.Equal(t, true, records[0]["translation"].(map[string]interface{})["cached"])
+				require.NotContains(t, records[0], "took_ms")
+				require.NotContains(t, records[0], "error")
+				require.Equal(t, "pokemon not found (pokemon_not_found)", records[2]["error"])
+				require.NotContains(t, records[2], "pokemon")
+			},
+		},
+		"JSONWithTiming": {
+			format: FormatJSON,
+			timing: true,
+			check: func(t *testing.T, out string) {
+				var records []map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(out), &records))
+				require.Equal(t, 1.5, records[0]["took_ms"])
+				require.Equal(t, "hit", records[0]["cache"])
+				require.Equal(t, "miss", records[1]["cache"])
+				require.Equal(t, "-", records[2]["cache"])
+			},
+		},
+		"YAML": {
+			format: FormatYAML,
+			check: func(t *testing.T, out string) {
+				var records []map[string]interface{}
+				require.NoError(t, yaml.Unmarshal([]byte(out), &records))
+				require.Len(t, records, 3)
+				require.Contains(t, out, "- name: mewtwo\n")
+				require.Contains(t, out, "    IsLegendary: true\n")
+				require.Equal(t, "pokemon not found (pokemon_not_found)", records[2]["error"])
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			require.NoError(t, Write(&out, results, tc.format, tc.timing))
+
+			tc.check(t, out.String())
+		})
+	}
+}
+
+func TestWrite_UntranslatedTable(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, []Result{{Name: "mewtwo", Pokemon: mewtwo}}, FormatTable, false))
+
+	header := strings.Fields(strings.SplitN(out.String(), "\n", 2)[0])
+	require.Equal(t, []string{"NAME", "NUMBER", "HABITAT", "TYPES", "LEGENDARY", "DESCRIPTION"}, header)
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	err := Write(&bytes.Buffer{}, nil, "xml", false)
+	require.True(t, errors.Is(err, ErrInvalidParam))
+}
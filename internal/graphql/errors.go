@@ -0,0 +1,75 @@
+package graphql
+
+import (
+	"pokedex/internal/handler"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const codeInternal = "internal_error"
+
+// clientError is a resolver error safe to expose to clients, its code is added to the error
+// extensions like the code of problem responses
+type clientError struct {
+	msg  string
+	code string
+}
+
+func (e clientError) Error() string { return e.msg }
+
+// Extensions returns the error extensions
+func (e clientError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// resolverError maps err to a client safe error using the same codes as the REST handlers, other
+// errors are logged and replaced by a generic error
+func resolverError(err error) error {
+	if sentinel, ok := handler.Sentinel(err); ok {
+		return clientError{msg: sentinel.Error(), code: sentinel.Code()}
+	}
+
+	var coded clientError
+	if errors.As(err, &coded) {
+		return coded
+	}
+
+	log.Error().Err(err).Str("type", "graphql").Send()
+
+	return clientError{msg: "an internal error occurred", code: codeInternal}
+}
+
+// withCodes adds the code of client errors missing from the formatted errors, errors returned by
+// thunks are formatted before being located and lose their extensions
+func withCodes(errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i, err := range errs {
+		if err.Extensions != nil {
+			continue
+		}
+
+		if coded, ok := originalClientError(err); ok {
+			errs[i].Extensions = coded.Extensions()
+		}
+	}
+
+	return errs
+}
+
+func originalClientError(err error) (clientError, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case clientError:
+			return e, true
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return clientError{}, false
+		}
+	}
+
+	return clientError{}, false
+}
@@ -0,0 +1,419 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"pokedex/internal/handler/mocks"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type gqlResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+var (
+	mewtwo = &pokeapi.Pokemon{
+		Name:        "mewtwo",
+		Number:      150,
+		Description: "It was created by a scientist.",
+		Habitat:     "rare",
+		Types:       []string{"psychic"},
+		Stats:       map[string]int{"hp": 106, "attack": 110},
+		IsLegendary: true,
+	}
+	ivysaur = &pokeapi.Pokemon{
+		Name:        "ivysaur",
+		Number:      2,
+		Description: "When the bulb on its back grows large.",
+		EvolvesFrom: "bulbasaur",
+	}
+	bulbasaur = &pokeapi.Pokemon{
+		Name:        "bulbasaur",
+		Number:      1,
+		Description: "A strange seed was planted on its back at birth.",
+	}
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		req           func(t *testing.T) *http.Request
+		opts          Options
+		fetcher       func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher
+		translator    func(t *testing.T, c *gomock.Controller) *mocks.MockDescriptionTranslator
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsSelectedFields": {
+			req: post(`{ pokemon(name: "mewtwo") { name number types isLegendary stats { name baseStat } } }`, nil),
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				require.Empty(t, res.Errors)
+				require.Equal(t, map[string]interface{}{
+					"pokemon": map[string]interface{}{
+						"name":        "mewtwo",
+						"number":      float64(150),
+						"types":       []interface{}{"psychic"},
+						"isLegendary": true,
+						"stats": []interface{}{
+							map[string]interface{}{"name": "attack", "baseStat": float64(110)},
+							map[string]interface{}{"name": "hp", "baseStat": float64(106)},
+						},
+					},
+				}, res.Data)
+			},
+		},
+		"SupportsGetRequestsWithVariables": {
+			req: func(t *testing.T) *http.Request {
+				q := url.Values{}
+				q.Set("query", `query Get($name: String!) { pokemon(name: $name) { name } }`)
+				q.Set("variables", `{"name": "mewtwo"}`)
+
+				return httptest.NewRequest(http.MethodGet, "/graphql?"+q.Encode(), nil)
+			},
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				require.Equal(t, map[string]interface{}{"name": "mewtwo"}, res.Data["pokemon"])
+			},
+		},
+		"FetchesEachPokemonOncePerRequest": {
+			req: post(`{
+				pokemons(names: ["ivysaur", "bulbasaur", "ivysaur"]) { name evolvesFrom { name } }
+				pokemon(name: "bulbasaur") { number }
+			}`, nil),
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "ivysaur").Return(ivysaur, nil).Times(1)
+				m.EXPECT().FetchByName(gomock.Any(), "bulbasaur").Return(bulbasaur, nil).Times(1)
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				require.Empty(t, res.Errors)
+				require.Equal(t, []interface{}{
+					map[string]interface{}{"name": "ivysaur", "evolvesFrom": map[string]interface{}{"name": "bulbasaur"}},
+					map[string]interface{}{"name": "bulbasaur", "evolvesFrom": nil},
+					map[string]interface{}{"name": "ivysaur", "evolvesFrom": map[string]interface{}{"name": "bulbasaur"}},
+				}, res.Data["pokemons"])
+				require.Equal(t, map[string]interface{}{"number": float64(1)}, res.Data["pokemon"])
+			},
+		},
+		"FetchesABatchConcurrently": {
+			req: post(`{ pokemons(names: ["ivysaur", "bulbasaur"]) { name } }`, nil),
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				// both fetches must be in flight at once for either to return
+				started := make(chan struct{}, 2)
+				wait := func(pok *pokeapi.Pokemon) func(interface{}, string) (*pokeapi.Pokemon, error) {
+					return func(interface{}, string) (*pokeapi.Pokemon, error) {
+						started <- struct{}{}
+
+						deadline := time.After(time.Second)
+						for len(started) < 2 {
+							select {
+							case <-deadline:
+								return nil, upstream.ErrTimeout
+							case <-time.After(time.Millisecond):
+							}
+						}
+
+						return pok, nil
+					}
+				}
+
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "ivysaur").DoAndReturn(wait(ivysaur))
+				m.EXPECT().FetchByName(gomock.Any(), "bulbasaur").DoAndReturn(wait(bulbasaur))
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				require.Empty(t, res.Errors)
+			},
+		},
+		"TranslatesEachPokemonOncePerRequest": {
+			req: post(`{
+				a: pokemon(name: "mewtwo") { translation { description style succeeded fallbackReason } }
+				b: pokemon(name: "mewtwo") { translation { cached } }
+			}`, nil),
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+
+				return m
+			},
+			translator: func(t *testing.T, c *gomock.Controller) *mocks.MockDescriptionTranslator {
+				m := mocks.NewMockDescriptionTranslator(c)
+				m.EXPECT().TranslateDescription(gomock.Any(), mewtwo).Return(pokemon.TranslationResult{
+					Description: "Created by a scientist, it was.",
+					Translation: pokemon.Translation{Style: pokemon.StyleYoda, Succeeded: true},
+				}).Times(1)
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				require.Empty(t, res.Errors)
+				require.Equal(t, map[string]interface{}{
+					"description":    "Created by a scientist, it was.",
+					"style":          pokemon.StyleYoda,
+					"succeeded":      true,
+					"fallbackReason": nil,
+				}, res.Data["a"].(map[string]interface{})["translation"])
+			},
+		},
+		"ReturnsNullForUnknownPokemon": {
+			req: post(`{ pokemon(name: "missingno") { name } }`, nil),
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "missingno").Return(nil, nil)
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				require.Empty(t, res.Errors)
+				require.Nil(t, res.Data["pokemon"])
+			},
+		},
+		"MapsUpstreamErrorsToCodes": {
+			req: post(`{ pokemon(name: "mewtwo") { name } }`, nil),
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrRateLimited)
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				requireCode(t, res, "rate_limited")
+			},
+		},
+		"HidesInternalErrors": {
+			req: post(`{ pokemon(name: "mewtwo") { name } }`, nil),
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, &json.SyntaxError{})
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				requireCode(t, res, codeInternal)
+				require.Equal(t, "an internal error occurred", res.Errors[0].Message)
+			},
+		},
+		"RejectsInvalidNames": {
+			req: post(`{ pokemon(name: "Mew Two") { name } }`, nil),
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				requireCode(t, res, "invalid_name")
+			},
+		},
+		"RejectsTooManyNames": {
+			req: post(`query Get($names: [String!]!) { pokemons(names: $names) { name } }`, map[string]interface{}{
+				"names": strings.Split(strings.Repeat("a,", maxNames)+"a", ","),
+			}),
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				requireCode(t, res, "too_many_names")
+			},
+		},
+		"RejectsDeepQueries": {
+			req: post(`{ pokemon(name: "mewtwo") { ...Evolution } }
+				fragment Evolution on Pokemon { evolvesFrom { evolvesFrom { evolvesFrom { name } } } }`, nil),
+			opts: Options{Limits: Limits{MaxDepth: 4}},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusBadRequest)
+
+				requireCode(t, res, "query_too_deep")
+			},
+		},
+		"AcceptsQueriesAtTheMaxDepth": {
+			req:  post(`{ pokemon(name: "mewtwo") { evolvesFrom { evolvesFrom { name } } } }`, nil),
+			opts: Options{Limits: Limits{MaxDepth: 4}},
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				require.Empty(t, res.Errors)
+			},
+		},
+		"RejectsComplexQueries": {
+			req:  post(`{ pokemons(names: ["a", "b", "c"]) { name translation { description } } }`, nil),
+			opts: Options{Limits: Limits{MaxComplexity: 30}},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusBadRequest)
+
+				requireCode(t, res, "query_too_complex")
+				require.Equal(t, "query complexity 37 exceeds the maximum of 30", res.Errors[0].Message)
+			},
+		},
+		"IgnoresIntrospectionInLimits": {
+			req:  post(`{ __schema { types { name fields { name type { name ofType { name } } } } } }`, nil),
+			opts: Options{Limits: Limits{MaxDepth: 1, MaxComplexity: 1}},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusOK)
+
+				require.Empty(t, res.Errors)
+				require.NotNil(t, res.Data["__schema"])
+			},
+		},
+		"RejectsInvalidQueries": {
+			req: post(`{ pokemon(name: "mewtwo") { weight } }`, nil),
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusBadRequest)
+
+				require.Len(t, res.Errors, 1)
+			},
+		},
+		"RejectsUnparsableQueries": {
+			req: post(`{ pokemon(name: `, nil),
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusBadRequest)
+
+				require.Len(t, res.Errors, 1)
+			},
+		},
+		"RejectsMissingQueries": {
+			req: func(t *testing.T) *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{}`))
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusBadRequest)
+
+				requireCode(t, res, "bad_request")
+			},
+		},
+		"ServesGraphiQLToBrowsers": {
+			req: func(t *testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+				req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+				return req
+			},
+			opts: Options{GraphiQL: true},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+				require.Contains(t, rec.Body.String(), "graphiql")
+			},
+		},
+		"DoesNotServeGraphiQLUnlessEnabled": {
+			req: func(t *testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+				req.Header.Set("Accept", "text/html")
+
+				return req
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				res := decode(t, rec, http.StatusBadRequest)
+
+				requireCode(t, res, "bad_request")
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fetcher := mocks.NewMockPokemonFetcher(ctrl)
+			if tc.fetcher != nil {
+				fetcher = tc.fetcher(t, ctrl)
+			}
+
+			translator := mocks.NewMockDescriptionTranslator(ctrl)
+			if tc.translator != nil {
+				translator = tc.translator(t, ctrl)
+			}
+
+			h, err := NewHandler(fetcher, translator, tc.opts)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, tc.req(t))
+
+			tc.checkResponse(t, rec)
+		})
+	}
+}
+
+func post(query string, variables map[string]interface{}) func(t *testing.T) *http.Request {
+	return func(t *testing.T) *http.Request {
+		body, err := json.Marshal(request{Query: query, Variables: variables})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+
+		return req
+	}
+}
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder, status int) *gqlResponse {
+	t.Helper()
+
+	require.Equal(t, status, rec.Code, rec.Body.String())
+
+	res := &gqlResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), res))
+
+	return res
+}
+
+func requireCode(t *testing.T, res *gqlResponse, code string) {
+	t.Helper()
+
+	require.NotEmpty(t, res.Errors)
+	require.Equal(t, code, res.Errors[0].Extensions["code"])
+}
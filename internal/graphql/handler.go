@@ -0,0 +1,166 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/http/response"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/pkg/errors"
+)
+
+// maxBodySize limits the size of POST bodies
+const maxBodySize = 64 << 10
+
+type (
+	// Options configures the GraphQL handler
+	Options struct {
+		Limits Limits
+		// GraphiQL serves the GraphiQL IDE to browsers, meant for development only
+		GraphiQL bool
+	}
+
+	// request is a GraphQL request, sent as a JSON body or as query parameters
+	request struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+)
+
+// NewHandler returns the GraphQL endpoint, queries are given as query parameters on GET requests or
+// as a JSON body on POST requests. Every request gets its own loaders so a Pokemon or a translation
+// is fetched at most once per request
+func NewHandler(fetcher pokeapi.PokemonFetcher, translator pokemon.DescriptionTranslator, opts Options) (http.HandlerFunc, error) {
+	schema, err := newSchema(fetcher, translator)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build graphql schema")
+	}
+
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		if opts.GraphiQL && r.Method == http.MethodGet && r.URL.Query().Get("query") == "" && acceptsHTML(r) {
+			return graphiql
+		}
+
+		req, err := decodeRequest(w, r)
+		if err != nil {
+			return response.JSON(http.StatusBadRequest, failure(err))
+		}
+
+		return execute(r, schema, opts.Limits, req)
+	}), nil
+}
+
+// execute parses, validates and runs a request. Requests which cannot run are rejected with a 400,
+// resolver errors are returned alongside the data with a 200 as usual for GraphQL
+func execute(r *http.Request, schema graphql.Schema, limits Limits, req *request) response.Renderer {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return response.JSON(http.StatusBadRequest, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+	}
+
+	if res := graphql.ValidateDocument(&schema, doc, nil); !res.IsValid {
+		return response.JSON(http.StatusBadRequest, &graphql.Result{Errors: res.Errors})
+	}
+
+	if err := limits.check(doc, req.OperationName, req.Variables); err != nil {
+		return response.JSON(http.StatusBadRequest, failure(err))
+	}
+
+	res := graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(r.Context()),
+	})
+	res.Errors = withCodes(res.Errors)
+
+	return response.JSON(http.StatusOK, res)
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request) (*request, error) {
+	req := &request{}
+
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				return nil, clientError{msg: "variables must be a JSON object", code: "bad_request"}
+			}
+		}
+	case http.MethodPost:
+		body := http.MaxBytesReader(w, r.Body, maxBodySize)
+
+		if err := json.NewDecoder(body).Decode(req); err != nil && err != io.EOF {
+			return nil, clientError{msg: "body must be a JSON object with a query", code: "bad_request"}
+		}
+	default:
+		return nil, clientError{msg: "only GET and POST requests are supported", code: "bad_request"}
+	}
+
+	if req.Query == "" {
+		return nil, clientError{msg: "a query is required", code: "bad_request"}
+	}
+
+	return req, nil
+}
+
+// failure returns a result carrying a single error and no data
+func failure(err error) *graphql.Result {
+	formatted := gqlerrors.FormatError(err)
+
+	var coded clientError
+	if errors.As(err, &coded) {
+		formatted.Extensions = coded.Extensions()
+	}
+
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{formatted}}
+}
+
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// graphiql serves the GraphiQL IDE from a CDN, queries are sent back to the page URL
+var graphiql = response.RendererFunc(func(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	_, err := io.WriteString(w, graphiqlPage)
+
+	return err
+})
+
+const graphiqlPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Pokedex GraphiQL</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@1.4.7/graphiql.min.css" />
+</head>
+<body style="margin: 0;">
+  <div id="graphiql" style="height: 100vh;"></div>
+  <script crossorigin src="https://unpkg.com/react@17/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@17/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@1.4.7/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: window.location.pathname });
+    ReactDOM.render(React.createElement(GraphiQL, { fetcher: fetcher }), document.getElementById('graphiql'));
+  </script>
+</body>
+</html>
+`
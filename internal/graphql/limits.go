@@ -0,0 +1,188 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// translationCost is the complexity of a translation field, translations call a rate limited API
+	translationCost = 10
+)
+
+// Limits bound the queries accepted by the endpoint
+type Limits struct {
+	// MaxDepth is the maximum nesting of selections, introspection fields are not counted
+	MaxDepth int
+	// MaxComplexity is the maximum cost of a query. Every field costs 1, a translation costs 10 and
+	// the fields selected on a pokemons list are counted once per requested name
+	MaxComplexity int
+}
+
+type limitChecker struct {
+	fragments    map[string]*ast.FragmentDefinition
+	variables    map[string]interface{}
+	depths       map[string]int
+	complexities map[string]int
+}
+
+// check returns an error when the operation exceeds the limits, the document must be valid
+func (l Limits) check(doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	c := &limitChecker{
+		fragments:    map[string]*ast.FragmentDefinition{},
+		variables:    variables,
+		depths:       map[string]int{},
+		complexities: map[string]int{},
+	}
+
+	var operations []*ast.OperationDefinition
+
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.FragmentDefinition:
+			c.fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				operations = append(operations, d)
+			}
+		}
+	}
+
+	for _, op := range operations {
+		if depth := c.depth(op.SelectionSet); l.MaxDepth > 0 && depth > l.MaxDepth {
+			return clientError{
+				msg:  fmt.Sprintf("query depth %d exceeds the maximum of %d", depth, l.MaxDepth),
+				code: "query_too_deep",
+			}
+		}
+
+		if complexity := c.complexity(op.SelectionSet); l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+			return clientError{
+				msg:  fmt.Sprintf("query complexity %d exceeds the maximum of %d", complexity, l.MaxComplexity),
+				code: "query_too_complex",
+			}
+		}
+	}
+
+	return nil
+}
+
+// depth of a selection set, fragment depths are memoised so reused fragments are walked once
+func (c *limitChecker) depth(set *ast.SelectionSet) int {
+	max := 0
+
+	for _, sel := range selections(set) {
+		d := 0
+
+		switch s := sel.(type) {
+		case *ast.Field:
+			if introspection(s) {
+				continue
+			}
+
+			d = 1 + c.depth(s.SelectionSet)
+		case *ast.InlineFragment:
+			d = c.depth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			d = c.fragment(c.depths, s.Name.Value, c.depth)
+		}
+
+		if d > max {
+			max = d
+		}
+	}
+
+	return max
+}
+
+// complexity of a selection set, capped to avoid overflows
+func (c *limitChecker) complexity(set *ast.SelectionSet) int {
+	total := 0
+
+	for _, sel := range selections(set) {
+		cost := 0
+
+		switch s := sel.(type) {
+		case *ast.Field:
+			if introspection(s) {
+				continue
+			}
+
+			cost = 1
+			if s.Name.Value == "translation" {
+				cost = translationCost
+			}
+
+			cost = capped(cost + capped(c.complexity(s.SelectionSet)*c.multiplier(s)))
+		case *ast.InlineFragment:
+			cost = c.complexity(s.SelectionSet)
+		case *ast.FragmentSpread:
+			cost = c.fragment(c.complexities, s.Name.Value, c.complexity)
+		}
+
+		total = capped(total + cost)
+	}
+
+	return total
+}
+
+// fragment returns the memoised measure of a fragment, unknown fragments measure 0
+func (c *limitChecker) fragment(memo map[string]int, name string, measure func(set *ast.SelectionSet) int) int {
+	if v, ok := memo[name]; ok {
+		return v
+	}
+
+	v := 0
+	if frag, ok := c.fragments[name]; ok {
+		v = measure(frag.SelectionSet)
+	}
+
+	memo[name] = v
+
+	return v
+}
+
+// multiplier is the number of items a list field returns, the length of the names argument
+func (c *limitChecker) multiplier(f *ast.Field) int {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "names" {
+			continue
+		}
+
+		switch v := arg.Value.(type) {
+		case *ast.ListValue:
+			return len(v.Values)
+		case *ast.Variable:
+			if names, ok := c.variables[v.Name.Value].([]interface{}); ok {
+				return len(names)
+			}
+		}
+
+		return maxNames
+	}
+
+	return 1
+}
+
+func selections(set *ast.SelectionSet) []ast.Selection {
+	if set == nil {
+		return nil
+	}
+
+	return set.Selections
+}
+
+func introspection(f *ast.Field) bool {
+	return strings.HasPrefix(f.Name.Value, "__")
+}
+
+func capped(n int) int {
+	if n > math.MaxInt32 || n < 0 {
+		return math.MaxInt32
+	}
+
+	return n
+}
@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+)
+
+// loaderConcurrency bounds the concurrent fetches of a batch
+const loaderConcurrency = 8
+
+type (
+	// loader batches and deduplicates the fetches of a single request. Resolvers register keys and
+	// return thunks, the first thunk called fetches every key registered so far concurrently
+	loader struct {
+		mu      sync.Mutex
+		results map[string]*result
+		pending []*result
+	}
+
+	result struct {
+		fetch func() (interface{}, error)
+		done  chan struct{}
+		value interface{}
+		err   error
+	}
+
+	loadersKey struct{}
+
+	// loaders are the per request loaders
+	loaders struct {
+		pokemon     *loader
+		translation *loader
+	}
+)
+
+func newLoader() *loader {
+	return &loader{
+		results: map[string]*result{},
+	}
+}
+
+func withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		pokemon:     newLoader(),
+		translation: newLoader(),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	l, ok := ctx.Value(loadersKey{}).(*loaders)
+	if !ok {
+		return &loaders{pokemon: newLoader(), translation: newLoader()}
+	}
+
+	return l
+}
+
+// load registers key and returns a thunk for its value, fetch is only called once per key
+func (l *loader) load(key string, fetch func() (interface{}, error)) func() (interface{}, error) {
+	l.mu.Lock()
+
+	r, ok := l.results[key]
+	if !ok {
+		r = &result{fetch: fetch, done: make(chan struct{})}
+
+		l.results[key] = r
+		l.pending = append(l.pending, r)
+	}
+
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.dispatch()
+		<-r.done
+
+		return r.value, r.err
+	}
+}
+
+// dispatch fetches every pending key
+func (l *loader) dispatch() {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	sem := make(chan struct{}, loaderConcurrency)
+	wg := sync.WaitGroup{}
+
+	for _, r := range batch {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(r *result) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			r.value, r.err = r.fetch()
+			close(r.done)
+		}(r)
+	}
+
+	wg.Wait()
+}
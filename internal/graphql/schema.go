@@ -0,0 +1,274 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"pokedex/internal/handler"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+	"github.com/pkg/errors"
+)
+
+// maxNames limits the names of a single pokemons query
+const maxNames = 50
+
+type resolver struct {
+	fetcher    pokeapi.PokemonFetcher
+	translator pokemon.DescriptionTranslator
+}
+
+// stat is a base stat of a Pokemon
+type stat struct {
+	Name     string
+	BaseStat int
+}
+
+// newSchema builds the schema, resolvers reuse the fetcher and translator of the REST handlers
+func newSchema(fetcher pokeapi.PokemonFetcher, translator pokemon.DescriptionTranslator) (graphql.Schema, error) {
+	r := &resolver{
+		fetcher:    fetcher,
+		translator: translator,
+	}
+
+	translationType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Translation",
+		Description: "A translated description, the original description is returned when translation failed",
+		Fields: graphql.Fields{
+			"description":    translationField(graphql.String, func(t pokemon.TranslationResult) interface{} { return t.Description }),
+			"style":          translationField(graphql.String, func(t pokemon.TranslationResult) interface{} { return t.Translation.Style }),
+			"succeeded":      translationField(graphql.Boolean, func(t pokemon.TranslationResult) interface{} { return t.Translation.Succeeded }),
+			"fallbackReason": translationField(graphql.String, func(t pokemon.TranslationResult) interface{} { return nullable(t.Translation.FallbackReason) }),
+			"cached":         translationField(graphql.Boolean, func(t pokemon.TranslationResult) interface{} { return t.Translation.Cached }),
+		},
+	})
+
+	statType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Stat",
+		Fields: graphql.Fields{
+			"name":     statField(graphql.String, func(s stat) interface{} { return s.Name }),
+			"baseStat": statField(graphql.Int, func(s stat) interface{} { return s.BaseStat }),
+		},
+	})
+
+	var pokemonType *graphql.Object
+	pokemonType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Pokemon",
+		Description: "A Pokemon species",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name":        pokemonField(graphql.String, func(p *pokeapi.Pokemon) interface{} { return p.Name }),
+				"number":      pokemonField(graphql.Int, func(p *pokeapi.Pokemon) interface{} { return nullableInt(p.Number) }),
+				"description": pokemonField(graphql.String, func(p *pokeapi.Pokemon) interface{} { return p.Description }),
+				"habitat":     pokemonField(graphql.String, func(p *pokeapi.Pokemon) interface{} { return nullable(p.Habitat) }),
+				"generation":  pokemonField(graphql.String, func(p *pokeapi.Pokemon) interface{} { return nullable(p.Generation) }),
+				"types":       pokemonField(graphql.NewList(graphql.String), func(p *pokeapi.Pokemon) interface{} { return p.Types }),
+				"stats":       pokemonField(graphql.NewList(statType), func(p *pokeapi.Pokemon) interface{} { return stats(p) }),
+				"isLegendary": pokemonField(graphql.Boolean, func(p *pokeapi.Pokemon) interface{} { return p.IsLegendary }),
+				"isMythical":  pokemonField(graphql.Boolean, func(p *pokeapi.Pokemon) interface{} { return p.IsMythical }),
+				"evolvesFrom": &graphql.Field{
+					Type:        pokemonType,
+					Description: "The species this Pokemon evolves from",
+					Resolve:     r.evolvesFrom,
+				},
+				"translation": &graphql.Field{
+					Type:        translationType,
+					Description: "The description translated to Yoda or Shakespeare speak",
+					Resolve:     r.translation,
+				},
+			}
+		}),
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"pokemon": &graphql.Field{
+				Type: pokemonType,
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: r.pokemon,
+			},
+			"pokemons": &graphql.Field{
+				Type: graphql.NewList(pokemonType),
+				Args: graphql.FieldConfigArgument{
+					"names": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				},
+				Resolve: r.pokemons,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func (r *resolver) pokemon(p graphql.ResolveParams) (interface{}, error) {
+	name, _ := p.Args["name"].(string)
+
+	thunk, err := r.load(p.Context, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return thunk, nil
+}
+
+func (r *resolver) pokemons(p graphql.ResolveParams) (interface{}, error) {
+	names, _ := p.Args["names"].([]interface{})
+	if len(names) > maxNames {
+		return nil, clientError{msg: fmt.Sprintf("at most %d names can be given", maxNames), code: "too_many_names"}
+	}
+
+	thunks := make([]func() (interface{}, error), 0, len(names))
+
+	for _, n := range names {
+		name, _ := n.(string)
+
+		thunk, err := r.load(p.Context, name)
+		if err != nil {
+			return nil, err
+		}
+
+		thunks = append(thunks, thunk)
+	}
+
+	return func() (interface{}, error) {
+		pokemons := make([]interface{}, 0, len(thunks))
+
+		for _, thunk := range thunks {
+			pok, err := thunk()
+			if err != nil {
+				return nil, err
+			}
+
+			pokemons = append(pokemons, pok)
+		}
+
+		return pokemons, nil
+	}, nil
+}
+
+func (r *resolver) evolvesFrom(p graphql.ResolveParams) (interface{}, error) {
+	pok, ok := p.Source.(*pokeapi.Pokemon)
+	if !ok || pok.EvolvesFrom == "" {
+		return nil, nil
+	}
+
+	thunk, err := r.load(p.Context, pok.EvolvesFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	return thunk, nil
+}
+
+func (r *resolver) translation(p graphql.ResolveParams) (interface{}, error) {
+	pok, ok := p.Source.(*pokeapi.Pokemon)
+	if !ok {
+		return nil, nil
+	}
+
+	ctx := p.Context
+
+	return loadersFrom(ctx).translation.load(pok.Name, func() (interface{}, error) {
+		return r.translator.TranslateDescription(ctx, pok), nil
+	}), nil
+}
+
+// load returns a thunk fetching the Pokemon through the request loader, a missing Pokemon is null
+func (r *resolver) load(ctx context.Context, name string) (func() (interface{}, error), error) {
+	if !handler.ValidName(name) {
+		return nil, resolverError(handler.ErrInvalidName)
+	}
+
+	thunk := loadersFrom(ctx).pokemon.load(name, func() (interface{}, error) {
+		pok, err := r.fetcher.FetchByName(ctx, name)
+		if err != nil {
+			return nil, resolverError(errors.Wrapf(err, "unable to fetch pokemon: %s", name))
+		}
+
+		if pok == nil {
+			return nil, nil
+		}
+
+		return pok, nil
+	})
+
+	return thunk, nil
+}
+
+// pokemonField returns a field resolved from a Pokemon source, the default resolver relies on json
+// tags which Pokemon does not have
+func pokemonField(t graphql.Output, get func(p *pokeapi.Pokemon) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			pok, ok := p.Source.(*pokeapi.Pokemon)
+			if !ok {
+				return nil, nil
+			}
+
+			return get(pok), nil
+		},
+	}
+}
+
+func translationField(t graphql.Output, get func(t pokemon.TranslationResult) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			res, ok := p.Source.(pokemon.TranslationResult)
+			if !ok {
+				return nil, nil
+			}
+
+			return get(res), nil
+		},
+	}
+}
+
+func statField(t graphql.Output, get func(s stat) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			s, ok := p.Source.(stat)
+			if !ok {
+				return nil, nil
+			}
+
+			return get(s), nil
+		},
+	}
+}
+
+func stats(pok *pokeapi.Pokemon) []stat {
+	s := make([]stat, 0, len(pok.Stats))
+	for name, value := range pok.Stats {
+		s = append(s, stat{Name: name, BaseStat: value})
+	}
+
+	sort.Slice(s, func(i, j int) bool {
+		return s[i].Name < s[j].Name
+	})
+
+	return s
+}
+
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+func nullableInt(i int) interface{} {
+	if i == 0 {
+		return nil
+	}
+
+	return i
+}
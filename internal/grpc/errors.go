@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"pokedex/internal/handler"
+	"pokedex/pkg/adapter/upstream"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain is the domain of the ErrorInfo details
+const errorDomain = "pokedex"
+
+// statusError maps err to a status with the code matching the REST status, see handler.ErrorRenderer.
+// The REST problem code is the reason of an ErrorInfo detail, the upstream Retry-After hint is a
+// RetryInfo detail. Internal errors are logged and never exposed
+func statusError(err error) error {
+	var queryErr handler.QueryError
+	if errors.As(err, &queryErr) {
+		return withInfo(status.New(codes.InvalidArgument, queryErr.Error()), queryErr.Code(), err)
+	}
+
+	sentinel, ok := handler.Sentinel(err)
+	if !ok {
+		log.Error().Err(err).Str("type", "grpc").Send()
+
+		return status.Error(codes.Internal, "an internal error occurred")
+	}
+
+	var code codes.Code
+
+	switch sentinel {
+	case handler.ErrPokemonNotFound:
+		return withInfo(status.New(codes.NotFound, sentinel.Error()), sentinel.Code(), err)
+	case handler.ErrInvalidName:
+		return withInfo(status.New(codes.InvalidArgument, sentinel.Error()), sentinel.Code(), err)
+	case handler.ErrRateLimited:
+		code = codes.ResourceExhausted
+	case handler.ErrUpstreamTimeout:
+		code = codes.DeadlineExceeded
+	default:
+		code = codes.Unavailable
+	}
+
+	log.Warn().Err(err).Str("type", "upstream").Str("code", code.String()).Msg(sentinel.Error())
+
+	return withInfo(status.New(code, sentinel.Error()), sentinel.Code(), err)
+}
+
+func withInfo(st *status.Status, reason string, err error) error {
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}}
+
+	var respErr *upstream.ResponseError
+	if errors.As(err, &respErr) && respErr.RetryAfter > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(respErr.RetryAfter)})
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
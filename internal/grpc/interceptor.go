@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// unaryLogger logs every call like the http access log and recovers from panics, see
+// middleware.LoggerMiddleware
+func unaryLogger(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (res interface{}, err error) {
+	defer logCall(ctx, info.FullMethod, time.Now(), &err)
+
+	return next(ctx, req)
+}
+
+// streamLogger is the unaryLogger of streaming calls
+func streamLogger(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) (err error) {
+	defer logCall(stream.Context(), info.FullMethod, time.Now(), &err)
+
+	return next(srv, stream)
+}
+
+func logCall(ctx context.Context, method string, start time.Time, err *error) {
+	if rec := recover(); rec != nil {
+		log.Error().
+			Str("type", "error").
+			Timestamp().
+			Interface("recover_info", rec).
+			Bytes("debug_stack", debug.Stack()).
+			Msg("log system error")
+
+		*err = status.Error(codes.Internal, "an internal error occurred")
+	}
+
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = p.Addr.String()
+	}
+
+	log.Info().
+		Str("type", "access").
+		Timestamp().
+		Fields(map[string]interface{}{
+			"remote_ip":  remote,
+			"method":     method,
+			"proto":      "grpc",
+			"code":       status.Code(*err).String(),
+			"latency_ms": float64(time.Since(start).Nanoseconds()) / 1000000.0,
+		}).
+		Msg("incoming_request")
+}
@@ -0,0 +1,257 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	pokedexv1 "pokedex/api/pokedex/v1"
+	"pokedex/internal/handler"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxNames limits the names of a single BatchGetPokemon call
+	maxNames = 50
+	// batchConcurrency bounds the concurrent fetches of a BatchGetPokemon call
+	batchConcurrency = 8
+)
+
+type server struct {
+	pokedexv1.UnimplementedPokedexServiceServer
+
+	fetcher    pokeapi.PokemonFetcher
+	translator pokemon.DescriptionTranslator
+	repo       pokeapi.PokemonRepository
+}
+
+// NewServer returns a gRPC server serving the PokedexService with the fetcher and translator of the
+// REST handlers, along with health checking and reflection. ListPokemon needs repo and is
+// unimplemented when it is nil
+func NewServer(fetcher pokeapi.PokemonFetcher, translator pokemon.DescriptionTranslator, repo pokeapi.PokemonRepository) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLogger),
+		grpc.ChainStreamInterceptor(streamLogger),
+	)
+
+	pokedexv1.RegisterPokedexServiceServer(srv, &server{
+		fetcher:    fetcher,
+		translator: translator,
+		repo:       repo,
+	})
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus(pokedexv1.PokedexService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+
+	reflection.Register(srv)
+
+	return srv
+}
+
+func (s *server) GetPokemon(ctx context.Context, req *pokedexv1.GetPokemonRequest) (*pokedexv1.Pokemon, error) {
+	pok, err := s.getPokemon(ctx, req.GetName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toProto(pok), nil
+}
+
+func (s *server) GetTranslatedPokemon(ctx context.Context, req *pokedexv1.GetTranslatedPokemonRequest) (*pokedexv1.TranslatedPokemon, error) {
+	pok, err := s.getPokemon(ctx, req.GetName())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	res := s.translator.TranslateDescription(ctx, pok)
+
+	translated := toProto(pok)
+	translated.Description = res.Description
+
+	return &pokedexv1.TranslatedPokemon{
+		Pokemon: translated,
+		Translation: &pokedexv1.Translation{
+			Style:          res.Translation.Style,
+			Succeeded:      res.Translation.Succeeded,
+			FallbackReason: res.Translation.FallbackReason,
+			Cached:         res.Translation.Cached,
+		},
+	}, nil
+}
+
+// BatchGetPokemon fetches the distinct names concurrently, the first error fails the whole batch
+func (s *server) BatchGetPokemon(ctx context.Context, req *pokedexv1.BatchGetPokemonRequest) (*pokedexv1.BatchGetPokemonResponse, error) {
+	names := req.GetNames()
+	if len(names) > maxNames {
+		return nil, statusError(handler.QueryError{fmt.Sprintf("at most %d names can be given", maxNames)})
+	}
+
+	for _, name := range names {
+		if !handler.ValidName(name) {
+			return nil, statusError(handler.ErrInvalidName)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		pokemons = map[string]*pokeapi.Pokemon{}
+		fetched  = map[string]bool{}
+		sem      = make(chan struct{}, batchConcurrency)
+	)
+
+	for _, name := range names {
+		if fetched[name] {
+			continue
+		}
+
+		fetched[name] = true
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			pok, err := s.getPokemon(ctx, name)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+
+				return
+			}
+
+			pokemons[name] = pok
+		}(name)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, statusError(firstErr)
+	}
+
+	res := &pokedexv1.BatchGetPokemonResponse{}
+	for _, name := range names {
+		res.Pokemons = append(res.Pokemons, toProto(pokemons[name]))
+	}
+
+	return res, nil
+}
+
+func (s *server) ListPokemon(req *pokedexv1.ListPokemonRequest, stream pokedexv1.PokedexService_ListPokemonServer) error {
+	if s.repo == nil {
+		return status.Error(codes.Unimplemented, "listing pokemons needs the local pokemon store")
+	}
+
+	q, err := listQuery(req)
+	if err != nil {
+		return statusError(err)
+	}
+
+	pokemons, err := s.repo.Query(stream.Context(), q)
+	if err != nil {
+		return statusError(errors.Wrap(err, "unable to query pokemons"))
+	}
+
+	for _, pok := range pokemons {
+		if err := stream.Send(toProto(pok)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *server) getPokemon(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+	if !handler.ValidName(name) {
+		return nil, handler.ErrInvalidName
+	}
+
+	pok, err := s.fetcher.FetchByName(ctx, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to fetch pokemon: %s", name)
+	}
+
+	if pok == nil {
+		return nil, errors.Wrapf(handler.ErrPokemonNotFound, "%s", name)
+	}
+
+	return pok, nil
+}
+
+// listQuery validates the filters, every problem found is reported
+func listQuery(req *pokedexv1.ListPokemonRequest) (pokeapi.Query, error) {
+	q := pokeapi.Query{
+		Habitat:    req.GetHabitat(),
+		Type:       req.GetType(),
+		Generation: req.GetGeneration(),
+		Legendary:  req.Legendary,
+		Mythical:   req.Mythical,
+	}
+
+	var errs handler.QueryError
+
+	filters := []struct {
+		param string
+		value string
+	}{
+		{"generation", q.Generation},
+		{"habitat", q.Habitat},
+		{"type", q.Type},
+	}
+
+	for _, f := range filters {
+		if f.value != "" && !handler.ValidName(f.value) {
+			errs = append(errs, fmt.Sprintf("%s must be a PokeAPI name e.g. cave", f.param))
+		}
+	}
+
+	if len(errs) > 0 {
+		return q, errs
+	}
+
+	return q, nil
+}
+
+func toProto(pok *pokeapi.Pokemon) *pokedexv1.Pokemon {
+	stats := make(map[string]int32, len(pok.Stats))
+	for name, value := range pok.Stats {
+		stats[name] = int32(value)
+	}
+
+	return &pokedexv1.Pokemon{
+		Name:        pok.Name,
+		Number:      int32(pok.Number),
+		Description: pok.Description,
+		Habitat:     pok.Habitat,
+		Generation:  pok.Generation,
+		Types:       pok.Types,
+		Stats:       stats,
+		IsLegendary: pok.IsLegendary,
+		IsMythical:  pok.IsMythical,
+		EvolvesFrom: pok.EvolvesFrom,
+	}
+}
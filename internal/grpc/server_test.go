@@ -0,0 +1,370 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	pokedexv1 "pokedex/api/pokedex/v1"
+	"pokedex/internal/handler/mocks"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var (
+	mewtwo = &pokeapi.Pokemon{
+		Name:        "mewtwo",
+		Number:      150,
+		Description: "It was created by a scientist.",
+		Habitat:     "rare",
+		Types:       []string{"psychic"},
+		Stats:       map[string]int{"hp": 106},
+		IsLegendary: true,
+	}
+	ivysaur = &pokeapi.Pokemon{
+		Name:        "ivysaur",
+		Number:      2,
+		EvolvesFrom: "bulbasaur",
+	}
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		fetcher    func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher
+		translator func(t *testing.T, c *gomock.Controller) *mocks.MockDescriptionTranslator
+		repo       func(t *testing.T, c *gomock.Controller) pokeapi.PokemonRepository
+		call       func(t *testing.T, client pokedexv1.PokedexServiceClient)
+	}
+
+	tests := map[string]testcase{
+		"GetPokemonReturnsPokemon": {
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				res, err := client.GetPokemon(context.Background(), &pokedexv1.GetPokemonRequest{Name: "mewtwo"})
+				require.NoError(t, err)
+
+				require.Equal(t, "mewtwo", res.GetName())
+				require.Equal(t, int32(150), res.GetNumber())
+				require.Equal(t, []string{"psychic"}, res.GetTypes())
+				require.Equal(t, map[string]int32{"hp": 106}, res.GetStats())
+				require.True(t, res.GetIsLegendary())
+			},
+		},
+		"GetPokemonReturnsNotFound": {
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "missingno").Return(nil, nil)
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				_, err := client.GetPokemon(context.Background(), &pokedexv1.GetPokemonRequest{Name: "missingno"})
+
+				requireStatus(t, err, codes.NotFound, "pokemon_not_found")
+			},
+		},
+		"GetPokemonRejectsInvalidNames": {
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				_, err := client.GetPokemon(context.Background(), &pokedexv1.GetPokemonRequest{Name: "Mew Two"})
+
+				requireStatus(t, err, codes.InvalidArgument, "invalid_name")
+			},
+		},
+		"GetPokemonForwardsRetryAfter": {
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, &upstream.ResponseError{
+					Kind:       upstream.ErrRateLimited,
+					StatusCode: 429,
+					RetryAfter: 30 * time.Second,
+				})
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				_, err := client.GetPokemon(context.Background(), &pokedexv1.GetPokemonRequest{Name: "mewtwo"})
+
+				st := requireStatus(t, err, codes.ResourceExhausted, "rate_limited")
+
+				var retry *errdetails.RetryInfo
+				for _, d := range st.Details() {
+					if r, ok := d.(*errdetails.RetryInfo); ok {
+						retry = r
+					}
+				}
+
+				require.NotNil(t, retry)
+				require.Equal(t, 30*time.Second, retry.GetRetryDelay().AsDuration())
+			},
+		},
+		"GetPokemonMapsUpstreamErrors": {
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrTimeout)
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				_, err := client.GetPokemon(context.Background(), &pokedexv1.GetPokemonRequest{Name: "mewtwo"})
+
+				requireStatus(t, err, codes.DeadlineExceeded, "upstream_timeout")
+			},
+		},
+		"GetPokemonHidesInternalErrors": {
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, io.ErrUnexpectedEOF)
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				_, err := client.GetPokemon(context.Background(), &pokedexv1.GetPokemonRequest{Name: "mewtwo"})
+
+				st, _ := status.FromError(err)
+				require.Equal(t, codes.Internal, st.Code())
+				require.Equal(t, "an internal error occurred", st.Message())
+			},
+		},
+		"GetTranslatedPokemonReturnsTranslation": {
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+
+				return m
+			},
+			translator: func(t *testing.T, c *gomock.Controller) *mocks.MockDescriptionTranslator {
+				m := mocks.NewMockDescriptionTranslator(c)
+				m.EXPECT().TranslateDescription(gomock.Any(), mewtwo).Return(pokemon.TranslationResult{
+					Description: "Created by a scientist, it was.",
+					Translation: pokemon.Translation{Style: pokemon.StyleYoda, Succeeded: true},
+				})
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				res, err := client.GetTranslatedPokemon(context.Background(), &pokedexv1.GetTranslatedPokemonRequest{Name: "mewtwo"})
+				require.NoError(t, err)
+
+				require.Equal(t, "Created by a scientist, it was.", res.GetPokemon().GetDescription())
+				require.Equal(t, pokemon.StyleYoda, res.GetTranslation().GetStyle())
+				require.True(t, res.GetTranslation().GetSucceeded())
+			},
+		},
+		"BatchGetPokemonKeepsTheRequestedOrder": {
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil).Times(1)
+				m.EXPECT().FetchByName(gomock.Any(), "ivysaur").Return(ivysaur, nil).Times(1)
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				res, err := client.BatchGetPokemon(context.Background(), &pokedexv1.BatchGetPokemonRequest{
+					Names: []string{"mewtwo", "ivysaur", "mewtwo"},
+				})
+				require.NoError(t, err)
+
+				var names []string
+				for _, pok := range res.GetPokemons() {
+					names = append(names, pok.GetName())
+				}
+
+				require.Equal(t, []string{"mewtwo", "ivysaur", "mewtwo"}, names)
+			},
+		},
+		"BatchGetPokemonFailsWhenAnyIsMissing": {
+			fetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil).AnyTimes()
+				m.EXPECT().FetchByName(gomock.Any(), "missingno").Return(nil, nil)
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				_, err := client.BatchGetPokemon(context.Background(), &pokedexv1.BatchGetPokemonRequest{
+					Names: []string{"mewtwo", "missingno"},
+				})
+
+				requireStatus(t, err, codes.NotFound, "pokemon_not_found")
+			},
+		},
+		"BatchGetPokemonRejectsTooManyNames": {
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				names := make([]string, maxNames+1)
+				for i := range names {
+					names[i] = "mewtwo"
+				}
+
+				_, err := client.BatchGetPokemon(context.Background(), &pokedexv1.BatchGetPokemonRequest{Names: names})
+
+				requireStatus(t, err, codes.InvalidArgument, "invalid_query")
+			},
+		},
+		"ListPokemonStreamsMatches": {
+			repo: func(t *testing.T, c *gomock.Controller) pokeapi.PokemonRepository {
+				legendary := true
+
+				m := mocks.NewMockPokemonRepository(c)
+				m.EXPECT().
+					Query(gomock.Any(), pokeapi.Query{Habitat: "rare", Legendary: &legendary}).
+					Return([]*pokeapi.Pokemon{ivysaur, mewtwo}, nil)
+
+				return m
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				legendary := true
+
+				stream, err := client.ListPokemon(context.Background(), &pokedexv1.ListPokemonRequest{
+					Habitat:   "rare",
+					Legendary: &legendary,
+				})
+				require.NoError(t, err)
+
+				var names []string
+				for {
+					pok, err := stream.Recv()
+					if err == io.EOF {
+						break
+					}
+					require.NoError(t, err)
+
+					names = append(names, pok.GetName())
+				}
+
+				require.Equal(t, []string{"ivysaur", "mewtwo"}, names)
+			},
+		},
+		"ListPokemonRejectsInvalidFilters": {
+			repo: func(t *testing.T, c *gomock.Controller) pokeapi.PokemonRepository {
+				return mocks.NewMockPokemonRepository(c)
+			},
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				stream, err := client.ListPokemon(context.Background(), &pokedexv1.ListPokemonRequest{Type: "Fire!"})
+				require.NoError(t, err)
+
+				_, err = stream.Recv()
+				requireStatus(t, err, codes.InvalidArgument, "invalid_query")
+			},
+		},
+		"ListPokemonNeedsTheStore": {
+			call: func(t *testing.T, client pokedexv1.PokedexServiceClient) {
+				stream, err := client.ListPokemon(context.Background(), &pokedexv1.ListPokemonRequest{})
+				require.NoError(t, err)
+
+				_, err = stream.Recv()
+				require.Equal(t, codes.Unimplemented, status.Code(err))
+			},
+		},
+	}
+
+	for name, testCase := range tests {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fetcher := mocks.NewMockPokemonFetcher(ctrl)
+			if tc.fetcher != nil {
+				fetcher = tc.fetcher(t, ctrl)
+			}
+
+			translator := mocks.NewMockDescriptionTranslator(ctrl)
+			if tc.translator != nil {
+				translator = tc.translator(t, ctrl)
+			}
+
+			var repo pokeapi.PokemonRepository
+			if tc.repo != nil {
+				repo = tc.repo(t, ctrl)
+			}
+
+			conn := dial(t, NewServer(fetcher, translator, repo))
+
+			tc.call(t, pokedexv1.NewPokedexServiceClient(conn))
+		})
+	}
+}
+
+func TestServer_HealthCheck(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conn := dial(t, NewServer(mocks.NewMockPokemonFetcher(ctrl), mocks.NewMockDescriptionTranslator(ctrl), nil))
+
+	res, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: pokedexv1.PokedexService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+}
+
+// dial serves srv on an in-memory listener and returns a client connection to it
+func dial(t *testing.T, srv *grpc.Server) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Stop()
+	})
+
+	return conn
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, reason string) *status.Status {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok, err)
+	require.Equal(t, code, st.Code(), st.Message())
+
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if i, ok := d.(*errdetails.ErrorInfo); ok {
+			info = i
+		}
+	}
+
+	require.NotNil(t, info)
+	require.Equal(t, reason, info.GetReason())
+	require.Equal(t, errorDomain, info.GetDomain())
+
+	return st
+}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/cache"
+	"pokedex/pkg/http/response"
+	"pokedex/pkg/secret"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxWarmupNames limits the names of a single warm-up
+	maxWarmupNames = 1000
+	// topRequested is the number of most requested Pokemons reported with the cache stats
+	topRequested = 10
+
+	maxWarmupBodySize = 64 << 10
+)
+
+type (
+	// CacheStats describes the Pokemon and translation caches along with the most requested Pokemons
+	CacheStats struct {
+		Pokemon      cache.Stats       `json:"pokemon"`
+		Translation  cache.Stats       `json:"translation"`
+		TopRequested []cache.NameCount `json:"top_requested"`
+	}
+
+	// CacheEntry holds what is cached for a Pokemon, translations are keyed by style
+	CacheEntry struct {
+		Name         string            `json:"name"`
+		Pokemon      *pokeapi.Pokemon  `json:"pokemon,omitempty"`
+		Translations map[string]string `json:"translations"`
+	}
+
+	// PurgeResult counts the purged cache entries
+	PurgeResult struct {
+		Pokemons     int `json:"pokemons"`
+		Translations int `json:"translations"`
+	}
+
+	// WarmupRequest lists the names to warm up, or how many of the most requested Pokemons to warm up
+	WarmupRequest struct {
+		Names []string `json:"names"`
+		Top   int      `json:"top"`
+	}
+)
+
+// RequireToken rejects requests without the secret of source as bearer token, source is read on
+// every request so the token can be rotated. Rejected requests are audit logged
+func RequireToken(source func() secret.Source) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			src := source()
+			if src == nil {
+				audit(r, "auth.denied").Str("reason", "no token configured").Send()
+				_ = ErrorRenderer(ErrUnauthorized).Render(w, r)
+
+				return
+			}
+
+			token, err := src.Secret()
+			if err != nil {
+				_ = ErrorRenderer(errors.Wrap(err, "unable to read admin token")).Render(w, r)
+
+				return
+			}
+
+			given, bearer := cutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" || !bearer || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				audit(r, "auth.denied").Str("reason", "invalid token").Send()
+				_ = ErrorRenderer(ErrUnauthorized).Render(w, r)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// cutPrefix returns s without prefix and whether s started with it
+func cutPrefix(s string, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+
+	return s[len(prefix):], true
+}
+
+// GetCacheStats returns the stats of the Pokemon and translation caches and the most requested
+// Pokemons
+func GetCacheStats(pokemons *pokeapi.Cache, translations *pokemon.CachedTranslator, requests *cache.Counter) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		audit(r, "cache.stats").Send()
+
+		return response.JSON(http.StatusOK, CacheStats{
+			Pokemon:      pokemons.Stats(r.Context()),
+			Translation:  translations.Stats(r.Context()),
+			TopRequested: requests.Top(topRequested),
+		})
+	})
+}
+
+// LookupCache returns the cached Pokemon and translations for a given name
+func LookupCache(pokemons *pokeapi.Cache, translations *pokemon.CachedTranslator) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		name := chi.URLParam(r, nameParam)
+		if !validName.MatchString(name) {
+			return ErrorRenderer(ErrInvalidName)
+		}
+
+		audit(r, "cache.lookup").Str("name", name).Send()
+
+		entry := CacheEntry{
+			Name:         name,
+			Translations: translations.Lookup(r.Context(), name),
+		}
+
+		if pok, ok := pokemons.Lookup(r.Context(), name); ok {
+			entry.Pokemon = pok
+		}
+
+		if entry.Pokemon == nil && len(entry.Translations) == 0 {
+			return ErrorRenderer(errors.Wrapf(ErrNotCached, "%s", name))
+		}
+
+		return response.JSON(http.StatusOK, entry)
+	})
+}
+
+// PurgeCache removes the cache entries matching the name, prefix and style query parameters,
+// at least one is required. Pokemons are only purged when no style is given
+func PurgeCache(pokemons *pokeapi.Cache, translations *pokemon.CachedTranslator) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		query := r.URL.Query()
+		name, prefix, style := query.Get("name"), query.Get("prefix"), query.Get("style")
+
+		var errs QueryError
+
+		if name != "" && !validName.MatchString(name) {
+			errs = append(errs, "name must be a PokeAPI name e.g. mewtwo")
+		}
+
+		if prefix != "" && !validName.MatchString(prefix) {
+			errs = append(errs, "prefix must be the beginning of a PokeAPI name e.g. mew")
+		}
+
+		if style != "" && style != pokemon.StyleYoda && style != pokemon.StyleShakespeare {
+			errs = append(errs, fmt.Sprintf("style must be %s or %s", pokemon.StyleYoda, pokemon.StyleShakespeare))
+		}
+
+		if name == "" && prefix == "" && style == "" {
+			errs = append(errs, "at least one of name, prefix or style must be given")
+		}
+
+		if len(errs) > 0 {
+			return ErrorRenderer(errs)
+		}
+
+		matchName := func(n string) bool {
+			return (name == "" || n == name) && strings.HasPrefix(n, prefix)
+		}
+
+		var res PurgeResult
+
+		if style == "" {
+			res.Pokemons = pokemons.Purge(r.Context(), matchName)
+		}
+
+		res.Translations = translations.Purge(r.Context(), func(s string, n string) bool {
+			return (style == "" || s == style) && matchName(n)
+		})
+
+		audit(r, "cache.purge").
+			Str("name", name).
+			Str("prefix", prefix).
+			Str("style", style).
+			Int("pokemons", res.Pokemons).
+			Int("translations", res.Translations).
+			Send()
+
+		return response.JSON(http.StatusOK, res)
+	})
+}
+
+// WarmupCache loads the given names, or the most requested ones, into the Pokemon and translation
+// caches and returns once done
+func WarmupCache(warmer *pokemon.Warmer, requests *cache.Counter) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		var req WarmupRequest
+
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWarmupBodySize))
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(&req); err != nil {
+			return ErrorRenderer(QueryError{"body must be a json object with either names or top"})
+		}
+
+		names, err := warmupNames(req, requests)
+		if err != nil {
+			return ErrorRenderer(err)
+		}
+
+		res := warmer.Warm(r.Context(), names)
+
+		audit(r, "cache.warmup").
+			Strs("names", names).
+			Int("top", req.Top).
+			Int("warmed", res.Warmed).
+			Int("failed", len(res.Failed)).
+			Send()
+
+		return response.JSON(http.StatusOK, res)
+	})
+}
+
+func warmupNames(req WarmupRequest, requests *cache.Counter) ([]string, error) {
+	var errs QueryError
+
+	if (len(req.Names) == 0) == (req.Top == 0) {
+		errs = append(errs, "exactly one of names or top must be given")
+	}
+
+	if len(req.Names) > maxWarmupNames || req.Top < 0 || req.Top > maxWarmupNames {
+		errs = append(errs, fmt.Sprintf("at most %d names can be warmed up", maxWarmupNames))
+	}
+
+	for _, name := range req.Names {
+		if !validName.MatchString(name) {
+			errs = append(errs, fmt.Sprintf("%q is not a PokeAPI name", name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if req.Top == 0 {
+		return req.Names, nil
+	}
+
+	names := []string{}
+	for _, nc := range requests.Top(req.Top) {
+		names = append(names, nc.Name)
+	}
+
+	return names, nil
+}
+
+// audit logs an admin action, audit events have no level so they are logged whatever the log level
+func audit(r *http.Request, action string) *zerolog.Event {
+	return log.Log().
+		Str("type", "audit").
+		Str("action", action).
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Str("remote_addr", r.RemoteAddr).
+		Str("request_id", middleware.GetReqID(r.Context()))
+}
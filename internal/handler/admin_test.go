@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"pokedex/internal/handler/mocks"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/cache"
+	"pokedex/pkg/secret"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// adminCaches holds real caches in front of mocks, warmed up with mewtwo and eevee
+type adminCaches struct {
+	pokemons     *pokeapi.Cache
+	translations *pokemon.CachedTranslator
+	requests     *cache.Counter
+	warmer       *pokemon.Warmer
+}
+
+func newAdminCaches(t *testing.T, c *gomock.Controller) adminCaches {
+	t.Helper()
+
+	fetcher := mocks.NewMockPokemonFetcher(c)
+	fetcher.EXPECT().
+		FetchByName(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+			if name == "missingno" {
+				return nil, nil
+			}
+
+			return &pokeapi.Pokemon{Name: name, Description: "original", IsLegendary: name == "mewtwo"}, nil
+		}).
+		AnyTimes()
+
+	translator := mocks.NewMockDescriptionTranslator(c)
+	translator.EXPECT().
+		TranslateDescription(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, pok *pokeapi.Pokemon) pokemon.TranslationResult {
+			return pokemon.TranslationResult{
+				Description: "translated " + pok.Name,
+				Translation: pokemon.Translation{Style: pokemon.Style(pok), Succeeded: true},
+			}
+		}).
+		AnyTimes()
+
+	requests, err := cache.NewCounter(10)
+	require.NoError(t, err)
+
+	pokemonStore, err := cache.NewMemory(10)
+	require.NoError(t, err)
+
+	pokemons, err := pokeapi.NewCache(fetcher, pokemonStore, time.Hour, requests)
+	require.NoError(t, err)
+
+	translationStore, err := cache.NewMemory(10)
+	require.NoError(t, err)
+
+	translations, err := pokemon.NewCachedTranslator(translator, translationStore, time.Hour)
+	require.NoError(t, err)
+
+	warmer, err := pokemon.NewWarmer(pokemons.Uncounted(), translations, pokemon.WarmerOptions{Concurrency: 2})
+	require.NoError(t, err)
+
+	for _, name := range []string{"mewtwo", "mewtwo", "eevee"} {
+		pok, err := pokemons.FetchByName(context.Background(), name)
+		require.NoError(t, err)
+
+		translations.TranslateDescription(context.Background(), pok)
+	}
+
+	return adminCaches{
+		pokemons:     pokemons,
+		translations: translations,
+		requests:     requests,
+		warmer:       warmer,
+	}
+}
+
+func TestRequireToken(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		source        func() secret.Source
+		authorization string
+		code          int
+	}
+
+	tests := map[string]testcase{
+		"PassesWhenTokenMatches": {
+			source:        func() secret.Source { return secret.Static("t0k3n") },
+			authorization: "Bearer t0k3n",
+			code:          http.StatusNoContent,
+		},
+		"ReturnsUnauthorizedWhenTokenMissing": {
+			source: func() secret.Source { return secret.Static("t0k3n") },
+			code:   http.StatusUnauthorized,
+		},
+		"ReturnsUnauthorizedWhenTokenInvalid": {
+			source:        func() secret.Source { return secret.Static("t0k3n") },
+			authorization: "Bearer t0k3",
+			code:          http.StatusUnauthorized,
+		},
+		"ReturnsUnauthorizedWhenTokenNotBearer": {
+			source:        func() secret.Source { return secret.Static("t0k3n") },
+			authorization: "t0k3n",
+			code:          http.StatusUnauthorized,
+		},
+		"ReturnsUnauthorizedWhenNoTokenConfigured": {
+			source:        func() secret.Source { return nil },
+			authorization: "Bearer ",
+			code:          http.StatusUnauthorized,
+		},
+		"ReturnsUnauthorizedWhenConfiguredTokenEmpty": {
+			source:        func() secret.Source { return secret.Static("") },
+			authorization: "Bearer ",
+			code:          http.StatusUnauthorized,
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/cache", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			RequireToken(tc.source)(next).ServeHTTP(rec, req)
+
+			require.Equal(t, tc.code, rec.Code)
+
+			if tc.code == http.StatusUnauthorized {
+				requireProblem(t, rec, "unauthorized")
+				require.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestGetCacheStats(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	caches := newAdminCaches(t, ctrl)
+
+	rec := httptest.NewRecorder()
+	GetCacheStats(caches.pokemons, caches.translations, caches.requests).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cache", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	stats := CacheStats{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+
+	require.Equal(t, CacheStats{
+		Pokemon:      cache.Stats{Entries: 2, Hits: 1, Misses: 2},
+		Translation:  cache.Stats{Entries: 2, Hits: 1, Misses: 2},
+		TopRequested: []cache.NameCount{{Name: "mewtwo", Count: 2}, {Name: "eevee", Count: 1}},
+	}, stats)
+}
+
+func TestLookupCache(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		name          string
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsCachedPokemonAndTranslations": {
+			name: "mewtwo",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				entry := CacheEntry{}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entry))
+
+				require.Equal(t, "mewtwo", entry.Name)
+				require.Equal(t, "original", entry.Pokemon.Description)
+				require.Equal(t, map[string]string{pokemon.StyleYoda: "translated mewtwo"}, entry.Translations)
+			},
+		},
+		"ReturnsNotFoundWhenNothingCached": {
+			name: "ditto",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, rec.Code)
+				requireProblem(t, rec, "not_cached")
+			},
+		},
+		"ReturnsBadRequestWhenNameInvalid": {
+			name: "Mew Two",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_name")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			caches := newAdminCaches(t, ctrl)
+
+			ctx := chi.NewRouteContext()
+			ctx.URLParams.Add(nameParam, tc.name)
+
+			req := httptest.NewRequest(http.MethodGet, "/cache/pokemon/"+url.PathEscape(tc.name), nil)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+
+			rec := httptest.NewRecorder()
+			LookupCache(caches.pokemons, caches.translations).ServeHTTP(rec, req)
+
+			tc.checkResponse(t, rec)
+		})
+	}
+}
+
+func TestPurgeCache(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		query         string
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches)
+	}
+
+	tests := map[string]testcase{
+		"PurgesPokemonAndTranslationsByName": {
+			query: "name=mewtwo",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.JSONEq(t, `{"pokemons":1,"translations":1}`, rec.Body.String())
+
+				_, ok := caches.pokemons.Lookup(context.Background(), "mewtwo")
+				require.False(t, ok)
+				_, ok = caches.pokemons.Lookup(context.Background(), "eevee")
+				require.True(t, ok)
+			},
+		},
+		"PurgesByPrefix": {
+			query: "prefix=mew",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.JSONEq(t, `{"pokemons":1,"translations":1}`, rec.Body.String())
+			},
+		},
+		"PurgesOnlyTranslationsByStyle": {
+			query: "style=shakespeare",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.JSONEq(t, `{"pokemons":0,"translations":1}`, rec.Body.String())
+
+				require.Empty(t, caches.translations.Lookup(context.Background(), "eevee"))
+				require.NotEmpty(t, caches.translations.Lookup(context.Background(), "mewtwo"))
+				require.Equal(t, 2, caches.pokemons.Stats(context.Background()).Entries)
+			},
+		},
+		"ReturnsBadRequestWhenNoFilterGiven": {
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_query")
+				require.Equal(t, 2, caches.pokemons.Stats(context.Background()).Entries)
+			},
+		},
+		"ReturnsBadRequestWhenFiltersInvalid": {
+			query: "name=Mew&prefix=-&style=pirate",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_query")
+				require.Contains(t, rec.Body.String(), "style must be yoda or shakespeare")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			caches := newAdminCaches(t, ctrl)
+
+			rec := httptest.NewRecorder()
+			PurgeCache(caches.pokemons, caches.translations).
+				ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/cache?"+tc.query, nil))
+
+			tc.checkResponse(t, rec, caches)
+		})
+	}
+}
+
+func TestWarmupCache(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		body          string
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches)
+	}
+
+	tests := map[string]testcase{
+		"WarmsUpGivenNames": {
+			body: `{"names":["ditto","missingno"]}`,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.JSONEq(t, `{"requested":2,"warmed":1,"failed":{"missingno":"pokemon not found"}}`, rec.Body.String())
+
+				_, ok := caches.pokemons.Lookup(context.Background(), "ditto")
+				require.True(t, ok)
+				require.NotEmpty(t, caches.translations.Lookup(context.Background(), "ditto"))
+				require.Equal(t, []cache.NameCount{{Name: "mewtwo", Count: 2}, {Name: "eevee", Count: 1}}, caches.requests.Top(10))
+			},
+		},
+		"WarmsUpMostRequested": {
+			body: `{"top":1}`,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.JSONEq(t, `{"requested":1,"warmed":1}`, rec.Body.String())
+			},
+		},
+		"ReturnsBadRequestWhenNamesAndTopGiven": {
+			body: `{"names":["ditto"],"top":1}`,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_query")
+			},
+		},
+		"ReturnsBadRequestWhenNameInvalid": {
+			body: `{"names":["Mew Two"]}`,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_query")
+			},
+		},
+		"ReturnsBadRequestWhenBodyInvalid": {
+			body: `{"name":"ditto"}`,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, caches adminCaches) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_query")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			caches := newAdminCaches(t, ctrl)
+
+			rec := httptest.NewRecorder()
+			WarmupCache(caches.warmer, caches.requests).
+				ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/cache/warmup", strings.NewReader(tc.body)))
+
+			tc.checkResponse(t, rec, caches)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"pokedex/pkg/http/response"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	swaggerFiles "github.com/swaggo/files"
+)
+
+// OpenAPI serves the OpenAPI document as json
+func OpenAPI(doc *openapi3.T) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		return response.JSON(http.StatusOK, doc)
+	})
+}
+
+// Docs serves the Swagger UI for the OpenAPI document at specURL under prefix, the Swagger UI
+// assets are embedded in the binary
+func Docs(prefix string, specURL string) http.HandlerFunc {
+	assets := http.StripPrefix(prefix, http.FileServer(swaggerFiles.HTTP))
+	page := fmt.Sprintf(swaggerPage, prefix, specURL)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, prefix) {
+		case "", "/", "/index.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(page))
+		default:
+			assets.ServeHTTP(w, r)
+		}
+	}
+}
+
+const swaggerPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Pokedex API</title>
+  <link rel="stylesheet" href="%[1]s/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%[1]s/swagger-ui-bundle.js"></script>
+  <script src="%[1]s/swagger-ui-standalone-preset.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "%[2]s",
+      dom_id: "#swagger-ui",
+      presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+      layout: "StandaloneLayout"
+    });
+  </script>
+</body>
+</html>
+`
@@ -2,33 +2,200 @@ package handler
 
 import (
 	"errors"
+	"math"
+	"net/http"
+	"pokedex/pkg/adapter/upstream"
 	"pokedex/pkg/http/response"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
 )
 
 // Sentinel errors
 const (
 	ErrPokemonNotFound Error = iota + 1
+	ErrUpstreamUnavailable
+	ErrRateLimited
+	ErrInvalidName
+	ErrTranslationFailed
+	ErrUpstreamTimeout
+	ErrUpstreamBadResponse
+	ErrUnauthorized
+	ErrNotCached
+	ErrJobNotFound
+	ErrJobQueueFull
 )
 
-// Error is a sentinel error
+// Error is a sentinel error, its message and code are safe to expose to clients
 type Error uint
 
 func (e Error) Error() string {
 	switch e {
 	case ErrPokemonNotFound:
 		return "pokemon not found"
+	case ErrUpstreamUnavailable:
+		return "upstream service unavailable"
+	case ErrRateLimited:
+		return "rate limit exceeded"
+	case ErrInvalidName:
+		return "invalid pokemon name"
+	case ErrTranslationFailed:
+		return "translation failed"
+	case ErrUpstreamTimeout:
+		return "upstream service timed out"
+	case ErrUpstreamBadResponse:
+		return "upstream service returned an invalid response"
+	case ErrUnauthorized:
+		return "missing or invalid token"
+	case ErrNotCached:
+		return "nothing cached for this pokemon"
+	case ErrJobNotFound:
+		return "translation job not found"
+	case ErrJobQueueFull:
+		return "too many translation jobs queued"
 	}
 
 	return "unknown error"
 }
 
+// Code returns a stable machine readable code for the error
+func (e Error) Code() string {
+	switch e {
+	case ErrPokemonNotFound:
+		return "pokemon_not_found"
+	case ErrUpstreamUnavailable:
+		return "upstream_unavailable"
+	case ErrRateLimited:
+		return "rate_limited"
+	case ErrInvalidName:
+		return "invalid_name"
+	case ErrTranslationFailed:
+		return "translation_failed"
+	case ErrUpstreamTimeout:
+		return "upstream_timeout"
+	case ErrUpstreamBadResponse:
+		return "upstream_bad_response"
+	case ErrUnauthorized:
+		return "unauthorized"
+	case ErrNotCached:
+		return "not_cached"
+	case ErrJobNotFound:
+		return "job_not_found"
+	case ErrJobQueueFull:
+		return "job_queue_full"
+	}
+
+	return "unknown_error"
+}
+
+// QueryError lists the problems of an invalid query, its message and code are safe to expose to
+// clients
+type QueryError []string
+
+func (e QueryError) Error() string {
+	return "invalid query: " + strings.Join(e, "; ")
+}
+
+// Code returns a stable machine readable code for the error
+func (e QueryError) Code() string {
+	return "invalid_query"
+}
+
 // ErrorRenderer returns a response.Renderer for handling errors
 func ErrorRenderer(err error) response.Renderer {
+	var queryErr QueryError
+	if errors.As(err, &queryErr) {
+		return response.Error(http.StatusBadRequest, queryErr)
+	}
+
+	sentinel, ok := Sentinel(err)
+	if !ok {
+		return response.InternalServerError(err)
+	}
+
+	switch sentinel {
+	case ErrPokemonNotFound:
+		return response.NotFound(sentinel)
+	case ErrInvalidName:
+		return response.Error(http.StatusBadRequest, sentinel)
+	case ErrUnauthorized:
+		return response.WithHeader("WWW-Authenticate", "Bearer", response.Error(http.StatusUnauthorized, sentinel))
+	case ErrNotCached, ErrJobNotFound:
+		return response.NotFound(sentinel)
+	case ErrJobQueueFull:
+		return response.Error(http.StatusServiceUnavailable, sentinel)
+	case ErrRateLimited:
+		return upstreamErrorRenderer(err, http.StatusTooManyRequests, sentinel)
+	case ErrUpstreamUnavailable:
+		return upstreamErrorRenderer(err, http.StatusServiceUnavailable, sentinel)
+	case ErrUpstreamTimeout:
+		return upstreamErrorRenderer(err, http.StatusGatewayTimeout, sentinel)
+	case ErrUpstreamBadResponse:
+		return upstreamErrorRenderer(err, http.StatusBadGateway, sentinel)
+	}
+
+	return response.Error(http.StatusBadGateway, sentinel)
+}
+
+// Sentinel returns the sentinel error matching err, upstream errors are matched to the sentinel
+// reported to clients. Errors without a sentinel are internal errors
+func Sentinel(err error) (Error, bool) {
 	switch {
 	case
 		errors.Is(err, ErrPokemonNotFound):
-		return response.NotFound(err)
+		return ErrPokemonNotFound, true
+	case
+		errors.Is(err, ErrInvalidName):
+		return ErrInvalidName, true
+	case
+		errors.Is(err, ErrRateLimited),
+		errors.Is(err, upstream.ErrRateLimited):
+		return ErrRateLimited, true
+	case
+		errors.Is(err, ErrUpstreamUnavailable),
+		errors.Is(err, upstream.ErrUnavailable):
+		return ErrUpstreamUnavailable, true
+	case
+		errors.Is(err, ErrUpstreamTimeout),
+		errors.Is(err, upstream.ErrTimeout):
+		return ErrUpstreamTimeout, true
+	case
+		errors.Is(err, ErrUpstreamBadResponse),
+		errors.Is(err, upstream.ErrBadResponse):
+		return ErrUpstreamBadResponse, true
+	case
+		errors.Is(err, ErrTranslationFailed):
+		return ErrTranslationFailed, true
+	case
+		errors.Is(err, ErrUnauthorized):
+		return ErrUnauthorized, true
+	case
+		errors.Is(err, ErrNotCached):
+		return ErrNotCached, true
+	case
+		errors.Is(err, ErrJobNotFound):
+		return ErrJobNotFound, true
+	case
+		errors.Is(err, ErrJobQueueFull):
+		return ErrJobQueueFull, true
+	}
+
+	return 0, false
+}
+
+// upstreamErrorRenderer logs upstream failures separately from internal errors and forwards the
+// upstream Retry-After hint when there is one
+func upstreamErrorRenderer(err error, status int, sentinel Error) response.Renderer {
+	log.Warn().Err(err).Str("type", "upstream").Int("status", status).Msg(sentinel.Error())
+
+	renderer := response.Error(status, sentinel)
+
+	var respErr *upstream.ResponseError
+	if errors.As(err, &respErr) && respErr.RetryAfter > 0 {
+		seconds := int(math.Ceil(respErr.RetryAfter.Seconds()))
+		renderer = response.WithHeader("Retry-After", strconv.Itoa(seconds), renderer)
 	}
 
-	return response.InternalServerError(err)
+	return renderer
 }
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/http/response"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+)
+
+const (
+	jobIDParam = "id"
+	jobsPath   = "/v1/jobs/"
+)
+
+type (
+	// PendingTranslation is a Pokemon with its original description while its translation job runs
+	PendingTranslation struct {
+		*pokeapi.Pokemon
+		Job JobLink `json:"job"`
+	}
+
+	// JobLink refers to a translation job, Href is where its status is polled
+	JobLink struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Href   string `json:"href"`
+	}
+)
+
+// TranslateAsync submits a translation job for a given name when the async query parameter is set,
+// returning 202 with the original description and a link to the job. The callback_url query
+// parameter is where the completed job is posted. Requests without async are served by sync
+func TranslateAsync(pc pokeapi.PokemonFetcher, jobs *pokemon.Jobs, sync http.HandlerFunc) http.HandlerFunc {
+	async := response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		ctx := r.Context()
+
+		pok, err := getPokemon(ctx, pc, chi.URLParam(r, nameParam))
+		if err != nil {
+			return ErrorRenderer(err)
+		}
+
+		job, err := jobs.Submit(ctx, pok, r.URL.Query().Get("callback_url"))
+		if errors.Is(err, pokemon.ErrInvalidCallback) {
+			reason := strings.TrimSuffix(err.Error(), ": "+pokemon.ErrInvalidCallback.Error())
+
+			return ErrorRenderer(QueryError{"callback_url is invalid: " + reason})
+		}
+
+		if errors.Is(err, pokemon.ErrQueueFull) {
+			return ErrorRenderer(ErrJobQueueFull)
+		}
+
+		if err != nil {
+			return ErrorRenderer(err)
+		}
+
+		href := jobsPath + job.ID
+
+		return response.WithHeader("Location", href, response.Negotiate(http.StatusAccepted, PendingTranslation{
+			Pokemon: pok,
+			Job: JobLink{
+				ID:     job.ID,
+				Status: job.Status,
+				Href:   href,
+			},
+		}))
+	})
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if queryFlag(r, "async") {
+			async(w, r)
+
+			return
+		}
+
+		sync(w, r)
+	}
+}
+
+// GetJob returns a translation job, with the translated Pokemon once it is completed
+func GetJob(jobs *pokemon.Jobs) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		job, ok, err := jobs.Get(r.Context(), chi.URLParam(r, jobIDParam))
+		if err != nil {
+			return ErrorRenderer(errors.Wrap(err, "unable to get translation job"))
+		}
+
+		if !ok {
+			return ErrorRenderer(ErrJobNotFound)
+		}
+
+		return response.JSON(http.StatusOK, job)
+	})
+}
+
+// queryFlag reports whether the given query parameter is set, e.g. ?async or ?async=true
+func queryFlag(r *http.Request, name string) bool {
+	query := r.URL.Query()
+	if _, ok := query[name]; !ok {
+		return false
+	}
+
+	if query.Get(name) == "" {
+		return true
+	}
+
+	v, err := strconv.ParseBool(query.Get(name))
+
+	return err == nil && v
+}
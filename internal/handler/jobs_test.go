@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"pokedex/internal/handler/mocks"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/queue"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJobs(t *testing.T) *pokemon.Jobs {
+	t.Helper()
+
+	q, err := queue.Open(filepath.Join(t.TempDir(), "jobs.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = q.Close() })
+
+	jobs, err := pokemon.NewJobs(q, mocks.NewMockDescriptionTranslator(gomock.NewController(t)), pokemon.JobOptions{
+		Workers:         1,
+		MaxPending:      1,
+		MaxAttempts:     1,
+		RetryDelay:      time.Second,
+		Retention:       time.Hour,
+		CallbackHosts:   []string{"example.com"},
+		CallbackTimeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	return jobs
+}
+
+func withURLParam(req *http.Request, key string, value string) *http.Request {
+	ctx := chi.NewRouteContext()
+	ctx.URLParams.Add(key, value)
+
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, ctx))
+}
+
+func TestTranslateAsync(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		target string
+		// pending is the number of jobs submitted before
+		pending       int
+		fetcher       func(m *mocks.MockPokemonFetcher)
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder, jobs *pokemon.Jobs)
+	}
+
+	sync := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}
+
+	tests := map[string]testcase{
+		"ServesSynchronouslyWithoutAsync": {
+			target: "/v1/pokemon/translated/mewtwo",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, jobs *pokemon.Jobs) {
+				require.Equal(t, http.StatusTeapot, rec.Code)
+			},
+		},
+		"ReturnsAcceptedWithOriginalDescriptionAndJobLink": {
+			target: "/v1/pokemon/translated/mewtwo?async&callback_url=https://example.com/translations",
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), pokemonName).Return(&pokeapi.Pokemon{Name: pokemonName, Description: "original"}, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, jobs *pokemon.Jobs) {
+				require.Equal(t, http.StatusAccepted, rec.Code)
+
+				var res PendingTranslation
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+				require.Equal(t, "original", res.Description)
+				require.Equal(t, pokemon.JobQueued, res.Job.Status)
+				require.Equal(t, "/v1/jobs/"+res.Job.ID, res.Job.Href)
+				require.Equal(t, res.Job.Href, rec.Header().Get("Location"))
+
+				job, ok, err := jobs.Get(context.Background(), res.Job.ID)
+				require.NoError(t, err)
+				require.True(t, ok)
+				require.Equal(t, pokemonName, job.Name)
+			},
+		},
+		"ReturnsBadRequestWhenCallbackHostNotAllowed": {
+			target: "/v1/pokemon/translated/mewtwo?async=true&callback_url=http://localhost:8080/admin",
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), pokemonName).Return(&pokeapi.Pokemon{Name: pokemonName}, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, jobs *pokemon.Jobs) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_query")
+				require.Contains(t, rec.Body.String(), "callback_url is invalid: host localhost is not allowed")
+			},
+		},
+		"ReturnsServiceUnavailableWhenJobQueueFull": {
+			target:  "/v1/pokemon/translated/mewtwo?async",
+			pending: 1,
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), pokemonName).Return(&pokeapi.Pokemon{Name: pokemonName}, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, jobs *pokemon.Jobs) {
+				require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+				requireProblem(t, rec, "job_queue_full")
+			},
+		},
+		"ReturnsNotFoundWhenPokemonNotFound": {
+			target: "/v1/pokemon/translated/mewtwo?async",
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), pokemonName).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder, jobs *pokemon.Jobs) {
+				require.Equal(t, http.StatusNotFound, rec.Code)
+				requireProblem(t, rec, "pokemon_not_found")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fetcher := mocks.NewMockPokemonFetcher(ctrl)
+			if tc.fetcher != nil {
+				tc.fetcher(fetcher)
+			}
+
+			jobs := newTestJobs(t)
+			for i := 0; i < tc.pending; i++ {
+				_, err := jobs.Submit(context.Background(), &pokeapi.Pokemon{Name: pokemonName}, "")
+				require.NoError(t, err)
+			}
+
+			rec := httptest.NewRecorder()
+			req := withURLParam(httptest.NewRequest(http.MethodGet, tc.target, nil), nameParam, pokemonName)
+
+			TranslateAsync(fetcher, jobs, sync)(rec, req)
+
+			tc.checkResponse(t, rec, jobs)
+		})
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	t.Parallel()
+
+	jobs := newTestJobs(t)
+
+	submitted, err := jobs.Submit(context.Background(), &pokeapi.Pokemon{Name: pokemonName}, "")
+	require.NoError(t, err)
+
+	t.Run("ReturnsJob", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		GetJob(jobs)(rec, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/jobs/"+submitted.ID, nil), jobIDParam, submitted.ID))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var job pokemon.Job
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		require.Equal(t, submitted.ID, job.ID)
+		require.Equal(t, pokemon.JobQueued, job.Status)
+		require.Nil(t, job.Result)
+	})
+
+	t.Run("ReturnsNotFoundWhenJobUnknown", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		GetJob(jobs)(rec, withURLParam(httptest.NewRequest(http.MethodGet, "/v1/jobs/missing", nil), jobIDParam, "missing"))
+
+		require.Equal(t, http.StatusNotFound, rec.Code)
+		requireProblem(t, rec, "job_not_found")
+	})
+}
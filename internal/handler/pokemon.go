@@ -9,6 +9,7 @@ import (
 	"pokedex/internal/service/pokemon"
 	"pokedex/pkg/adapter/pokeapi"
 	"pokedex/pkg/http/response"
+	"regexp"
 
 	"github.com/go-chi/chi"
 	"github.com/pkg/errors"
@@ -19,6 +20,20 @@ const (
 	nameParam = "name"
 )
 
+// TranslatedPokemon is a Pokemon with details about how its description was translated
+type TranslatedPokemon struct {
+	*pokeapi.Pokemon
+	Translation pokemon.Translation `json:"translation"`
+}
+
+// validName matches PokeAPI resource names e.g. "mewtwo", "mr-mime" or "porygon-z"
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// ValidName reports whether name is a PokeAPI resource name, the other APIs validate names with it
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
 // GetPokemonByName fetches a Pokemon for a given name
 func GetPokemonByName(pc pokeapi.PokemonFetcher) http.HandlerFunc {
 	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
@@ -29,7 +44,7 @@ func GetPokemonByName(pc pokeapi.PokemonFetcher) http.HandlerFunc {
 			return ErrorRenderer(err)
 		}
 
-		return response.JSON(http.StatusOK, pok)
+		return response.Negotiate(http.StatusOK, pok)
 	})
 }
 
@@ -43,13 +58,22 @@ func GetPokemonByNameTranslated(pc pokeapi.PokemonFetcher, dt pokemon.Descriptio
 			return ErrorRenderer(err)
 		}
 
-		pok.Description = dt.TranslateDescription(ctx, pok)
+		res := dt.TranslateDescription(ctx, pok)
+		pok.Description = res.Description
 
-		return response.JSON(http.StatusOK, pok)
+		return response.Negotiate(http.StatusOK, TranslatedPokemon{
+			Pokemon:     pok,
+			Translation: res.Translation,
+		})
 	})
 }
 
 func getPokemon(ctx context.Context, pc pokeapi.PokemonFetcher, name string) (*pokeapi.Pokemon, error) {
+	if !validName.MatchString(name) {
+		log.Info().Str("name", name).Msg(ErrInvalidName.Error())
+		return nil, ErrInvalidName
+	}
+
 	pok, err := pc.FetchByName(ctx, name)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to fetch pokemon: %s", name)
@@ -8,8 +8,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"pokedex/internal/handler/mocks"
+	"pokedex/internal/service/pokemon"
 	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
+	"pokedex/pkg/http/response"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
@@ -50,9 +54,10 @@ func TestGetPokemonByName(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, rec.Code)
+				requireProblem(t, rec, "pokemon_not_found")
 			},
 		},
-		"ReturnsNotFoundWhenPokemonNameEmpty": {
+		"ReturnsBadRequestWhenPokemonNameEmpty": {
 			req: func(t *testing.T) *http.Request {
 				ctx := chi.NewRouteContext()
 				ctx.URLParams.Add(nameParam, "")
@@ -64,14 +69,30 @@ func TestGetPokemonByName(t *testing.T) {
 			pokemonFetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
 				m := mocks.NewMockPokemonFetcher(c)
 
-				m.EXPECT().
-					FetchByName(gomock.Any(), "").
-					Return(nil, nil)
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_name")
+			},
+		},
+		"ReturnsBadRequestWhenPokemonNameInvalid": {
+			req: func(t *testing.T) *http.Request {
+				ctx := chi.NewRouteContext()
+				ctx.URLParams.Add(nameParam, "../mewtwo")
+
+				req := &http.Request{}
+
+				return req.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, ctx))
+			},
+			pokemonFetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
 
 				return m
 			},
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, rec.Code)
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_name")
 			},
 		},
 		"ReturnsInternalServerErrorWhenFailedToFetchPokemon": {
@@ -94,6 +115,83 @@ func TestGetPokemonByName(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, rec.Code)
+				requireProblem(t, rec, "internal_error")
+				require.NotContains(t, rec.Body.String(), "foo")
+			},
+		},
+		"ReturnsTooManyRequestsWithRetryAfterWhenUpstreamRateLimited": {
+			req: func(t *testing.T) *http.Request {
+				ctx := chi.NewRouteContext()
+				ctx.URLParams.Add(nameParam, pokemonName)
+
+				req := &http.Request{}
+
+				return req.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, ctx))
+			},
+			pokemonFetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+
+				m.EXPECT().
+					FetchByName(gomock.Any(), pokemonName).
+					Return(nil, &upstream.ResponseError{
+						Kind:       upstream.ErrRateLimited,
+						Service:    "pokeapi",
+						StatusCode: http.StatusTooManyRequests,
+						RetryAfter: 30 * time.Second,
+					})
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, rec.Code)
+				require.Equal(t, "30", rec.Header().Get("Retry-After"))
+				requireProblem(t, rec, "rate_limited")
+			},
+		},
+		"ReturnsGatewayTimeoutWhenUpstreamTimedOut": {
+			req: func(t *testing.T) *http.Request {
+				ctx := chi.NewRouteContext()
+				ctx.URLParams.Add(nameParam, pokemonName)
+
+				req := &http.Request{}
+
+				return req.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, ctx))
+			},
+			pokemonFetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+
+				m.EXPECT().
+					FetchByName(gomock.Any(), pokemonName).
+					Return(nil, upstream.FromTransport("pokeapi", context.DeadlineExceeded))
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusGatewayTimeout, rec.Code)
+				requireProblem(t, rec, "upstream_timeout")
+			},
+		},
+		"ReturnsBadGatewayWhenUpstreamReturnedBadResponse": {
+			req: func(t *testing.T) *http.Request {
+				ctx := chi.NewRouteContext()
+				ctx.URLParams.Add(nameParam, pokemonName)
+
+				req := &http.Request{}
+
+				return req.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, ctx))
+			},
+			pokemonFetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
+				m := mocks.NewMockPokemonFetcher(c)
+
+				m.EXPECT().
+					FetchByName(gomock.Any(), pokemonName).
+					Return(nil, upstream.FromStatus("pokeapi", &http.Response{StatusCode: http.StatusInternalServerError}))
+
+				return m
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadGateway, rec.Code)
+				requireProblem(t, rec, "upstream_bad_response")
 			},
 		},
 		"ReturnsOKAndPokemonWhenPokemonFound": {
@@ -187,9 +285,10 @@ func TestGetPokemonByNameTranslated(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, rec.Code)
+				requireProblem(t, rec, "pokemon_not_found")
 			},
 		},
-		"ReturnsNotFoundWhenPokemonNameEmpty": {
+		"ReturnsBadRequestWhenPokemonNameEmpty": {
 			req: func(t *testing.T) *http.Request {
 				ctx := chi.NewRouteContext()
 				ctx.URLParams.Add(nameParam, "")
@@ -201,10 +300,6 @@ func TestGetPokemonByNameTranslated(t *testing.T) {
 			pokemonFetcher: func(t *testing.T, c *gomock.Controller) *mocks.MockPokemonFetcher {
 				m := mocks.NewMockPokemonFetcher(c)
 
-				m.EXPECT().
-					FetchByName(gomock.Any(), "").
-					Return(nil, nil)
-
 				return m
 			},
 			translator: func(t *testing.T, c *gomock.Controller) *mocks.MockDescriptionTranslator {
@@ -213,7 +308,8 @@ func TestGetPokemonByNameTranslated(t *testing.T) {
 				return m
 			},
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, rec.Code)
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_name")
 			},
 		},
 		"ReturnsInternalServerErrorWhenFailedToFetchPokemon": {
@@ -241,6 +337,8 @@ func TestGetPokemonByNameTranslated(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, rec.Code)
+				requireProblem(t, rec, "internal_error")
+				require.NotContains(t, rec.Body.String(), "foo")
 			},
 		},
 		"ReturnsOKAndPokemonWithTranslatedDescriptionWhenPokemonFound": {
@@ -269,7 +367,13 @@ func TestGetPokemonByNameTranslated(t *testing.T) {
 
 				m.EXPECT().
 					TranslateDescription(gomock.Any(), gomock.AssignableToTypeOf(&pokeapi.Pokemon{})).
-					Return("translated description")
+					Return(pokemon.TranslationResult{
+						Description: "translated description",
+						Translation: pokemon.Translation{
+							Style:     pokemon.StyleShakespeare,
+							Succeeded: true,
+						},
+					})
 
 				return m
 			},
@@ -279,12 +383,14 @@ func TestGetPokemonByNameTranslated(t *testing.T) {
 				body, err := ioutil.ReadAll(rec.Body)
 				require.NoError(t, err)
 
-				pokemon := &pokeapi.Pokemon{}
-				err = json.Unmarshal(body, pokemon)
+				pok := &TranslatedPokemon{}
+				err = json.Unmarshal(body, pok)
 				require.NoError(t, err)
 
-				require.Equal(t, pokemonName, pokemon.Name)
-				require.Equal(t, "translated description", pokemon.Description)
+				require.Equal(t, pokemonName, pok.Name)
+				require.Equal(t, "translated description", pok.Description)
+				require.Equal(t, pokemon.StyleShakespeare, pok.Translation.Style)
+				require.True(t, pok.Translation.Succeeded)
 			},
 		},
 	}
@@ -308,3 +414,16 @@ func TestGetPokemonByNameTranslated(t *testing.T) {
 		})
 	}
 }
+
+func requireProblem(t *testing.T, rec *httptest.ResponseRecorder, code string) {
+	t.Helper()
+
+	require.Equal(t, response.ContentTypeProblem, rec.Header().Get("Content-Type"))
+
+	problem := &response.Problem{}
+	err := json.Unmarshal(rec.Body.Bytes(), problem)
+	require.NoError(t, err)
+
+	require.Equal(t, rec.Code, problem.Status)
+	require.Equal(t, code, problem.Code)
+}
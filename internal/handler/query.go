@@ -0,0 +1,346 @@
+//go:generate mockgen -destination=./mocks/pokemonrepository_mock.go -package=mocks pokedex/pkg/adapter/pokeapi PokemonRepository
+
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/http/response"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+var (
+	// stats maps query stat names to PokeAPI stat names
+	stats = map[string]string{
+		"hp":              "hp",
+		"attack":          "attack",
+		"defense":         "defense",
+		"special_attack":  "special-attack",
+		"special_defense": "special-defense",
+		"speed":           "speed",
+	}
+
+	// fields maps selectable field names to Pokemon json keys
+	fields = map[string]string{
+		"name":         "Name",
+		"number":       "Number",
+		"description":  "Description",
+		"habitat":      "Habitat",
+		"generation":   "Generation",
+		"types":        "Types",
+		"stats":        "Stats",
+		"is_legendary": "IsLegendary",
+		"is_mythical":  "IsMythical",
+		"evolves_from": "EvolvesFrom",
+	}
+)
+
+type (
+	// PokemonPage is a page of the Pokemons matching a query, Count is the number of matches across
+	// all pages
+	PokemonPage struct {
+		Count   int           `json:"count"`
+		Limit   int           `json:"limit"`
+		Offset  int           `json:"offset"`
+		Results []interface{} `json:"results"`
+	}
+
+	pokemonQuery struct {
+		query  pokeapi.Query
+		sort   []sortKey
+		fields []string
+		limit  int
+		offset int
+	}
+
+	sortKey struct {
+		field string
+		desc  bool
+	}
+)
+
+// QueryPokemon returns a page of the Pokemons matching the query parameters, looked up in the local
+// repository rather than PokeAPI
+func QueryPokemon(repo pokeapi.PokemonRepository) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		q, err := parseQuery(r.URL.Query())
+		if err != nil {
+			return ErrorRenderer(err)
+		}
+
+		pokemons, err := repo.Query(r.Context(), q.query)
+		if err != nil {
+			return ErrorRenderer(errors.Wrap(err, "unable to query pokemons"))
+		}
+
+		sortPokemons(pokemons, q.sort)
+
+		page := &PokemonPage{
+			Count:   len(pokemons),
+			Limit:   q.limit,
+			Offset:  q.offset,
+			Results: []interface{}{},
+		}
+
+		for i := q.offset; i < len(pokemons) && i < q.offset+q.limit; i++ {
+			result, err := selectFields(pokemons[i], q.fields)
+			if err != nil {
+				return ErrorRenderer(err)
+			}
+
+			page.Results = append(page.Results, result)
+		}
+
+		return response.Negotiate(http.StatusOK, page)
+	})
+}
+
+// parseQuery validates the query parameters, every problem found is reported
+func parseQuery(values url.Values) (*pokemonQuery, error) {
+	q := &pokemonQuery{limit: defaultLimit}
+	ranges := map[string]*pokeapi.StatRange{}
+
+	var errs QueryError
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if len(values[name]) > 1 {
+			errs = append(errs, fmt.Sprintf("%s must be given once", name))
+
+			continue
+		}
+
+		value := values.Get(name)
+
+		switch name {
+		case "habitat":
+			q.query.Habitat, errs = parseName(name, value, errs)
+		case "type":
+			q.query.Type, errs = parseName(name, value, errs)
+		case "generation":
+			q.query.Generation, errs = parseName(name, value, errs)
+		case "legendary":
+			q.query.Legendary, errs = parseBool(name, value, errs)
+		case "mythical":
+			q.query.Mythical, errs = parseBool(name, value, errs)
+		case "sort":
+			q.sort, errs = parseSort(value, errs)
+		case "fields":
+			q.fields, errs = parseFields(value, errs)
+		case "limit":
+			q.limit, errs = parseInt(name, value, 1, maxLimit, errs)
+		case "offset":
+			q.offset, errs = parseInt(name, value, 0, math.MaxInt32, errs)
+		case "format":
+			// content negotiation, see response.Negotiate
+		default:
+			errs = parseStat(name, value, ranges, errs)
+		}
+	}
+
+	for _, name := range sortedKeys(ranges) {
+		r := ranges[name]
+
+		if r.Min > r.Max {
+			errs = append(errs, fmt.Sprintf("min_%s must not be greater than max_%s", name, name))
+		}
+
+		q.query.Stats = append(q.query.Stats, *r)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return q, nil
+}
+
+func parseName(param string, value string, errs QueryError) (string, QueryError) {
+	if !validName.MatchString(value) {
+		return "", append(errs, fmt.Sprintf("%s must be a PokeAPI name e.g. cave", param))
+	}
+
+	return value, errs
+}
+
+func parseBool(param string, value string, errs QueryError) (*bool, QueryError) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, append(errs, fmt.Sprintf("%s must be true or false", param))
+	}
+
+	return &b, errs
+}
+
+func parseInt(param string, value string, min int, max int, errs QueryError) (int, QueryError) {
+	i, err := strconv.Atoi(value)
+	if err != nil || i < min || i > max {
+		return 0, append(errs, fmt.Sprintf("%s must be an integer between %d and %d", param, min, max))
+	}
+
+	return i, errs
+}
+
+// parseStat parses min_<stat> and max_<stat> parameters, anything else is an unknown parameter
+func parseStat(param string, value string, ranges map[string]*pokeapi.StatRange, errs QueryError) QueryError {
+	bound, name := "", ""
+	if i := strings.Index(param, "_"); i > 0 {
+		bound, name = param[:i], param[i+1:]
+	}
+
+	stat, ok := stats[name]
+	if !ok || (bound != "min" && bound != "max") {
+		return append(errs, fmt.Sprintf("unknown parameter %s", param))
+	}
+
+	n, errs := parseInt(param, value, 0, math.MaxInt32, errs)
+
+	r, ok := ranges[name]
+	if !ok {
+		r = &pokeapi.StatRange{Stat: stat, Max: math.MaxInt32}
+		ranges[name] = r
+	}
+
+	if bound == "min" {
+		r.Min = n
+	} else {
+		r.Max = n
+	}
+
+	return errs
+}
+
+// parseSort parses a comma separated list of fields, a leading - sorts in descending order
+func parseSort(value string, errs QueryError) ([]sortKey, QueryError) {
+	var keys []sortKey
+
+	for _, field := range strings.Split(value, ",") {
+		key := sortKey{field: strings.TrimPrefix(field, "-"), desc: strings.HasPrefix(field, "-")}
+
+		if _, ok := stats[key.field]; !ok && key.field != "name" && key.field != "number" {
+			errs = append(errs, fmt.Sprintf("cannot sort by %s, expected name, number or a stat", field))
+
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, errs
+}
+
+func parseFields(value string, errs QueryError) ([]string, QueryError) {
+	var selected []string
+
+	for _, field := range strings.Split(value, ",") {
+		if _, ok := fields[field]; !ok {
+			errs = append(errs, fmt.Sprintf("unknown field %s", field))
+
+			continue
+		}
+
+		selected = append(selected, field)
+	}
+
+	return selected, errs
+}
+
+// sortPokemons sorts by the given keys, the repository order by number is kept for ties. Pokemons
+// without a sorted stat come last
+func sortPokemons(pokemons []*pokeapi.Pokemon, keys []sortKey) {
+	sort.SliceStable(pokemons, func(i, j int) bool {
+		for _, k := range keys {
+			if c := compare(pokemons[i], pokemons[j], k); c != 0 {
+				return c < 0
+			}
+		}
+
+		return false
+	})
+}
+
+func compare(a *pokeapi.Pokemon, b *pokeapi.Pokemon, k sortKey) int {
+	var c int
+
+	switch k.field {
+	case "name":
+		c = strings.Compare(a.Name, b.Name)
+	case "number":
+		c = a.Number - b.Number
+	default:
+		av, aok := a.Stats[stats[k.field]]
+		bv, bok := b.Stats[stats[k.field]]
+
+		if aok != bok {
+			if aok {
+				return -1
+			}
+
+			return 1
+		}
+
+		c = av - bv
+	}
+
+	if k.desc {
+		return -c
+	}
+
+	return c
+}
+
+// selectFields returns the Pokemon with only the selected fields, or the whole Pokemon when none are
+func selectFields(pok *pokeapi.Pokemon, selected []string) (interface{}, error) {
+	if len(selected) == 0 {
+		return pok, nil
+	}
+
+	data, err := json.Marshal(pok)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal pokemon")
+	}
+
+	all := map[string]interface{}{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal pokemon")
+	}
+
+	result := make(map[string]interface{}, len(selected))
+	for _, field := range selected {
+		if v, ok := all[fields[field]]; ok {
+			result[fields[field]] = v
+		}
+	}
+
+	return result, nil
+}
+
+func sortedKeys(m map[string]*pokeapi.StatRange) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
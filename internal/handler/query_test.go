@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"pokedex/internal/handler/mocks"
+	"pokedex/pkg/adapter/pokeapi"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var queryPokemons = []*pokeapi.Pokemon{
+	{Name: "zubat", Number: 41, Habitat: "cave", Stats: map[string]int{"hp": 40, "speed": 55}},
+	{Name: "onix", Number: 95, Habitat: "cave", Stats: map[string]int{"hp": 35, "speed": 70}},
+	{Name: "mewtwo", Number: 150, Habitat: "rare", Stats: map[string]int{"hp": 106, "speed": 130}, IsLegendary: true},
+	{Name: "missingno", Number: 0},
+}
+
+func TestQueryPokemon(t *testing.T) {
+	t.Parallel()
+
+	legendary := true
+
+	type testcase struct {
+		url           string
+		query         *pokeapi.Query
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsFirstPageWhenNoParameters": {
+			url:   "/v1/pokemon",
+			query: &pokeapi.Query{},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				page := requirePage(t, rec)
+				require.Equal(t, 4, page.Count)
+				require.Equal(t, defaultLimit, page.Limit)
+				require.Len(t, page.Results, 4)
+			},
+		},
+		"PassesFiltersToRepository": {
+			url: "/v1/pokemon?habitat=cave&type=rock&generation=generation-i&legendary=true&min_hp=30&max_special_attack=90",
+			query: &pokeapi.Query{
+				Habitat:    "cave",
+				Type:       "rock",
+				Generation: "generation-i",
+				Legendary:  &legendary,
+				Stats: []pokeapi.StatRange{
+					{Stat: "hp", Min: 30, Max: math.MaxInt32},
+					{Stat: "special-attack", Min: 0, Max: 90},
+				},
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		"SortsByStatDescendingWithMissingStatsLast": {
+			url:   "/v1/pokemon?sort=-speed&fields=name",
+			query: &pokeapi.Query{},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				page := requirePage(t, rec)
+				require.Equal(t, []interface{}{
+					map[string]interface{}{"Name": "mewtwo"},
+					map[string]interface{}{"Name": "onix"},
+					map[string]interface{}{"Name": "zubat"},
+					map[string]interface{}{"Name": "missingno"},
+				}, page.Results)
+			},
+		},
+		"PaginatesAndSelectsFields": {
+			url:   "/v1/pokemon?sort=name&limit=2&offset=1&fields=name,number,is_legendary",
+			query: &pokeapi.Query{},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				page := requirePage(t, rec)
+				require.Equal(t, 4, page.Count)
+				require.Equal(t, 2, page.Limit)
+				require.Equal(t, 1, page.Offset)
+				require.Equal(t, []interface{}{
+					map[string]interface{}{"Name": "missingno", "IsLegendary": false},
+					map[string]interface{}{"Name": "onix", "Number": float64(95), "IsLegendary": false},
+				}, page.Results)
+			},
+		},
+		"ReturnsEmptyResultsPastTheLastPage": {
+			url:   "/v1/pokemon?offset=10",
+			query: &pokeapi.Query{},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				page := requirePage(t, rec)
+				require.Equal(t, 4, page.Count)
+				require.Empty(t, page.Results)
+			},
+		},
+		"ReturnsBadRequestWhenParametersInvalid": {
+			url: "/v1/pokemon?legendary=maybe&limit=1000&sort=weight&fields=secret&min_hp=90&max_hp=10&color=red&type=Fire",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				requireProblem(t, rec, "invalid_query")
+
+				for _, detail := range []string{
+					"unknown parameter color",
+					"unknown field secret",
+					"legendary must be true or false",
+					"limit must be an integer between 1 and 100",
+					"cannot sort by weight",
+					"min_hp must not be greater than max_hp",
+					"type must be a PokeAPI name",
+				} {
+					require.Contains(t, rec.Body.String(), detail)
+				}
+			},
+		},
+		"ReturnsBadRequestWhenParameterRepeated": {
+			url: "/v1/pokemon?type=fire&type=water",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+				require.Contains(t, rec.Body.String(), "type must be given once")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			repo := mocks.NewMockPokemonRepository(ctrl)
+			if tc.query != nil {
+				pokemons := make([]*pokeapi.Pokemon, len(queryPokemons))
+				copy(pokemons, queryPokemons)
+
+				repo.EXPECT().
+					Query(gomock.Any(), *tc.query).
+					Return(pokemons, nil)
+			}
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+
+			QueryPokemon(repo).ServeHTTP(rec, req)
+
+			tc.checkResponse(t, rec)
+		})
+	}
+}
+
+func TestQueryPokemon_ReturnsInternalServerErrorWhenQueryFails(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockPokemonRepository(ctrl)
+	repo.EXPECT().
+		Query(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("foo"))
+
+	rec := httptest.NewRecorder()
+	QueryPokemon(repo).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/pokemon", nil))
+
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	requireProblem(t, rec, "internal_error")
+}
+
+func requirePage(t *testing.T, rec *httptest.ResponseRecorder) *PokemonPage {
+	t.Helper()
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	page := &PokemonPage{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), page))
+
+	return page
+}
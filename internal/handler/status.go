@@ -1,8 +1,77 @@
 package handler
 
-import "net/http"
+import (
+	"net/http"
+	"pokedex/pkg/adapter/funtranslations"
+	"pokedex/pkg/health"
+	"pokedex/pkg/http/response"
+)
+
+type (
+	// Liveness reports the process is alive
+	Liveness struct {
+		Status string `json:"status"`
+	}
+
+	// Readiness is the readiness report without details, with the status of every check
+	Readiness struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+)
 
 // HealthCheck handler for returning a 200
 func HealthCheck(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(200)
 }
+
+// Live returns 200 as long as the process serves requests, it does not check dependencies
+func Live(w http.ResponseWriter, r *http.Request) {
+	_ = response.JSON(http.StatusOK, Liveness{Status: health.StatusOK}).Render(w, r)
+}
+
+// Ready runs the readiness checks and returns 503 when a required one fails. Only the status of
+// each check is returned, errors and details are left to ReadyReport as they may reveal internals
+func Ready(checker *health.Checker) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		report := checker.Run(r.Context())
+
+		res := Readiness{Status: report.Status, Checks: map[string]string{}}
+		for name, check := range report.Checks {
+			res.Checks[name] = check.Status
+		}
+
+		return response.JSON(readyCode(report), res)
+	})
+}
+
+// ReadyReport runs the readiness checks like Ready, returning the error, details and timing of
+// every check. It is meant for the admin API
+func ReadyReport(checker *health.Checker) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		report := checker.Run(r.Context())
+
+		return response.JSON(readyCode(report), report)
+	})
+}
+
+func readyCode(report health.Report) int {
+	if report.Status == health.StatusFail {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
+
+// TranslationQuota returns the usage of the translation budgets and the quota last reported by
+// Funtranslations
+func TranslationQuota(budget *funtranslations.Budget) http.HandlerFunc {
+	return response.Render(func(w http.ResponseWriter, r *http.Request) response.Renderer {
+		status, err := budget.Status(r.Context())
+		if err != nil {
+			return ErrorRenderer(err)
+		}
+
+		return response.JSON(http.StatusOK, status)
+	})
+}
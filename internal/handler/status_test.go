@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"pokedex/pkg/health"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReady(t *testing.T) {
+	t.Parallel()
+
+	checker := health.NewChecker()
+	err := checker.Register("cache", func(ctx context.Context) (interface{}, error) {
+		return "10.0.0.1:6379", errors.New("dial tcp 10.0.0.1:6379: connection refused")
+	}, health.Options{Timeout: time.Second})
+	require.NoError(t, err)
+
+	type testcase struct {
+		handler       http.HandlerFunc
+		target        string
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsCheckStatusesOnly": {
+			handler: Ready(checker),
+			target:  "/_readyz?verbose",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.JSONEq(t, `{"status":"fail","checks":{"cache":"fail"}}`, rec.Body.String())
+			},
+		},
+		"ReportReturnsErrorsAndDetails": {
+			handler: ReadyReport(checker),
+			target:  "/ready",
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var report health.Report
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+				require.Equal(t, health.StatusFail, report.Status)
+				require.Equal(t, "dial tcp 10.0.0.1:6379: connection refused", report.Checks["cache"].Error)
+				require.Equal(t, "10.0.0.1:6379", report.Checks["cache"].Details)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			tc.handler(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			tc.checkResponse(t, rec)
+		})
+	}
+}
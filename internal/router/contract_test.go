@@ -0,0 +1,558 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"pokedex/api"
+	"pokedex/internal/handler"
+	"pokedex/internal/handler/mocks"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/funtranslations"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
+	"pokedex/pkg/cache"
+	"pokedex/pkg/health"
+	"pokedex/pkg/queue"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/ghodss/yaml"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mewtwo = &pokeapi.Pokemon{
+		Name:        "mewtwo",
+		Number:      150,
+		Description: "It was created by a scientist.",
+		Habitat:     "rare",
+		Generation:  "generation-i",
+		Types:       []string{"psychic"},
+		Stats:       map[string]int{"hp": 106, "attack": 110},
+		IsLegendary: true,
+	}
+	ivysaur = &pokeapi.Pokemon{
+		Name:        "ivysaur",
+		Description: "When the bulb on its back grows large.",
+		Habitat:     "grassland",
+		EvolvesFrom: "bulbasaur",
+	}
+)
+
+// the yaml decoder of openapi3filter yields ints, which the schema validation does not handle, YAML
+// bodies are converted to JSON to be validated like JSON ones
+func init() {
+	openapi3filter.RegisterBodyDecoder("application/yaml", func(body io.Reader, _ http.Header, _ *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (interface{}, error) {
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	})
+}
+
+// TestContract validates real handler responses against the OpenAPI document
+func TestContract(t *testing.T) {
+	t.Parallel()
+
+	doc, err := api.LoadOpenAPI()
+	require.NoError(t, err)
+
+	budget := newTestBudget(t)
+	jobs, jobID := newTestJobs(t, 10)
+
+	// a queued job fills the queue of fullJobs
+	fullJobs, _ := newTestJobs(t, 1)
+	_, err = fullJobs.Submit(context.Background(), mewtwo, "")
+	require.NoError(t, err)
+
+	type testcase struct {
+		req        func(t *testing.T) *http.Request
+		fetcher    func(m *mocks.MockPokemonFetcher)
+		translator func(m *mocks.MockDescriptionTranslator)
+		repo       func(m *mocks.MockPokemonRepository)
+		checks     func(t *testing.T, c *health.Checker)
+		// jobs replaces the translation jobs when set
+		jobs   *pokemon.Jobs
+		status int
+	}
+
+	tests := map[string]testcase{
+		"GetPokemon": {
+			req: get("/v1/pokemon/mewtwo"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+			},
+			status: http.StatusOK,
+		},
+		"GetPokemonWithoutOptionalFields": {
+			req: get("/v1/pokemon/ivysaur"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "ivysaur").Return(ivysaur, nil)
+			},
+			status: http.StatusOK,
+		},
+		"GetPokemonAsYAML": {
+			req: get("/v1/pokemon/mewtwo?format=yaml"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+			},
+			status: http.StatusOK,
+		},
+		"GetPokemonNotAcceptable": {
+			req: func(t *testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/v1/pokemon/mewtwo", nil)
+				req.Header.Set("Accept", "image/png")
+
+				return req
+			},
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+			},
+			status: http.StatusNotAcceptable,
+		},
+		"GetPokemonNotFound": {
+			req: get("/v1/pokemon/missingno"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "missingno").Return(nil, nil)
+			},
+			status: http.StatusNotFound,
+		},
+		"GetPokemonInvalidName": {
+			req:    get("/v1/pokemon/-mewtwo"),
+			status: http.StatusBadRequest,
+		},
+		"GetPokemonRateLimited": {
+			req: get("/v1/pokemon/mewtwo"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, &upstream.ResponseError{
+					Kind:       upstream.ErrRateLimited,
+					StatusCode: http.StatusTooManyRequests,
+					RetryAfter: time.Minute,
+				})
+			},
+			status: http.StatusTooManyRequests,
+		},
+		"GetPokemonUpstreamUnavailable": {
+			req: get("/v1/pokemon/mewtwo"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrUnavailable)
+			},
+			status: http.StatusServiceUnavailable,
+		},
+		"GetPokemonUpstreamTimeout": {
+			req: get("/v1/pokemon/mewtwo"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrTimeout)
+			},
+			status: http.StatusGatewayTimeout,
+		},
+		"GetPokemonUpstreamBadResponse": {
+			req: get("/v1/pokemon/mewtwo"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrBadResponse)
+			},
+			status: http.StatusBadGateway,
+		},
+		"GetPokemonInternalError": {
+			req: get("/v1/pokemon/mewtwo"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, context.Canceled)
+			},
+			status: http.StatusInternalServerError,
+		},
+		"GetTranslatedPokemon": {
+			req: get("/v1/pokemon/translated/mewtwo"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+			},
+			translator: func(m *mocks.MockDescriptionTranslator) {
+				m.EXPECT().TranslateDescription(gomock.Any(), gomock.Any()).Return(pokemon.TranslationResult{
+					Description: "Created by a scientist, it was.",
+					Translation: pokemon.Translation{Style: pokemon.StyleYoda, Succeeded: true, Cached: true},
+				})
+			},
+			status: http.StatusOK,
+		},
+		"GetTranslatedPokemonFallback": {
+			req: get("/v1/pokemon/translated/ivysaur"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "ivysaur").Return(ivysaur, nil)
+			},
+			translator: func(m *mocks.MockDescriptionTranslator) {
+				m.EXPECT().TranslateDescription(gomock.Any(), gomock.Any()).Return(pokemon.TranslationResult{
+					Description: ivysaur.Description,
+					Translation: pokemon.Translation{Style: pokemon.StyleShakespeare, FallbackReason: pokemon.FallbackRateLimited},
+				})
+			},
+			status: http.StatusOK,
+		},
+		"QueryPokemon": {
+			req: get("/v1/pokemon?habitat=rare&sort=-hp"),
+			repo: func(m *mocks.MockPokemonRepository) {
+				m.EXPECT().Query(gomock.Any(), gomock.Any()).Return([]*pokeapi.Pokemon{mewtwo, ivysaur}, nil)
+			},
+			status: http.StatusOK,
+		},
+		"QueryPokemonFields": {
+			req: get("/v1/pokemon?fields=name,types,evolves_from"),
+			repo: func(m *mocks.MockPokemonRepository) {
+				m.EXPECT().Query(gomock.Any(), gomock.Any()).Return([]*pokeapi.Pokemon{mewtwo, ivysaur}, nil)
+			},
+			status: http.StatusOK,
+		},
+		"QueryPokemonInvalid": {
+			req:    get("/v1/pokemon?limit=1000&color=red"),
+			status: http.StatusBadRequest,
+		},
+		"HealthCheck": {
+			req:    get("/_healthcheck"),
+			status: http.StatusOK,
+		},
+		"Live": {
+			req:    get("/_livez"),
+			status: http.StatusOK,
+		},
+		"Ready": {
+			req:    get("/_readyz"),
+			checks: registerChecks(nil, errors.New("quota exhausted")),
+			status: http.StatusOK,
+		},
+		"ReadyIgnoresVerbose": {
+			req:    get("/_readyz?verbose"),
+			checks: registerChecks(nil, errors.New("quota exhausted")),
+			status: http.StatusOK,
+		},
+		"NotReady": {
+			req:    get("/_readyz"),
+			checks: registerChecks(errors.New("warming up, 1 of 10 names processed"), nil),
+			status: http.StatusServiceUnavailable,
+		},
+		"NotReadyIgnoresVerbose": {
+			req:    get("/_readyz?verbose=true"),
+			checks: registerChecks(errors.New("warming up, 1 of 10 names processed"), nil),
+			status: http.StatusServiceUnavailable,
+		},
+		"GetTranslatedPokemonAsync": {
+			req: get("/v1/pokemon/translated/mewtwo?async&callback_url=https://hooks.example.com/pokemon"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+			},
+			status: http.StatusAccepted,
+		},
+		"GetTranslatedPokemonAsyncQueueFull": {
+			req: get("/v1/pokemon/translated/mewtwo?async"),
+			fetcher: func(m *mocks.MockPokemonFetcher) {
+				m.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+			},
+			jobs:   fullJobs,
+			status: http.StatusServiceUnavailable,
+		},
+		"GetJob": {
+			req:    get("/v1/jobs/" + jobID),
+			status: http.StatusOK,
+		},
+		"GetJobNotFound": {
+			req:    get("/v1/jobs/missing"),
+			status: http.StatusNotFound,
+		},
+		"TranslationQuota": {
+			req:    get("/_quota"),
+			status: http.StatusOK,
+		},
+	}
+
+	for name, testCase := range tests {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fetcher := mocks.NewMockPokemonFetcher(ctrl)
+			if tc.fetcher != nil {
+				tc.fetcher(fetcher)
+			}
+
+			translator := mocks.NewMockDescriptionTranslator(ctrl)
+			if tc.translator != nil {
+				tc.translator(translator)
+			}
+
+			repo := mocks.NewMockPokemonRepository(ctrl)
+			if tc.repo != nil {
+				tc.repo(repo)
+			}
+
+			checker := health.NewChecker()
+			if tc.checks != nil {
+				tc.checks(t, checker)
+			}
+
+			jobs := jobs
+			if tc.jobs != nil {
+				jobs = tc.jobs
+			}
+
+			r := New(Handlers{
+				GetPokemonByName:           handler.GetPokemonByName(fetcher),
+				GetPokemonByNameTranslated: handler.TranslateAsync(fetcher, jobs, handler.GetPokemonByNameTranslated(fetcher, translator)),
+				QueryPokemon:               handler.QueryPokemon(repo),
+				GetJob:                     handler.GetJob(jobs),
+				HealthCheck:                handler.HealthCheck,
+				Live:                       handler.Live,
+				Ready:                      handler.Ready(checker),
+				Quota:                      handler.TranslationQuota(budget),
+			}, Options{
+				PokemonMaxAge:           func() time.Duration { return time.Hour },
+				PokemonTranslatedMaxAge: func() time.Duration { return time.Minute },
+			})
+
+			req := tc.req(t)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.status, rec.Code, rec.Body.String())
+			requireValidResponse(t, doc, req, rec)
+		})
+	}
+}
+
+// registerChecks registers a required warmup check and an optional funtranslations check failing
+// with the given errors
+func registerChecks(warmupErr error, funtranslationsErr error) func(t *testing.T, c *health.Checker) {
+	return func(t *testing.T, c *health.Checker) {
+		err := c.Register("warmup", func(ctx context.Context) (interface{}, error) {
+			return pokemon.WarmupStatus{Started: true, Requested: 10, Warmed: 1}, warmupErr
+		}, health.Options{Timeout: time.Second})
+		require.NoError(t, err)
+
+		err = c.Register("funtranslations", func(ctx context.Context) (interface{}, error) {
+			return nil, funtranslationsErr
+		}, health.Options{Timeout: time.Second, Optional: true})
+		require.NoError(t, err)
+	}
+}
+
+// newTestBudget returns a Budget with both budgets partly used and a quota reported by Funtranslations
+func newTestBudget(t *testing.T) *funtranslations.Budget {
+	usage, err := cache.NewMemory(10)
+	require.NoError(t, err)
+
+	budget, err := funtranslations.NewBudget(&stubTranslator{}, usage, funtranslations.BudgetOptions{
+		Hourly:  5,
+		Daily:   60,
+		Reserve: 0.1,
+		Quota: func() (funtranslations.Quota, bool) {
+			return funtranslations.Quota{Limit: 60, Remaining: 12, UpdatedAt: time.Now()}, true
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = budget.TranslateToYoda(context.Background(), "text")
+	require.NoError(t, err)
+
+	return budget
+}
+
+// newTestJobs returns translation jobs, which are not processed, with one completed job
+func newTestJobs(t *testing.T, maxPending int) (*pokemon.Jobs, string) {
+	q, err := queue.Open(filepath.Join(t.TempDir(), "jobs.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = q.Close() })
+
+	jobs, err := pokemon.NewJobs(q, mocks.NewMockDescriptionTranslator(gomock.NewController(t)), pokemon.JobOptions{
+		Workers:         1,
+		MaxPending:      maxPending,
+		MaxAttempts:     1,
+		RetryDelay:      time.Second,
+		Retention:       time.Hour,
+		CallbackHosts:   []string{"hooks.example.com"},
+		CallbackTimeout: time.Second,
+	})
+	require.NoError(t, err)
+
+	job, err := jobs.Submit(context.Background(), mewtwo, "")
+	require.NoError(t, err)
+
+	task, err := q.Claim(context.Background())
+	require.NoError(t, err)
+
+	translated := *mewtwo
+	translated.Description = "Created by a scientist, it was."
+
+	err = q.Complete(context.Background(), task.ID, pokemon.JobResult{
+		Pokemon:     &translated,
+		Translation: pokemon.Translation{Style: pokemon.StyleYoda, Succeeded: true},
+	})
+	require.NoError(t, err)
+
+	return jobs, job.ID
+}
+
+// stubTranslator returns the text untranslated
+type stubTranslator struct{}
+
+func (stubTranslator) TranslateToYoda(ctx context.Context, text string) (string, error) {
+	return text, nil
+}
+
+func (stubTranslator) TranslateToShakespeare(ctx context.Context, text string) (string, error) {
+	return text, nil
+}
+
+// TestContract_Routes checks every REST route is documented and every documented operation is
+// routed, the GraphQL endpoint and the documentation itself are not part of the document
+func TestContract_Routes(t *testing.T) {
+	t.Parallel()
+
+	doc, err := api.LoadOpenAPI()
+	require.NoError(t, err)
+
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	r := New(Handlers{
+		GetPokemonByName:           noop,
+		GetPokemonByNameTranslated: noop,
+		QueryPokemon:               noop,
+		GetJob:                     noop,
+		HealthCheck:                noop,
+		Live:                       noop,
+		Ready:                      noop,
+		Quota:                      noop,
+		GraphQL:                    noop,
+		OpenAPI:                    noop,
+		Docs:                       noop,
+	}, Options{})
+
+	undocumented := map[string]bool{"/graphql": true, "/openapi.json": true, "/docs": true, "/docs/*": true}
+
+	var routed []string
+	err = chi.Walk(r, func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(route, "/")
+		if route == "" || undocumented[route] {
+			return nil
+		}
+
+		routed = append(routed, method+" "+route)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	var documented []string
+	for path, item := range doc.Paths {
+		for method := range item.Operations() {
+			documented = append(documented, method+" "+path)
+		}
+	}
+
+	sort.Strings(routed)
+	sort.Strings(documented)
+
+	require.Equal(t, documented, routed)
+}
+
+// TestContract_Documentation checks the served document is the embedded one and the Swagger UI
+// page and assets are served
+func TestContract_Documentation(t *testing.T) {
+	t.Parallel()
+
+	doc, err := api.LoadOpenAPI()
+	require.NoError(t, err)
+
+	r := New(Handlers{
+		OpenAPI: handler.OpenAPI(doc),
+		Docs:    handler.Docs("/docs", "/openapi.json"),
+	}, Options{})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	served, err := openapi3.NewLoader().LoadFromData(rec.Body.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, served.Validate(context.Background()))
+	require.Equal(t, doc.Info.Version, served.Info.Version)
+	require.Len(t, served.Paths, len(doc.Paths))
+
+	for _, target := range []string{"/docs", "/docs/", "/docs/index.html"} {
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, rec.Code, target)
+		require.Contains(t, rec.Header().Get("Content-Type"), "text/html", target)
+		require.Contains(t, rec.Body.String(), `url: "/openapi.json"`, target)
+	}
+
+	// assets are served through a real server as the file server needs a response writer
+	// implementing io.ReaderFrom, which the recorder does not
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	res, err := srv.Client().Get(srv.URL + "/docs/swagger-ui.css")
+	require.NoError(t, err)
+
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Contains(t, res.Header.Get("Content-Type"), "text/css")
+}
+
+func get(target string) func(t *testing.T) *http.Request {
+	return func(t *testing.T) *http.Request {
+		return httptest.NewRequest(http.MethodGet, target, nil)
+	}
+}
+
+func requireValidResponse(t *testing.T, doc *openapi3.T, req *http.Request, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	docRouter, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	route, pathParams, err := docRouter.FindRoute(req)
+	require.NoError(t, err)
+
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: rec.Code,
+		Header: rec.Header(),
+		Body:   ioutil.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+		},
+	}
+
+	require.NoError(t, openapi3filter.ValidateResponse(context.Background(), input), rec.Body.String())
+}
@@ -2,7 +2,10 @@ package router
 
 import (
 	"net/http"
+	"pokedex/internal/handler"
 	"pokedex/pkg/http/middleware"
+	"pokedex/pkg/secret"
+	"time"
 
 	"github.com/go-chi/chi"
 )
@@ -13,21 +16,126 @@ import (
 type Handlers struct {
 	GetPokemonByName           http.HandlerFunc
 	GetPokemonByNameTranslated http.HandlerFunc
+	// QueryPokemon is optional, it needs the local Pokemon store
+	QueryPokemon http.HandlerFunc
+	// GetJob is optional, it serves the translation jobs submitted with ?async
+	GetJob http.HandlerFunc
+	// GraphQL is optional, it serves queries on GET and POST
+	GraphQL http.HandlerFunc
+	// OpenAPI serves the OpenAPI document and Docs the Swagger UI for it, both are optional
+	OpenAPI http.HandlerFunc
+	Docs    http.HandlerFunc
 
 	HealthCheck http.HandlerFunc
+	// Live and Ready are the liveness and readiness probes, both are optional
+	Live  http.HandlerFunc
+	Ready http.HandlerFunc
+	// Quota is optional, it needs the translation budget
+	Quota http.HandlerFunc
+
+	// Admin is served by NewAdmin on its own port
+	Admin AdminHandlers
+}
+
+// AdminHandlers holds http.HandlerFuncs for the admin endpoints
+type AdminHandlers struct {
+	// Ready is the detailed readiness report
+	Ready       http.HandlerFunc
+	CacheStats  http.HandlerFunc
+	CacheLookup http.HandlerFunc
+	CachePurge  http.HandlerFunc
+	CacheWarmup http.HandlerFunc
+}
+
+// AdminOptions holds the admin router settings, Token is read on every request so it can be rotated
+type AdminOptions struct {
+	Token func() secret.Source
+}
+
+// Options holds per route settings that are not part of the handlers themselves, they are read on
+// every request so they can change at runtime
+type Options struct {
+	PokemonMaxAge           func() time.Duration
+	PokemonTranslatedMaxAge func() time.Duration
+	// PokemonLastModified is optional, it is when the served Pokemons last changed, e.g. the time of
+	// the snapshot they are served from. Translated Pokemons change with their translation, it does
+	// not apply to them
+	PokemonLastModified func() time.Time
 }
 
 // New constructs a new router
-func New(handlers Handlers) *chi.Mux {
+func New(handlers Handlers, opts Options) *chi.Mux {
 	router := chi.NewRouter()
 
 	middleware.Common(router)
 
 	router.Get("/_healthcheck", handlers.HealthCheck)
 
+	if handlers.Live != nil {
+		router.Get("/_livez", handlers.Live)
+	}
+
+	if handlers.Ready != nil {
+		router.Get("/_readyz", handlers.Ready)
+	}
+
+	if handlers.Quota != nil {
+		router.Get("/_quota", handlers.Quota)
+	}
+
+	if handlers.OpenAPI != nil {
+		router.Get("/openapi.json", handlers.OpenAPI)
+	}
+
+	if handlers.Docs != nil {
+		router.Get("/docs", handlers.Docs)
+		router.Get("/docs/*", handlers.Docs)
+	}
+
+	if handlers.GraphQL != nil {
+		router.Get("/graphql", handlers.GraphQL)
+		router.Post("/graphql", handlers.GraphQL)
+	}
+
+	pokemon := []func(http.Handler) http.Handler{middleware.CacheControlFunc(opts.PokemonMaxAge)}
+	if opts.PokemonLastModified != nil {
+		pokemon = append(pokemon, middleware.LastModifiedFunc(opts.PokemonLastModified))
+	}
+
 	router.Route("/v1/pokemon", func(r chi.Router) {
-		r.Get("/{name}", handlers.GetPokemonByName)
-		r.Get("/translated/{name}", handlers.GetPokemonByNameTranslated)
+		if handlers.QueryPokemon != nil {
+			r.With(pokemon...).
+				Get("/", handlers.QueryPokemon)
+		}
+		r.With(pokemon...).
+			Get("/{name}", handlers.GetPokemonByName)
+		r.With(middleware.CacheControlFunc(opts.PokemonTranslatedMaxAge)).
+			Get("/translated/{name}", handlers.GetPokemonByNameTranslated)
+	})
+
+	if handlers.GetJob != nil {
+		router.Get("/v1/jobs/{id}", handlers.GetJob)
+	}
+
+	return router
+}
+
+// NewAdmin constructs the admin router, every route requires the admin token
+func NewAdmin(handlers Handlers, opts AdminOptions) *chi.Mux {
+	router := chi.NewRouter()
+
+	middleware.Common(router)
+
+	router.Get("/_healthcheck", handlers.HealthCheck)
+
+	router.Group(func(r chi.Router) {
+		r.Use(handler.RequireToken(opts.Token))
+
+		r.Get("/ready", handlers.Admin.Ready)
+		r.Get("/cache", handlers.Admin.CacheStats)
+		r.Delete("/cache", handlers.Admin.CachePurge)
+		r.Get("/cache/pokemon/{name}", handlers.Admin.CacheLookup)
+		r.Post("/cache/warmup", handlers.Admin.CacheWarmup)
 	})
 
 	return router
@@ -0,0 +1,145 @@
+package pokemon
+
+import (
+	"context"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/cache"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// translationCacheVersion is the version of the cached translation serialisation
+const translationCacheVersion = 1
+
+// CachedTranslator caches successful translations by style and Pokemon name for ttl, fallbacks to
+// the original description are not cached so they are retried
+type CachedTranslator struct {
+	next  DescriptionTranslator
+	store *cache.Store
+
+	mu  sync.RWMutex
+	ttl time.Duration
+}
+
+// NewCachedTranslator creates a new CachedTranslator in front of next
+func NewCachedTranslator(next DescriptionTranslator, backend cache.Backend, ttl time.Duration) (*CachedTranslator, error) {
+	if next == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "next")
+	}
+
+	if backend == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "backend")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "ttl")
+	}
+
+	store, err := cache.NewStore(backend, "translation", translationCacheVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachedTranslator{
+		next:  next,
+		store: store,
+		ttl:   ttl,
+	}, nil
+}
+
+// TranslateDescription translates description of a given Pokemon, Translation.Cached is set when
+// the translation was served from cache
+func (c *CachedTranslator) TranslateDescription(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+	style := Style(pok)
+	key := translationKey(style, pok.Name)
+
+	var description string
+	if c.store.Get(ctx, key, &description) {
+		return TranslationResult{
+			Description: description,
+			Translation: Translation{
+				Style:     style,
+				Succeeded: true,
+				Cached:    true,
+			},
+		}
+	}
+
+	res := c.next.TranslateDescription(ctx, pok)
+	if res.Translation.Succeeded {
+		c.store.Set(ctx, key, res.Description, c.getTTL())
+	}
+
+	return res
+}
+
+// SetTTL changes how long translations cached afterwards are kept
+func (c *CachedTranslator) SetTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.Wrap(ErrInvalidParam, "ttl")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = ttl
+
+	return nil
+}
+
+func (c *CachedTranslator) getTTL() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.ttl
+}
+
+// Lookup returns the cached translations of a Pokemon by style
+func (c *CachedTranslator) Lookup(ctx context.Context, name string) map[string]string {
+	translations := map[string]string{}
+
+	for _, style := range []string{StyleYoda, StyleShakespeare} {
+		var description string
+		if c.store.Peek(ctx, translationKey(style, name), &description) {
+			translations[style] = description
+		}
+	}
+
+	return translations
+}
+
+// Purge removes the cached translations whose style and Pokemon name match, it returns the number
+// removed
+func (c *CachedTranslator) Purge(ctx context.Context, match func(style string, name string) bool) int {
+	return c.store.Delete(ctx, func(key string) bool {
+		style, name := splitTranslationKey(key)
+
+		return match(style, name)
+	})
+}
+
+// Stats returns the content and usage of the cache
+func (c *CachedTranslator) Stats(ctx context.Context) cache.Stats {
+	return c.store.Stats(ctx)
+}
+
+// Ping checks the cache backend is reachable
+func (c *CachedTranslator) Ping(ctx context.Context) error {
+	return c.store.Ping(ctx)
+}
+
+func translationKey(style string, name string) string {
+	return style + ":" + name
+}
+
+func splitTranslationKey(key string) (string, string) {
+	parts := strings.SplitN(key, ":", 2)
+	if len(parts) != 2 {
+		return "", key
+	}
+
+	return parts[0], parts[1]
+}
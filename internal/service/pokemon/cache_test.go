@@ -0,0 +1,170 @@
+package pokemon
+
+import (
+	"context"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/cache"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type translatorFunc func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult
+
+func (fn translatorFunc) TranslateDescription(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+	return fn(ctx, pok)
+}
+
+func TestNewCachedTranslator_Error(t *testing.T) {
+	t.Parallel()
+
+	store, err := cache.NewMemory(10)
+	require.NoError(t, err)
+
+	next := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		return TranslationResult{}
+	})
+
+	tests := map[string]struct {
+		next    DescriptionTranslator
+		backend cache.Backend
+		ttl     time.Duration
+		err     string
+	}{
+		"ReturnsErrorWhenNextNil": {
+			backend: store,
+			ttl:     time.Hour,
+			err:     "next: invalid parameter",
+		},
+		"ReturnsErrorWhenBackendNil": {
+			next: next,
+			ttl:  time.Hour,
+			err:  "backend: invalid parameter",
+		},
+		"ReturnsErrorWhenTTLNotPositive": {
+			next:    next,
+			backend: store,
+			err:     "ttl: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCachedTranslator(tc.next, tc.backend, tc.ttl)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, c)
+		})
+	}
+}
+
+func TestCachedTranslator(t *testing.T) {
+	t.Parallel()
+
+	mewtwo := &pokeapi.Pokemon{Name: "mewtwo", Description: "original", IsLegendary: true}
+	eevee := &pokeapi.Pokemon{Name: "eevee", Description: "original"}
+
+	type testcase struct {
+		succeeded bool
+		run       func(t *testing.T, c *CachedTranslator, calls *int)
+	}
+
+	tests := map[string]testcase{
+		"ServesSuccessfulTranslationsFromCache": {
+			succeeded: true,
+			run: func(t *testing.T, c *CachedTranslator, calls *int) {
+				res := c.TranslateDescription(context.Background(), mewtwo)
+				require.Equal(t, TranslationResult{
+					Description: "translated mewtwo",
+					Translation: Translation{Style: StyleYoda, Succeeded: true},
+				}, res)
+
+				res = c.TranslateDescription(context.Background(), mewtwo)
+				require.Equal(t, TranslationResult{
+					Description: "translated mewtwo",
+					Translation: Translation{Style: StyleYoda, Succeeded: true, Cached: true},
+				}, res)
+
+				require.Equal(t, 1, *calls)
+				require.Equal(t, map[string]string{StyleYoda: "translated mewtwo"}, c.Lookup(context.Background(), "mewtwo"))
+			},
+		},
+		"DoesNotCacheFallbacks": {
+			run: func(t *testing.T, c *CachedTranslator, calls *int) {
+				for i := 0; i < 2; i++ {
+					res := c.TranslateDescription(context.Background(), mewtwo)
+					require.Equal(t, "original", res.Description)
+					require.False(t, res.Translation.Cached)
+				}
+
+				require.Equal(t, 2, *calls)
+				require.Empty(t, c.Lookup(context.Background(), "mewtwo"))
+			},
+		},
+		"CachesForTTLSetAfterwards": {
+			succeeded: true,
+			run: func(t *testing.T, c *CachedTranslator, calls *int) {
+				require.EqualError(t, c.SetTTL(0), "ttl: invalid parameter")
+				require.NoError(t, c.SetTTL(time.Millisecond))
+
+				c.TranslateDescription(context.Background(), mewtwo)
+				time.Sleep(5 * time.Millisecond)
+
+				res := c.TranslateDescription(context.Background(), mewtwo)
+				require.False(t, res.Translation.Cached)
+				require.Equal(t, 2, *calls)
+			},
+		},
+		"PurgesMatchingTranslations": {
+			succeeded: true,
+			run: func(t *testing.T, c *CachedTranslator, calls *int) {
+				c.TranslateDescription(context.Background(), mewtwo)
+				c.TranslateDescription(context.Background(), eevee)
+
+				n := c.Purge(context.Background(), func(style string, name string) bool { return style == StyleShakespeare })
+				require.Equal(t, 1, n)
+
+				require.Empty(t, c.Lookup(context.Background(), "eevee"))
+				require.NotEmpty(t, c.Lookup(context.Background(), "mewtwo"))
+				require.Equal(t, 1, c.Stats(context.Background()).Entries)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			store, err := cache.NewMemory(10)
+			require.NoError(t, err)
+
+			calls := 0
+			next := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+				calls++
+
+				if !tc.succeeded {
+					return TranslationResult{
+						Description: pok.Description,
+						Translation: Translation{Style: Style(pok), FallbackReason: FallbackUpstreamError},
+					}
+				}
+
+				return TranslationResult{
+					Description: "translated " + pok.Name,
+					Translation: Translation{Style: Style(pok), Succeeded: true},
+				}
+			})
+
+			c, err := NewCachedTranslator(next, store, time.Hour)
+			require.NoError(t, err)
+
+			tc.run(t, c, &calls)
+		})
+	}
+}
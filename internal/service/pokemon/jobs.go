@@ -0,0 +1,422 @@
+package pokemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/queue"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// Job statuses
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+)
+
+const (
+	// callbackAttempts is the number of times a callback is sent before giving up
+	callbackAttempts = 3
+	callbackBackoff  = time.Second
+
+	// claimBackoff is the wait after the queue failed to return a job
+	claimBackoff = time.Second
+	// purgeInterval is how often completed jobs past their retention are removed
+	purgeInterval = 10 * time.Minute
+)
+
+var (
+	// ErrInvalidCallback is returned when a callback URL is not allowed
+	ErrInvalidCallback = errors.New("invalid callback url")
+	// ErrQueueFull is returned instead of submitting a job while MaxPending jobs are queued
+	ErrQueueFull = errors.New("translation job queue full")
+)
+
+type (
+	// JobOptions configures the translation jobs
+	JobOptions struct {
+		// Workers is the number of jobs translated concurrently
+		Workers int
+		// MaxPending is the number of queued jobs, including the ones waiting to be retried, over
+		// which submitted jobs are rejected
+		MaxPending int
+		// MaxAttempts is the number of times a rate limited translation is attempted, RetryDelay the
+		// wait between attempts. The job completes with the original description after the last one
+		MaxAttempts int
+		RetryDelay  time.Duration
+		// Retention is how long completed jobs can be looked up
+		Retention time.Duration
+		// CallbackHosts lists the hosts callbacks can be sent to, callbacks are rejected when empty
+		CallbackHosts []string
+		// CallbackTimeout bounds every callback attempt
+		CallbackTimeout time.Duration
+	}
+
+	// Jobs translates descriptions in the background. Jobs are persisted in a queue processed by a
+	// pool of workers, they are looked up by ID and their result is posted to the callback URL given
+	// when they were submitted
+	Jobs struct {
+		queue      *queue.Queue
+		translator DescriptionTranslator
+		opts       JobOptions
+		hosts      map[string]bool
+		client     *http.Client
+		now        func() time.Time
+	}
+
+	// Job is a translation job, Result is set once it is completed
+	Job struct {
+		ID        string     `json:"id"`
+		Status    string     `json:"status"`
+		Name      string     `json:"name"`
+		Attempts  int        `json:"attempts"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt time.Time  `json:"updated_at"`
+		Result    *JobResult `json:"result,omitempty"`
+	}
+
+	// JobResult is a Pokemon with its translated description and details about the translation
+	JobResult struct {
+		*pokeapi.Pokemon
+		Translation Translation `json:"translation"`
+	}
+
+	// jobPayload is the queued work of a job
+	jobPayload struct {
+		Pokemon  *pokeapi.Pokemon `json:"pokemon"`
+		Callback string           `json:"callback,omitempty"`
+	}
+)
+
+// NewJobs creates new translation jobs kept in q
+func NewJobs(q *queue.Queue, translator DescriptionTranslator, opts JobOptions) (*Jobs, error) {
+	if q == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "queue")
+	}
+
+	if translator == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "translator")
+	}
+
+	if opts.Workers < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "workers")
+	}
+
+	if opts.MaxPending < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "max pending")
+	}
+
+	if opts.MaxAttempts < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "max attempts")
+	}
+
+	if opts.RetryDelay <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "retry delay")
+	}
+
+	if opts.Retention <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "retention")
+	}
+
+	if opts.CallbackTimeout <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "callback timeout")
+	}
+
+	hosts := map[string]bool{}
+	for _, host := range opts.CallbackHosts {
+		hosts[host] = true
+	}
+
+	return &Jobs{
+		queue:      q,
+		translator: translator,
+		opts:       opts,
+		hosts:      hosts,
+		client: &http.Client{
+			Timeout: opts.CallbackTimeout,
+			// a redirect could send the callback to a host that is not allowed
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}, nil
+}
+
+// Submit queues the translation of the description of pok, the result is posted to callback once
+// the job is completed unless it is empty. ErrQueueFull is returned while too many jobs are queued
+func (j *Jobs) Submit(ctx context.Context, pok *pokeapi.Pokemon, callback string) (Job, error) {
+	if callback != "" {
+		if err := j.validCallback(callback); err != nil {
+			return Job{}, err
+		}
+	}
+
+	task, err := j.queue.PushBounded(ctx, jobPayload{Pokemon: pok, Callback: callback}, j.opts.MaxPending)
+	if errors.Is(err, queue.ErrFull) {
+		return Job{}, ErrQueueFull
+	}
+
+	if err != nil {
+		return Job{}, errors.Wrap(err, "unable to queue translation job")
+	}
+
+	return job(task)
+}
+
+// Get returns the job with the given ID, false when there is none or it is past its retention
+func (j *Jobs) Get(ctx context.Context, id string) (Job, bool, error) {
+	task, ok, err := j.queue.Get(ctx, id)
+	if err != nil || !ok {
+		return Job{}, false, err
+	}
+
+	res, err := job(task)
+	if err != nil {
+		return Job{}, false, err
+	}
+
+	return res, true, nil
+}
+
+// Run processes jobs with the configured number of workers and removes the completed jobs past
+// their retention, it returns once ctx is done. Jobs interrupted by ctx are processed again when
+// the queue is reopened
+func (j *Jobs) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < j.opts.Workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			j.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+
+			return
+		case <-ticker.C:
+			j.purge(ctx)
+		}
+	}
+}
+
+func (j *Jobs) work(ctx context.Context) {
+	for {
+		task, err := j.queue.Claim(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			log.Error().Err(err).Msg("failed to claim translation job")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(claimBackoff):
+			}
+
+			continue
+		}
+
+		j.process(ctx, task)
+	}
+}
+
+// process translates the description of a job, a rate limited translation is retried later until
+// the last attempt
+func (j *Jobs) process(ctx context.Context, task queue.Task) {
+	logger := log.With().Str("job", task.ID).Int("attempt", task.Attempts).Logger()
+
+	var payload jobPayload
+	if err := json.Unmarshal(task.Payload, &payload); err != nil || payload.Pokemon == nil {
+		logger.Error().Err(err).Msg("dropping invalid translation job")
+
+		if err := j.queue.Complete(ctx, task.ID, nil); err != nil {
+			logger.Error().Err(err).Msg("failed to complete translation job")
+		}
+
+		return
+	}
+
+	res := j.translator.TranslateDescription(ctx, payload.Pokemon)
+
+	// the job is left running and processed again once the queue is reopened
+	if ctx.Err() != nil {
+		return
+	}
+
+	if res.Translation.FallbackReason == FallbackRateLimited && task.Attempts < j.opts.MaxAttempts {
+		if err := j.queue.Retry(ctx, task.ID, j.now().Add(j.opts.RetryDelay)); err != nil {
+			logger.Error().Err(err).Msg("failed to retry translation job")
+		}
+
+		return
+	}
+
+	pok := *payload.Pokemon
+	pok.Description = res.Description
+
+	result := &JobResult{Pokemon: &pok, Translation: res.Translation}
+	if err := j.queue.Complete(ctx, task.ID, result); err != nil {
+		logger.Error().Err(err).Msg("failed to complete translation job")
+
+		return
+	}
+
+	logger.Info().Bool("succeeded", res.Translation.Succeeded).Msg("completed translation job")
+
+	if payload.Callback == "" {
+		return
+	}
+
+	completed, ok, err := j.Get(ctx, task.ID)
+	if err != nil || !ok {
+		logger.Error().Err(err).Msg("failed to read completed translation job")
+
+		return
+	}
+
+	j.notify(ctx, payload.Callback, completed)
+}
+
+// notify posts the completed job to callback, retrying transport errors and 5xx responses
+func (j *Jobs) notify(ctx context.Context, callback string, job Job) {
+	body, err := json.Marshal(job)
+	if err != nil {
+		log.Error().Err(err).Str("job", job.ID).Msg("failed to encode translation job")
+
+		return
+	}
+
+	wait := callbackBackoff
+
+	for attempt := 1; ; attempt++ {
+		status, err := j.post(ctx, callback, body)
+		if err == nil && status < http.StatusInternalServerError {
+			if status >= http.StatusBadRequest {
+				log.Warn().Str("job", job.ID).Int("status", status).Msg("translation job callback rejected")
+			}
+
+			return
+		}
+
+		if attempt >= callbackAttempts {
+			log.Warn().Err(err).Str("job", job.ID).Int("status", status).Msg("failed to send translation job callback")
+
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		wait *= 2
+	}
+}
+
+func (j *Jobs) post(ctx context.Context, callback string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callback, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := j.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	return res.StatusCode, nil
+}
+
+func (j *Jobs) purge(ctx context.Context) {
+	n, err := j.queue.Purge(ctx, j.now().Add(-j.opts.Retention))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to purge translation jobs")
+
+		return
+	}
+
+	if n > 0 {
+		log.Info().Int("jobs", n).Msg("purged translation jobs")
+	}
+}
+
+// validCallback checks callback is an absolute http(s) URL to one of the allowed hosts, so the
+// service can not be used to send requests to arbitrary hosts
+func (j *Jobs) validCallback(callback string) error {
+	if len(j.hosts) == 0 {
+		return errors.Wrap(ErrInvalidCallback, "callbacks are not enabled")
+	}
+
+	u, err := url.Parse(callback)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Wrap(ErrInvalidCallback, "not an absolute http(s) url")
+	}
+
+	if !j.hosts[u.Hostname()] {
+		return errors.Wrapf(ErrInvalidCallback, "host %s is not allowed", u.Hostname())
+	}
+
+	return nil
+}
+
+// job returns the job of a queued task
+func job(task queue.Task) (Job, error) {
+	var payload jobPayload
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return Job{}, errors.Wrapf(err, "unable to decode translation job %s", task.ID)
+	}
+
+	res := Job{
+		ID:        task.ID,
+		Attempts:  task.Attempts,
+		CreatedAt: task.CreatedAt,
+		UpdatedAt: task.UpdatedAt,
+	}
+
+	if payload.Pokemon != nil {
+		res.Name = payload.Pokemon.Name
+	}
+
+	switch task.State {
+	case queue.StatePending:
+		res.Status = JobQueued
+	case queue.StateRunning:
+		res.Status = JobRunning
+	case queue.StateDone:
+		res.Status = JobCompleted
+	}
+
+	if len(task.Result) > 0 && string(task.Result) != "null" {
+		res.Result = &JobResult{}
+		if err := json.Unmarshal(task.Result, res.Result); err != nil {
+			return Job{}, errors.Wrapf(err, "unable to decode translation job %s result", task.ID)
+		}
+	}
+
+	return res, nil
+}
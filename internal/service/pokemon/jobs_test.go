@@ -0,0 +1,332 @@
+package pokemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/queue"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var jobOptions = JobOptions{
+	Workers:         2,
+	MaxPending:      10,
+	MaxAttempts:     2,
+	RetryDelay:      10 * time.Millisecond,
+	Retention:       time.Hour,
+	CallbackHosts:   []string{"127.0.0.1"},
+	CallbackTimeout: time.Second,
+}
+
+func openJobQueue(t *testing.T) *queue.Queue {
+	t.Helper()
+
+	q, err := queue.Open(filepath.Join(t.TempDir(), "jobs.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = q.Close() })
+
+	return q
+}
+
+// requireCompleted waits for the job to complete and returns it
+func requireCompleted(t *testing.T, jobs *Jobs, id string) Job {
+	t.Helper()
+
+	var job Job
+
+	require.Eventually(t, func() bool {
+		var (
+			ok  bool
+			err error
+		)
+
+		job, ok, err = jobs.Get(context.Background(), id)
+
+		return err == nil && ok && job.Status == JobCompleted
+	}, time.Second, time.Millisecond)
+
+	return job
+}
+
+func TestNewJobs_Error(t *testing.T) {
+	t.Parallel()
+
+	q := openJobQueue(t)
+	translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		return TranslationResult{}
+	})
+
+	tests := map[string]struct {
+		queue      *queue.Queue
+		translator DescriptionTranslator
+		opts       func(o JobOptions) JobOptions
+		err        string
+	}{
+		"ReturnsErrorWhenQueueNil": {
+			translator: translator,
+			err:        "queue: invalid parameter",
+		},
+		"ReturnsErrorWhenTranslatorNil": {
+			queue: q,
+			err:   "translator: invalid parameter",
+		},
+		"ReturnsErrorWhenWorkersNotPositive": {
+			queue:      q,
+			translator: translator,
+			opts:       func(o JobOptions) JobOptions { o.Workers = 0; return o },
+			err:        "workers: invalid parameter",
+		},
+		"ReturnsErrorWhenMaxPendingNotPositive": {
+			queue:      q,
+			translator: translator,
+			opts:       func(o JobOptions) JobOptions { o.MaxPending = 0; return o },
+			err:        "max pending: invalid parameter",
+		},
+		"ReturnsErrorWhenRetentionNotPositive": {
+			queue:      q,
+			translator: translator,
+			opts:       func(o JobOptions) JobOptions { o.Retention = 0; return o },
+			err:        "retention: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			opts := jobOptions
+			if tc.opts != nil {
+				opts = tc.opts(opts)
+			}
+
+			j, err := NewJobs(tc.queue, tc.translator, opts)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, j)
+		})
+	}
+}
+
+func TestJobs(t *testing.T) {
+	t.Parallel()
+
+	mewtwo := &pokeapi.Pokemon{Name: "mewtwo", Description: "It was created by a scientist.", IsLegendary: true}
+
+	type testcase struct {
+		// rateLimited is the number of translations rate limited before they succeed
+		rateLimited int32
+		run         func(t *testing.T, jobs *Jobs)
+	}
+
+	tests := map[string]testcase{
+		"CompletesJobWithTranslatedDescription": {
+			run: func(t *testing.T, jobs *Jobs) {
+				submitted, err := jobs.Submit(context.Background(), mewtwo, "")
+				require.NoError(t, err)
+				require.Equal(t, JobQueued, submitted.Status)
+				require.Equal(t, "mewtwo", submitted.Name)
+				require.Nil(t, submitted.Result)
+
+				job := requireCompleted(t, jobs, submitted.ID)
+				require.Equal(t, 1, job.Attempts)
+				require.Equal(t, "translated: It was created by a scientist.", job.Result.Description)
+				require.Equal(t, "mewtwo", job.Result.Name)
+				require.Equal(t, Translation{Style: StyleYoda, Succeeded: true}, job.Result.Translation)
+
+				require.Equal(t, "It was created by a scientist.", mewtwo.Description)
+			},
+		},
+		"RetriesRateLimitedTranslations": {
+			rateLimited: 1,
+			run: func(t *testing.T, jobs *Jobs) {
+				submitted, err := jobs.Submit(context.Background(), mewtwo, "")
+				require.NoError(t, err)
+
+				job := requireCompleted(t, jobs, submitted.ID)
+				require.Equal(t, 2, job.Attempts)
+				require.True(t, job.Result.Translation.Succeeded)
+			},
+		},
+		"CompletesWithOriginalDescriptionAfterLastAttempt": {
+			rateLimited: 5,
+			run: func(t *testing.T, jobs *Jobs) {
+				submitted, err := jobs.Submit(context.Background(), mewtwo, "")
+				require.NoError(t, err)
+
+				job := requireCompleted(t, jobs, submitted.ID)
+				require.Equal(t, 2, job.Attempts)
+				require.Equal(t, mewtwo.Description, job.Result.Description)
+				require.Equal(t, Translation{Style: StyleYoda, FallbackReason: FallbackRateLimited}, job.Result.Translation)
+			},
+		},
+		"PostsCompletedJobToCallback": {
+			run: func(t *testing.T, jobs *Jobs) {
+				received := make(chan Job, 1)
+
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					var job Job
+					_ = json.NewDecoder(r.Body).Decode(&job)
+					received <- job
+				}))
+				defer srv.Close()
+
+				submitted, err := jobs.Submit(context.Background(), mewtwo, srv.URL+"/translations")
+				require.NoError(t, err)
+
+				select {
+				case job := <-received:
+					require.Equal(t, submitted.ID, job.ID)
+					require.Equal(t, JobCompleted, job.Status)
+					require.Equal(t, "translated: It was created by a scientist.", job.Result.Description)
+				case <-time.After(time.Second):
+					require.Fail(t, "callback not received")
+				}
+			},
+		},
+		"DoesNotFollowCallbackRedirects": {
+			run: func(t *testing.T, jobs *Jobs) {
+				var redirected int32
+
+				target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&redirected, 1)
+				}))
+				defer target.Close()
+
+				sent := make(chan struct{}, 1)
+
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					http.Redirect(w, r, target.URL+"/latest", http.StatusTemporaryRedirect)
+					sent <- struct{}{}
+				}))
+				defer srv.Close()
+
+				_, err := jobs.Submit(context.Background(), mewtwo, srv.URL+"/translations")
+				require.NoError(t, err)
+
+				select {
+				case <-sent:
+				case <-time.After(time.Second):
+					require.Fail(t, "callback not received")
+				}
+
+				time.Sleep(20 * time.Millisecond)
+				require.Zero(t, atomic.LoadInt32(&redirected))
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+
+			translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+				if atomic.AddInt32(&calls, 1) <= tc.rateLimited {
+					return TranslationResult{
+						Description: pok.Description,
+						Translation: Translation{Style: Style(pok), FallbackReason: FallbackRateLimited},
+					}
+				}
+
+				return TranslationResult{
+					Description: "translated: " + pok.Description,
+					Translation: Translation{Style: Style(pok), Succeeded: true},
+				}
+			})
+
+			jobs, err := NewJobs(openJobQueue(t), translator, jobOptions)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+
+				jobs.Run(ctx)
+			}()
+
+			tc.run(t, jobs)
+
+			cancel()
+			<-done
+		})
+	}
+}
+
+func TestJobs_Submit_Error(t *testing.T) {
+	t.Parallel()
+
+	translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		return TranslationResult{}
+	})
+	mew := &pokeapi.Pokemon{Name: "mew"}
+
+	tests := map[string]struct {
+		hosts    []string
+		callback string
+		// pending is the number of jobs submitted before
+		pending int
+		err     string
+		is      error
+	}{
+		"ReturnsErrorWhenCallbacksDisabled": {
+			callback: "https://example.com/translations",
+			err:      "callbacks are not enabled: invalid callback url",
+			is:       ErrInvalidCallback,
+		},
+		"ReturnsErrorWhenCallbackNotAbsolute": {
+			hosts:    []string{"example.com"},
+			callback: "/translations",
+			err:      "not an absolute http(s) url: invalid callback url",
+			is:       ErrInvalidCallback,
+		},
+		"ReturnsErrorWhenCallbackHostNotAllowed": {
+			hosts:    []string{"example.com"},
+			callback: "http://169.254.169.254/latest",
+			err:      "host 169.254.169.254 is not allowed: invalid callback url",
+			is:       ErrInvalidCallback,
+		},
+		"ReturnsErrorWhenQueueFull": {
+			pending: 2,
+			err:     "translation job queue full",
+			is:      ErrQueueFull,
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			opts := jobOptions
+			opts.CallbackHosts = tc.hosts
+			opts.MaxPending = 2
+
+			jobs, err := NewJobs(openJobQueue(t), translator, opts)
+			require.NoError(t, err)
+
+			for i := 0; i < tc.pending; i++ {
+				_, err := jobs.Submit(context.Background(), mew, "")
+				require.NoError(t, err)
+			}
+
+			_, err = jobs.Submit(context.Background(), mew, tc.callback)
+			require.EqualError(t, err, tc.err)
+			require.ErrorIs(t, err, tc.is)
+		})
+	}
+}
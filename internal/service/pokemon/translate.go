@@ -6,6 +6,7 @@ import (
 	"context"
 	"pokedex/pkg/adapter/funtranslations"
 	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -15,13 +16,41 @@ const (
 	caveHabitat = "cave"
 )
 
+// Translation styles
+const (
+	StyleYoda        = "yoda"
+	StyleShakespeare = "shakespeare"
+)
+
+// Reasons for falling back to the original description
+const (
+	FallbackRateLimited   = "rate_limited"
+	FallbackUpstreamError = "upstream_error"
+	FallbackCircuitOpen   = "circuit_open"
+)
+
 var ErrInvalidParam = errors.New("invalid parameter")
 
 type (
 	// DescriptionTranslator alows description translations
 	DescriptionTranslator interface {
 		// TranslateDescription translates description of a given Pokemon
-		TranslateDescription(ctx context.Context, pok *pokeapi.Pokemon) string
+		TranslateDescription(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult
+	}
+
+	// TranslationResult holds a translated description, or the original one when translation failed,
+	// together with details about the translation
+	TranslationResult struct {
+		Description string
+		Translation Translation
+	}
+
+	// Translation describes how a description was translated
+	Translation struct {
+		Style          string `json:"style"`
+		Succeeded      bool   `json:"succeeded"`
+		FallbackReason string `json:"fallback_reason,omitempty"`
+		Cached         bool   `json:"cached"`
 	}
 
 	// TranslatService service that allows transations on the original Pokemon data
@@ -41,25 +70,55 @@ func NewTranslateService(translator funtranslations.Translator) (*TranslatServic
 	}, nil
 }
 
-// TranslateDescription translates description of a given Pokemon
-func (t *TranslatService) TranslateDescription(ctx context.Context, pok *pokeapi.Pokemon) string {
-	if pok.IsLegendary || pok.Habitat == caveHabitat {
-		desc, err := t.translator.TranslateToYoda(ctx, pok.Description)
-		if err != nil {
-			log.Info().Err(err).Msg("failed to translate; defauling description")
+// TranslateDescription translates description of a given Pokemon, the original description is
+// returned when translation fails
+func (t *TranslatService) TranslateDescription(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+	style := Style(pok)
+
+	translate := t.translator.TranslateToShakespeare
+	if style == StyleYoda {
+		translate = t.translator.TranslateToYoda
+	}
+
+	desc, err := translate(ctx, pok.Description)
+	if err != nil {
+		log.Info().Err(err).Str("style", style).Msg("failed to translate; defauling description")
 
-			return pok.Description
+		return TranslationResult{
+			Description: pok.Description,
+			Translation: Translation{
+				Style:          style,
+				FallbackReason: fallbackReason(err),
+			},
 		}
+	}
 
-		return desc
+	return TranslationResult{
+		Description: desc,
+		Translation: Translation{
+			Style:     style,
+			Succeeded: true,
+		},
 	}
+}
 
-	desc, err := t.translator.TranslateToShakespeare(ctx, pok.Description)
-	if err != nil {
-		log.Info().Err(err).Msg("failed to translate; defauling description")
+// Style returns the translation style applied to a given Pokemon
+func Style(pok *pokeapi.Pokemon) string {
+	if pok.IsLegendary || pok.Habitat == caveHabitat {
+		return StyleYoda
+	}
+
+	return StyleShakespeare
+}
+
+func fallbackReason(err error) string {
+	if errors.Is(err, funtranslations.ErrCircuitOpen) {
+		return FallbackCircuitOpen
+	}
 
-		return pok.Description
+	if errors.Is(err, upstream.ErrRateLimited) {
+		return FallbackRateLimited
 	}
 
-	return desc
+	return FallbackUpstreamError
 }
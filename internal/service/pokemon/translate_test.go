@@ -5,6 +5,7 @@ import (
 	"pokedex/internal/service/pokemon/mocks"
 	"pokedex/pkg/adapter/funtranslations"
 	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -58,7 +59,7 @@ func TestTranslateDescription(t *testing.T) {
 	type testcase struct {
 		pokemon       *pokeapi.Pokemon
 		translator    func(t *testing.T, c *gomock.Controller) *mocks.MockTranslator
-		checkResponse func(t *testing.T, desc string)
+		checkResponse func(t *testing.T, res TranslationResult)
 	}
 
 	tests := map[string]testcase{
@@ -79,8 +80,9 @@ func TestTranslateDescription(t *testing.T) {
 
 				return m
 			},
-			checkResponse: func(t *testing.T, desc string) {
-				require.Equal(t, yodaDesc, desc)
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, yodaDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleYoda, Succeeded: true}, res.Translation)
 			},
 		},
 		"ReturnYodaTranslationWhenCaveHabitat": {
@@ -100,8 +102,9 @@ func TestTranslateDescription(t *testing.T) {
 
 				return m
 			},
-			checkResponse: func(t *testing.T, desc string) {
-				require.Equal(t, yodaDesc, desc)
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, yodaDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleYoda, Succeeded: true}, res.Translation)
 			},
 		},
 		"ReturnYodaTranslationWhenLegendaryAndCave": {
@@ -121,8 +124,9 @@ func TestTranslateDescription(t *testing.T) {
 
 				return m
 			},
-			checkResponse: func(t *testing.T, desc string) {
-				require.Equal(t, yodaDesc, desc)
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, yodaDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleYoda, Succeeded: true}, res.Translation)
 			},
 		},
 		"ReturnDefaultDescriptionWhenLegendaryAndFaildToTranslate": {
@@ -142,8 +146,9 @@ func TestTranslateDescription(t *testing.T) {
 
 				return m
 			},
-			checkResponse: func(t *testing.T, desc string) {
-				require.Equal(t, defaultDesc, desc)
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, defaultDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleYoda, FallbackReason: FallbackUpstreamError}, res.Translation)
 			},
 		},
 		"ReturnDefaultDescriptionWhenCaveHabitatAndFaildToTranslate": {
@@ -163,8 +168,9 @@ func TestTranslateDescription(t *testing.T) {
 
 				return m
 			},
-			checkResponse: func(t *testing.T, desc string) {
-				require.Equal(t, defaultDesc, desc)
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, defaultDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleYoda, FallbackReason: FallbackUpstreamError}, res.Translation)
 			},
 		},
 		"ReturnShakespeareTranslationWhenNotLegendaryOrCaveHabitat": {
@@ -184,8 +190,9 @@ func TestTranslateDescription(t *testing.T) {
 
 				return m
 			},
-			checkResponse: func(t *testing.T, desc string) {
-				require.Equal(t, shakespeareDesc, desc)
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, shakespeareDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleShakespeare, Succeeded: true}, res.Translation)
 			},
 		},
 		"ReturnDefaultDescriptionWhenNotLegendaryOrCaveHabitatAndFailedToTranslate": {
@@ -205,8 +212,51 @@ func TestTranslateDescription(t *testing.T) {
 
 				return m
 			},
-			checkResponse: func(t *testing.T, desc string) {
-				require.Equal(t, defaultDesc, desc)
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, defaultDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleShakespeare, FallbackReason: FallbackUpstreamError}, res.Translation)
+			},
+		},
+		"ReturnRateLimitedFallbackReasonWhenTranslatorRateLimited": {
+			pokemon: &pokeapi.Pokemon{
+				Name:        "eevee",
+				Habitat:     "normal",
+				Description: defaultDesc,
+				IsLegendary: false,
+			},
+			translator: func(t *testing.T, c *gomock.Controller) *mocks.MockTranslator {
+				m := mocks.NewMockTranslator(c)
+				m.EXPECT().
+					TranslateToShakespeare(gomock.Any(), defaultDesc).
+					DoAndReturn(func(ctx context.Context, desc string) (string, error) {
+						return "", errors.Wrap(&upstream.ResponseError{Kind: upstream.ErrRateLimited}, "invalid response code")
+					})
+
+				return m
+			},
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, defaultDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleShakespeare, FallbackReason: FallbackRateLimited}, res.Translation)
+			},
+		},
+		"ReturnCircuitOpenFallbackReasonWhenCircuitOpen": {
+			pokemon: &pokeapi.Pokemon{
+				Name:        "eevee",
+				Habitat:     "normal",
+				Description: defaultDesc,
+				IsLegendary: false,
+			},
+			translator: func(t *testing.T, c *gomock.Controller) *mocks.MockTranslator {
+				m := mocks.NewMockTranslator(c)
+				m.EXPECT().
+					TranslateToShakespeare(gomock.Any(), defaultDesc).
+					Return("", funtranslations.ErrCircuitOpen)
+
+				return m
+			},
+			checkResponse: func(t *testing.T, res TranslationResult) {
+				require.Equal(t, defaultDesc, res.Description)
+				require.Equal(t, Translation{Style: StyleShakespeare, FallbackReason: FallbackCircuitOpen}, res.Translation)
 			},
 		},
 	}
@@ -0,0 +1,339 @@
+package pokemon
+
+import (
+	"context"
+	"math"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	errSkippedPokeAPI         = errors.New("skipped, pokeapi rate limited the warm-up")
+	errSkippedFuntranslations = errors.New("translation skipped, funtranslations rate limited the warm-up")
+)
+
+type (
+	// Warmer loads Pokemons and their translated description into the caches
+	Warmer struct {
+		fetcher    pokeapi.PokemonFetcher
+		translator DescriptionTranslator
+
+		mu   sync.RWMutex
+		opts WarmerOptions
+	}
+
+	// WarmerOptions limits the load a warm-up puts on the upstream APIs
+	WarmerOptions struct {
+		// Concurrency is the number of names warmed up at once
+		Concurrency int
+		// Rate limits the names warmed up per second, unlimited when 0
+		Rate float64
+	}
+
+	// WarmupResult is the outcome of a warm-up, Failed holds why every name that was not warmed up
+	// failed
+	WarmupResult struct {
+		Requested int               `json:"requested"`
+		Warmed    int               `json:"warmed"`
+		Failed    map[string]string `json:"failed,omitempty"`
+	}
+
+	// WarmupProgress tracks a running warm-up, it is ready once the threshold share of the names
+	// has been processed, warmed up or failed. It is safe for concurrent use
+	WarmupProgress struct {
+		mu        sync.Mutex
+		threshold float64
+		status    WarmupStatus
+	}
+
+	// WarmupStatus is a snapshot of a WarmupProgress
+	WarmupStatus struct {
+		Started   bool `json:"started"`
+		Done      bool `json:"done"`
+		Ready     bool `json:"ready"`
+		Requested int  `json:"requested"`
+		Warmed    int  `json:"warmed"`
+		Failed    int  `json:"failed"`
+	}
+
+	// warmup is a single run of a Warmer, it stops calling an upstream API once rate limited
+	warmup struct {
+		rateLimited          int32
+		translationsDisabled int32
+	}
+)
+
+// NewWarmer creates a new Warmer, fetcher and translator are expected to be the caching ones
+func NewWarmer(fetcher pokeapi.PokemonFetcher, translator DescriptionTranslator, opts WarmerOptions) (*Warmer, error) {
+	if fetcher == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "fetcher")
+	}
+
+	if translator == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "translator")
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Warmer{
+		fetcher:    fetcher,
+		translator: translator,
+		opts:       opts,
+	}, nil
+}
+
+// SetOptions changes the limits of the warm-ups started afterwards, running ones keep theirs
+func (w *Warmer) SetOptions(opts WarmerOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.opts = opts
+
+	return nil
+}
+
+func (w *Warmer) options() WarmerOptions {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.opts
+}
+
+func (o WarmerOptions) validate() error {
+	if o.Concurrency < 1 {
+		return errors.Wrap(ErrInvalidParam, "concurrency")
+	}
+
+	if o.Rate < 0 {
+		return errors.Wrap(ErrInvalidParam, "rate")
+	}
+
+	return nil
+}
+
+// NewWarmupProgress creates a WarmupProgress ready once threshold, between 0 and 1, of the names
+// have been processed
+func NewWarmupProgress(threshold float64) (*WarmupProgress, error) {
+	if threshold < 0 || threshold > 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "threshold")
+	}
+
+	return &WarmupProgress{threshold: threshold}, nil
+}
+
+// Warm fetches every distinct name and translates its description. A name is warmed up when both
+// succeed, an unknown Pokemon or a translation falling back to the original description fails.
+// Once PokeAPI rate limits the warm-up the remaining names are skipped, once Funtranslations does
+// the remaining translations are
+func (w *Warmer) Warm(ctx context.Context, names []string) WarmupResult {
+	return w.WarmTracked(ctx, names, nil)
+}
+
+// WarmTracked warms up names like Warm, reporting its progress to progress when not nil
+func (w *Warmer) WarmTracked(ctx context.Context, names []string, progress *WarmupProgress) WarmupResult {
+	opts := w.options()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, opts.Concurrency)
+		run    = &warmup{}
+		result = WarmupResult{Failed: map[string]string{}}
+	)
+
+	names = distinct(names)
+	result.Requested = len(names)
+	progress.start(len(names))
+	defer progress.finish()
+
+	tick, stop := limiter(opts.Rate)
+	defer stop()
+
+	for _, name := range names {
+		if tick != nil && atomic.LoadInt32(&run.rateLimited) == 0 {
+			select {
+			case <-ctx.Done():
+			case <-tick:
+			}
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			err := w.warm(ctx, run, name)
+			progress.record(err)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				result.Failed[name] = err.Error()
+
+				return
+			}
+
+			result.Warmed++
+		}(name)
+	}
+
+	wg.Wait()
+
+	return result
+}
+
+func (w *Warmer) warm(ctx context.Context, run *warmup, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if atomic.LoadInt32(&run.rateLimited) == 1 {
+		return errSkippedPokeAPI
+	}
+
+	pok, err := w.fetcher.FetchByName(ctx, name)
+	if errors.Is(err, upstream.ErrRateLimited) {
+		atomic.StoreInt32(&run.rateLimited, 1)
+	}
+	if err != nil {
+		return err
+	}
+
+	if pok == nil {
+		return errors.New("pokemon not found")
+	}
+
+	if atomic.LoadInt32(&run.translationsDisabled) == 1 {
+		return errSkippedFuntranslations
+	}
+
+	res := w.translator.TranslateDescription(ctx, pok)
+	if !res.Translation.Succeeded {
+		if res.Translation.FallbackReason == FallbackRateLimited {
+			atomic.StoreInt32(&run.translationsDisabled, 1)
+		}
+
+		return errors.Errorf("translation fell back to the original description: %s", res.Translation.FallbackReason)
+	}
+
+	return nil
+}
+
+// limiter returns a channel delivering rate ticks per second, one per allowed name, nil when unlimited
+func limiter(rate float64) (<-chan time.Time, func()) {
+	if rate <= 0 {
+		return nil, func() {}
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+
+	return ticker.C, ticker.Stop
+}
+
+// Status returns the current progress of the warm-up
+func (p *WarmupProgress) Status() WarmupStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.status
+}
+
+// Ready reports whether enough names have been processed
+func (p *WarmupProgress) Ready() bool {
+	return p.Status().Ready
+}
+
+// Check fails until the warm-up is ready, the status is returned as details. It is meant as a
+// readiness check
+func (p *WarmupProgress) Check(ctx context.Context) (interface{}, error) {
+	status := p.Status()
+
+	switch {
+	case !status.Started:
+		return status, errors.New("warm-up not started")
+	case !status.Ready:
+		return status, errors.Errorf("warming up, %d of %d names processed", status.Warmed+status.Failed, status.Requested)
+	}
+
+	return status, nil
+}
+
+func (p *WarmupProgress) start(requested int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status = WarmupStatus{Started: true, Requested: requested}
+	p.update()
+}
+
+func (p *WarmupProgress) record(err error) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		p.status.Failed++
+	} else {
+		p.status.Warmed++
+	}
+
+	p.update()
+}
+
+func (p *WarmupProgress) finish() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status.Done = true
+	p.update()
+}
+
+// update must be called with mu held
+func (p *WarmupProgress) update() {
+	processed := p.status.Warmed + p.status.Failed
+	required := int(math.Ceil(p.threshold * float64(p.status.Requested)))
+
+	p.status.Ready = p.status.Ready || p.status.Done || processed >= required
+}
+
+// distinct returns names without duplicates, in order
+func distinct(names []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(names))
+
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,301 @@
+package pokemon
+
+import (
+	"context"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type fetcherFunc func(ctx context.Context, name string) (*pokeapi.Pokemon, error)
+
+func (fn fetcherFunc) FetchByName(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+	return fn(ctx, name)
+}
+
+func TestNewWarmer_Error(t *testing.T) {
+	t.Parallel()
+
+	fetcher := fetcherFunc(func(ctx context.Context, name string) (*pokeapi.Pokemon, error) { return nil, nil })
+	translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		return TranslationResult{}
+	})
+
+	tests := map[string]struct {
+		fetcher    pokeapi.PokemonFetcher
+		translator DescriptionTranslator
+		opts       WarmerOptions
+		err        string
+	}{
+		"ReturnsErrorWhenFetcherNil": {
+			translator: translator,
+			opts:       WarmerOptions{Concurrency: 1},
+			err:        "fetcher: invalid parameter",
+		},
+		"ReturnsErrorWhenTranslatorNil": {
+			fetcher: fetcher,
+			opts:    WarmerOptions{Concurrency: 1},
+			err:     "translator: invalid parameter",
+		},
+		"ReturnsErrorWhenConcurrencyNotPositive": {
+			fetcher:    fetcher,
+			translator: translator,
+			err:        "concurrency: invalid parameter",
+		},
+		"ReturnsErrorWhenRateNegative": {
+			fetcher:    fetcher,
+			translator: translator,
+			opts:       WarmerOptions{Concurrency: 1, Rate: -1},
+			err:        "rate: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			w, err := NewWarmer(tc.fetcher, tc.translator, tc.opts)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, w)
+		})
+	}
+}
+
+func TestNewWarmupProgress_Error(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewWarmupProgress(1.5)
+	require.EqualError(t, err, "threshold: invalid parameter")
+	require.Nil(t, p)
+}
+
+func TestWarmer_Warm(t *testing.T) {
+	t.Parallel()
+
+	var fetches int32
+
+	fetcher := fetcherFunc(func(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+		atomic.AddInt32(&fetches, 1)
+
+		switch name {
+		case "missingno":
+			return nil, nil
+		case "porygon":
+			return nil, errors.New("upstream unavailable")
+		}
+
+		return &pokeapi.Pokemon{Name: name, Description: "original"}, nil
+	})
+
+	translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		if pok.Name == "ditto" {
+			return TranslationResult{Translation: Translation{FallbackReason: FallbackUpstreamError}}
+		}
+
+		return TranslationResult{Translation: Translation{Succeeded: true}}
+	})
+
+	w, err := NewWarmer(fetcher, translator, WarmerOptions{Concurrency: 2})
+	require.NoError(t, err)
+
+	res := w.Warm(context.Background(), []string{"mewtwo", "mew", "mewtwo", "missingno", "porygon", "ditto"})
+
+	require.Equal(t, WarmupResult{
+		Requested: 5,
+		Warmed:    2,
+		Failed: map[string]string{
+			"missingno": "pokemon not found",
+			"porygon":   "upstream unavailable",
+			"ditto":     "translation fell back to the original description: " + FallbackUpstreamError,
+		},
+	}, res)
+	require.Equal(t, int32(5), atomic.LoadInt32(&fetches))
+}
+
+func TestWarmer_Warm_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		fetcher    fetcherFunc
+		translator func(translations *int32) translatorFunc
+		check      func(t *testing.T, res WarmupResult, translations int32)
+	}
+
+	tests := map[string]testcase{
+		"SkipsRemainingNamesOncePokeAPIRateLimited": {
+			fetcher: func(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+				if name == "mew" {
+					return nil, &upstream.ResponseError{Kind: upstream.ErrRateLimited}
+				}
+
+				return &pokeapi.Pokemon{Name: name}, nil
+			},
+			translator: func(translations *int32) translatorFunc {
+				return func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+					atomic.AddInt32(translations, 1)
+
+					return TranslationResult{Translation: Translation{Succeeded: true}}
+				}
+			},
+			check: func(t *testing.T, res WarmupResult, translations int32) {
+				require.Equal(t, 1, res.Warmed)
+				require.Equal(t, errSkippedPokeAPI.Error(), res.Failed["ditto"])
+				require.Equal(t, errSkippedPokeAPI.Error(), res.Failed["eevee"])
+				require.Equal(t, int32(1), translations)
+			},
+		},
+		"SkipsRemainingTranslationsOnceFuntranslationsRateLimited": {
+			fetcher: func(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+				return &pokeapi.Pokemon{Name: name}, nil
+			},
+			translator: func(translations *int32) translatorFunc {
+				return func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+					atomic.AddInt32(translations, 1)
+
+					if pok.Name == "mew" {
+						return TranslationResult{Translation: Translation{FallbackReason: FallbackRateLimited}}
+					}
+
+					return TranslationResult{Translation: Translation{Succeeded: true}}
+				}
+			},
+			check: func(t *testing.T, res WarmupResult, translations int32) {
+				require.Equal(t, 1, res.Warmed)
+				require.Equal(t, "translation fell back to the original description: "+FallbackRateLimited, res.Failed["mew"])
+				require.Equal(t, errSkippedFuntranslations.Error(), res.Failed["ditto"])
+				require.Equal(t, errSkippedFuntranslations.Error(), res.Failed["eevee"])
+				require.Equal(t, int32(2), translations)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			var translations int32
+
+			w, err := NewWarmer(tc.fetcher, tc.translator(&translations), WarmerOptions{Concurrency: 1})
+			require.NoError(t, err)
+
+			res := w.Warm(context.Background(), []string{"mewtwo", "mew", "ditto", "eevee"})
+
+			require.Equal(t, 4, res.Requested)
+			tc.check(t, res, atomic.LoadInt32(&translations))
+		})
+	}
+}
+
+func TestWarmer_Warm_Rate(t *testing.T) {
+	t.Parallel()
+
+	fetcher := fetcherFunc(func(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+		return &pokeapi.Pokemon{Name: name}, nil
+	})
+	translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		return TranslationResult{Translation: Translation{Succeeded: true}}
+	})
+
+	w, err := NewWarmer(fetcher, translator, WarmerOptions{Concurrency: 4, Rate: 50})
+	require.NoError(t, err)
+
+	start := time.Now()
+	res := w.Warm(context.Background(), []string{"mewtwo", "mew", "ditto", "eevee", "zubat"})
+
+	require.Equal(t, 5, res.Warmed)
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestWarmer_SetOptions(t *testing.T) {
+	t.Parallel()
+
+	fetcher := fetcherFunc(func(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+		return &pokeapi.Pokemon{Name: name}, nil
+	})
+	translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		return TranslationResult{Translation: Translation{Succeeded: true}}
+	})
+
+	w, err := NewWarmer(fetcher, translator, WarmerOptions{Concurrency: 1, Rate: 1})
+	require.NoError(t, err)
+
+	require.EqualError(t, w.SetOptions(WarmerOptions{}), "concurrency: invalid parameter")
+	require.NoError(t, w.SetOptions(WarmerOptions{Concurrency: 4}))
+
+	start := time.Now()
+	res := w.Warm(context.Background(), []string{"mewtwo", "mew", "ditto", "eevee", "zubat"})
+
+	require.Equal(t, 5, res.Warmed)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestWarmer_WarmTracked(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	fetcher := fetcherFunc(func(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+		if name == "eevee" {
+			<-release
+		}
+
+		return &pokeapi.Pokemon{Name: name}, nil
+	})
+	translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		return TranslationResult{Translation: Translation{Succeeded: pok.Name != "ditto"}}
+	})
+
+	w, err := NewWarmer(fetcher, translator, WarmerOptions{Concurrency: 4})
+	require.NoError(t, err)
+
+	progress, err := NewWarmupProgress(0.75)
+	require.NoError(t, err)
+	require.Equal(t, WarmupStatus{}, progress.Status())
+
+	done := make(chan WarmupResult)
+	go func() {
+		done <- w.WarmTracked(context.Background(), []string{"mewtwo", "mew", "ditto", "eevee"}, progress)
+	}()
+
+	require.Eventually(t, progress.Ready, time.Second, time.Millisecond)
+	require.Equal(t, WarmupStatus{Started: true, Ready: true, Requested: 4, Warmed: 2, Failed: 1}, progress.Status())
+
+	close(release)
+	<-done
+
+	require.Equal(t, WarmupStatus{Started: true, Done: true, Ready: true, Requested: 4, Warmed: 3, Failed: 1}, progress.Status())
+}
+
+func TestWarmer_Warm_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	fetcher := fetcherFunc(func(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+		t.Fatal("unexpected fetch")
+
+		return nil, nil
+	})
+	translator := translatorFunc(func(ctx context.Context, pok *pokeapi.Pokemon) TranslationResult {
+		return TranslationResult{}
+	})
+
+	w, err := NewWarmer(fetcher, translator, WarmerOptions{Concurrency: 1, Rate: 1})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res := w.Warm(ctx, []string{"mewtwo", "mew"})
+	require.Equal(t, WarmupResult{Requested: 2, Failed: map[string]string{
+		"mewtwo": "context canceled",
+		"mew":    "context canceled",
+	}}, res)
+}
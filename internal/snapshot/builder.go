@@ -0,0 +1,409 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"pokedex/pkg/adapter/pokeapi"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	indexFile = "index.json"
+
+	// saveEvery is the number of written files after which the manifest is saved, bounding the work
+	// repeated when an interrupted run is resumed
+	saveEvery = 100
+)
+
+// DefaultResources are the resources mirrored when none are given
+var DefaultResources = []string{"pokemon-species", "pokemon", "evolution-chain"}
+
+var ErrInvalidParam = errors.New("invalid parameter")
+
+type (
+	// Source lists and fetches raw PokeAPI resources, implemented by pokeapi.Client
+	Source interface {
+		List(ctx context.Context, resource string) (*pokeapi.ResourceList, error)
+		FetchResource(ctx context.Context, resource string, id string, etag string) (*pokeapi.Resource, error)
+	}
+
+	// Options configure a Builder
+	Options struct {
+		// Dir is the dataset directory, laid out like the api-data repository
+		Dir string
+		// BaseURL of the source, stripped from urls so the dataset only holds relative urls
+		BaseURL string
+		// Resources to mirror, DefaultResources when empty
+		Resources []string
+		// Concurrency is the number of concurrent requests
+		Concurrency int
+		// Rate limits the number of requests per second, unlimited when 0
+		Rate float64
+		// Refresh revalidates files already in the dataset, only changed resources are downloaded
+		Refresh bool
+	}
+
+	// Stats summarises a run
+	Stats struct {
+		Fetched   int
+		Unchanged int
+		Skipped   int
+		Removed   int
+		Failed    int
+	}
+
+	// Builder mirrors PokeAPI resources into a versioned, checksummed dataset. A run resumes from
+	// the files a previous, possibly interrupted, run recorded in the manifest
+	Builder struct {
+		source Source
+		opts   Options
+
+		mu       sync.Mutex
+		manifest *Manifest
+		stats    Stats
+		changed  bool
+		unsaved  int
+	}
+
+	job struct {
+		resource string
+		id       string
+		name     string
+	}
+)
+
+// NewBuilder creates a new Builder
+func NewBuilder(source Source, opts Options) (*Builder, error) {
+	if source == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "source")
+	}
+
+	if opts.Dir == "" {
+		return nil, errors.Wrap(ErrInvalidParam, "dir")
+	}
+
+	if len(opts.Resources) == 0 {
+		opts.Resources = DefaultResources
+	}
+
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+
+	return &Builder{
+		source: source,
+		opts:   opts,
+	}, nil
+}
+
+// Run mirrors the configured resources. The manifest is saved when the run ends, including when
+// ctx is cancelled, so the next run resumes where this one stopped
+func (b *Builder) Run(ctx context.Context) (Stats, error) {
+	manifest, err := ReadManifest(b.opts.Dir)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	b.manifest = manifest
+	b.stats = Stats{}
+	b.changed = false
+
+	if !b.opts.Refresh {
+		for _, name := range manifest.Verify(b.opts.Dir) {
+			log.Warn().Str("file", name).Msg("snapshot file missing or corrupt, fetching again")
+
+			delete(manifest.Files, name)
+		}
+	}
+
+	runErr := b.run(ctx)
+
+	if err := b.save(); err != nil {
+		return b.stats, err
+	}
+
+	if runErr != nil {
+		return b.stats, runErr
+	}
+
+	if b.stats.Failed > 0 {
+		return b.stats, errors.Errorf("failed to fetch %d resources, run again to resume", b.stats.Failed)
+	}
+
+	return b.stats, nil
+}
+
+func (b *Builder) run(ctx context.Context) error {
+	tick, stop := b.limiter()
+	defer stop()
+
+	for _, resource := range b.opts.Resources {
+		if err := wait(ctx, tick); err != nil {
+			return err
+		}
+
+		jobs, err := b.index(ctx, resource)
+		if err != nil {
+			return err
+		}
+
+		b.fetchAll(ctx, tick, jobs)
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// index lists a resource, writes its index.json and removes resources no longer listed
+func (b *Builder) index(ctx context.Context, resource string) ([]job, error) {
+	list, err := b.source.List(ctx, resource)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]job, 0, len(list.Results))
+	listed := map[string]bool{}
+	index := &pokeapi.ResourceList{Count: list.Count}
+
+	for _, r := range list.Results {
+		id, err := resourceID(r.Url)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s url %q", resource, r.Url)
+		}
+
+		jobs = append(jobs, job{resource: resource, id: id, name: r.Name})
+		listed[resourceFile(resource, id)] = true
+
+		index.Results = append(index.Results, pokeapi.NamedResource{
+			Name: r.Name,
+			Url:  "/" + path.Join("api/v2", resource, id) + "/",
+		})
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %s index", resource)
+	}
+
+	if err := b.write(path.Join("api/v2", resource, indexFile), data, ""); err != nil {
+		return nil, err
+	}
+
+	b.prune(resource, listed)
+
+	return jobs, nil
+}
+
+// prune removes the files of resources that are no longer listed
+func (b *Builder) prune(resource string, listed map[string]bool) {
+	prefix := path.Join("api/v2", resource) + "/"
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for name := range b.manifest.Files {
+		if !strings.HasPrefix(name, prefix) || name == prefix+indexFile || listed[name] {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(b.opts.Dir, filepath.FromSlash(name))); err != nil && !os.IsNotExist(err) {
+			log.Warn().Err(err).Str("file", name).Msg("failed to remove snapshot file")
+
+			continue
+		}
+
+		delete(b.manifest.Files, name)
+		b.stats.Removed++
+		b.changed = true
+	}
+}
+
+func (b *Builder) fetchAll(ctx context.Context, tick <-chan time.Time, jobs []job) {
+	queue := make(chan job)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < b.opts.Concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range queue {
+				b.fetch(ctx, tick, j)
+			}
+		}()
+	}
+
+	for _, j := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		queue <- j
+	}
+
+	close(queue)
+	wg.Wait()
+}
+
+func (b *Builder) fetch(ctx context.Context, tick <-chan time.Time, j job) {
+	name := resourceFile(j.resource, j.id)
+
+	b.mu.Lock()
+	existing, ok := b.manifest.Files[name]
+	b.mu.Unlock()
+
+	if ok && !b.opts.Refresh {
+		b.count(func(s *Stats) { s.Skipped++ })
+
+		return
+	}
+
+	if err := wait(ctx, tick); err != nil {
+		return
+	}
+
+	res, err := b.source.FetchResource(ctx, j.resource, j.id, existing.ETag)
+	if errors.Is(err, pokeapi.ErrNotModified) {
+		b.count(func(s *Stats) { s.Unchanged++ })
+
+		return
+	}
+
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Warn().Err(err).Str("resource", j.resource).Str("id", j.id).Msg("failed to fetch resource")
+
+			b.count(func(s *Stats) { s.Failed++ })
+		}
+
+		return
+	}
+
+	data := bytes.ReplaceAll(res.Data, []byte(b.opts.BaseURL+"/api/v2/"), []byte("/api/v2/"))
+
+	if err := b.write(name, data, res.ETag); err != nil {
+		log.Warn().Err(err).Str("resource", j.resource).Str("id", j.id).Msg("failed to write resource")
+
+		b.count(func(s *Stats) { s.Failed++ })
+	}
+}
+
+// write stores a file and records it in the manifest, unchanged content is not rewritten
+func (b *Builder) write(name string, data []byte, etag string) error {
+	sum := checksum(data)
+
+	b.mu.Lock()
+	existing, ok := b.manifest.Files[name]
+	b.mu.Unlock()
+
+	unchanged := ok && existing.SHA256 == sum
+
+	if !unchanged {
+		if err := writeFile(b.opts.Dir, name, data); err != nil {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.manifest.Files[name] = File{SHA256: sum, ETag: etag}
+
+	if unchanged {
+		b.stats.Unchanged++
+
+		return nil
+	}
+
+	b.stats.Fetched++
+	b.changed = true
+	b.unsaved++
+
+	if b.unsaved < saveEvery {
+		return nil
+	}
+
+	b.unsaved = 0
+
+	return b.manifest.Write(b.opts.Dir)
+}
+
+func (b *Builder) save() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.changed {
+		b.manifest.Version++
+		b.manifest.UpdatedAt = time.Now().UTC()
+	}
+
+	b.manifest.Source = b.opts.BaseURL
+
+	return b.manifest.Write(b.opts.Dir)
+}
+
+func (b *Builder) count(fn func(s *Stats)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	fn(&b.stats)
+}
+
+// limiter returns a channel delivering one tick per allowed request, nil when unlimited
+func (b *Builder) limiter() (<-chan time.Time, func()) {
+	if b.opts.Rate <= 0 {
+		return nil, func() {}
+	}
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / b.opts.Rate))
+
+	return ticker.C, ticker.Stop
+}
+
+func wait(ctx context.Context, tick <-chan time.Time) error {
+	if tick == nil {
+		return ctx.Err()
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-tick:
+		return nil
+	}
+}
+
+func resourceFile(resource string, id string) string {
+	return path.Join("api/v2", resource, id, indexFile)
+}
+
+// resourceID returns the id at the end of a resource url, e.g. 150 for /api/v2/pokemon-species/150/
+func resourceID(resourceURL string) (string, error) {
+	u, err := url.Parse(resourceURL)
+	if err != nil {
+		return "", err
+	}
+
+	id := path.Base(strings.TrimRight(u.Path, "/"))
+	if id == "." || id == "/" {
+		return "", errors.New("missing id")
+	}
+
+	return id, nil
+}
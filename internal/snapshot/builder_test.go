@@ -0,0 +1,224 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"pokedex/pkg/adapter/pokeapi"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	mu        sync.Mutex
+	resources map[string]map[string]string
+	etags     map[string]string
+	fetched   []string
+	fail      map[string]bool
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		resources: map[string]map[string]string{
+			"pokemon-species": {
+				"150": `{"name":"mewtwo","url":"https://pokeapi.co/api/v2/pokemon-species/150/"}`,
+				"151": `{"name":"mew"}`,
+			},
+		},
+		etags: map[string]string{},
+		fail:  map[string]bool{},
+	}
+}
+
+func (s *fakeSource) List(ctx context.Context, resource string) (*pokeapi.ResourceList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// PokeAPI lists resources in a stable order, the index must not change between runs
+	ids := make([]string, 0, len(s.resources[resource]))
+	for id := range s.resources[resource] {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	list := &pokeapi.ResourceList{Count: len(ids)}
+	for _, id := range ids {
+		list.Results = append(list.Results, pokeapi.NamedResource{
+			Name: "name-" + id,
+			Url:  fmt.Sprintf("https://pokeapi.co/api/v2/%s/%s/", resource, id),
+		})
+	}
+
+	return list, nil
+}
+
+func (s *fakeSource) FetchResource(ctx context.Context, resource string, id string, etag string) (*pokeapi.Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := resource + "/" + id
+	if s.fail[key] {
+		return nil, errors.New("boom")
+	}
+
+	if etag != "" && etag == s.etags[key] {
+		return nil, pokeapi.ErrNotModified
+	}
+
+	s.fetched = append(s.fetched, key)
+
+	return &pokeapi.Resource{Data: []byte(s.resources[resource][id]), ETag: s.etags[key]}, nil
+}
+
+func (s *fakeSource) reset() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fetched := s.fetched
+	s.fetched = nil
+
+	return fetched
+}
+
+func newTestBuilder(t *testing.T, source Source, dir string, refresh bool) *Builder {
+	b, err := NewBuilder(source, Options{
+		Dir:         dir,
+		BaseURL:     "https://pokeapi.co/",
+		Resources:   []string{"pokemon-species"},
+		Concurrency: 2,
+		Refresh:     refresh,
+	})
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestBuilder_Run(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := newFakeSource()
+
+	stats, err := newTestBuilder(t, source, dir, false).Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, stats.Fetched)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "api/v2/pokemon-species/150/index.json"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"mewtwo","url":"/api/v2/pokemon-species/150/"}`, string(data))
+
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, manifest.Version)
+	require.Equal(t, "https://pokeapi.co", manifest.Source)
+	require.Len(t, manifest.Files, 3)
+	require.NotEmpty(t, manifest.Checksum)
+	require.Empty(t, manifest.Verify(dir))
+
+	snap, err := pokeapi.NewSnapshot(dir)
+	require.NoError(t, err)
+
+	pok, err := snap.FetchByName(context.Background(), "name-150")
+	require.NoError(t, err)
+	require.Equal(t, "mewtwo", pok.Name)
+}
+
+func TestBuilder_Run_Resumes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := newFakeSource()
+	source.fail["pokemon-species/151"] = true
+
+	stats, err := newTestBuilder(t, source, dir, false).Run(context.Background())
+	require.EqualError(t, err, "failed to fetch 1 resources, run again to resume")
+	require.Equal(t, 1, stats.Failed)
+	require.Equal(t, []string{"pokemon-species/150"}, source.reset())
+
+	source.fail = map[string]bool{}
+
+	stats, err = newTestBuilder(t, source, dir, false).Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Skipped)
+	require.Equal(t, []string{"pokemon-species/151"}, source.reset())
+}
+
+func TestBuilder_Run_RefetchesCorruptFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := newFakeSource()
+
+	_, err := newTestBuilder(t, source, dir, false).Run(context.Background())
+	require.NoError(t, err)
+	source.reset()
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "api/v2/pokemon-species/151/index.json"), []byte("{}"), 0600))
+
+	_, err = newTestBuilder(t, source, dir, false).Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"pokemon-species/151"}, source.reset())
+}
+
+func TestBuilder_Run_Refresh(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := newFakeSource()
+	source.etags["pokemon-species/150"] = `"a"`
+
+	_, err := newTestBuilder(t, source, dir, false).Run(context.Background())
+	require.NoError(t, err)
+	source.reset()
+
+	source.resources["pokemon-species"]["151"] = `{"name":"mew","changed":true}`
+	delete(source.resources["pokemon-species"], "150")
+	source.resources["pokemon-species"]["152"] = `{"name":"chikorita"}`
+
+	stats, err := newTestBuilder(t, source, dir, true).Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Removed)
+	require.ElementsMatch(t, []string{"pokemon-species/151", "pokemon-species/152"}, source.reset())
+
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, 2, manifest.Version)
+	require.NotContains(t, manifest.Files, "api/v2/pokemon-species/150/index.json")
+	require.Empty(t, manifest.Verify(dir))
+}
+
+func TestBuilder_Run_RefreshSkipsNotModified(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	source := newFakeSource()
+	source.etags["pokemon-species/150"] = `"a"`
+	source.etags["pokemon-species/151"] = `"b"`
+
+	_, err := newTestBuilder(t, source, dir, false).Run(context.Background())
+	require.NoError(t, err)
+	source.reset()
+
+	stats, err := newTestBuilder(t, source, dir, true).Run(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, source.reset())
+	require.Equal(t, 0, stats.Fetched)
+
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, manifest.Version)
+}
+
+func TestNewBuilder_Error(t *testing.T) {
+	t.Parallel()
+
+	b, err := NewBuilder(newFakeSource(), Options{})
+	require.EqualError(t, err, "dir: invalid parameter")
+	require.Nil(t, b)
+}
@@ -0,0 +1,161 @@
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FormatVersion is the version of the dataset layout, datasets of another format are rebuilt
+	FormatVersion = 1
+
+	manifestFile = "manifest.json"
+)
+
+type (
+	// Manifest describes a dataset: its version, where it was mirrored from and the checksum of
+	// every file. Version is incremented by every run that changes the dataset
+	Manifest struct {
+		Format    int             `json:"format"`
+		Version   int             `json:"version"`
+		Source    string          `json:"source"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		Checksum  string          `json:"checksum"`
+		Files     map[string]File `json:"files"`
+	}
+
+	// File is a dataset file, identified by its slash separated path relative to the dataset root
+	File struct {
+		SHA256 string `json:"sha256"`
+		ETag   string `json:"etag,omitempty"`
+	}
+)
+
+// ReadManifest reads the manifest of the dataset in dir, an empty manifest is returned when dir does
+// not contain a dataset yet
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, manifestFile))
+	if os.IsNotExist(err) {
+		return &Manifest{Format: FormatVersion, Files: map[string]File{}}, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal manifest")
+	}
+
+	if m.Format != FormatVersion {
+		return nil, errors.Errorf("unsupported dataset format %d, expected %d", m.Format, FormatVersion)
+	}
+
+	if m.Files == nil {
+		m.Files = map[string]File{}
+	}
+
+	return m, nil
+}
+
+// Write computes the dataset checksum and atomically writes the manifest to dir
+func (m *Manifest) Write(dir string) error {
+	m.Checksum = m.checksum()
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal manifest")
+	}
+
+	return writeFile(dir, manifestFile, data)
+}
+
+// Verify checks the dataset files in dir match their checksum, the paths of missing or changed
+// files are returned
+func (m *Manifest) Verify(dir string) []string {
+	var invalid []string
+
+	for name, f := range m.Files {
+		if !f.matches(dir, name) {
+			invalid = append(invalid, name)
+		}
+	}
+
+	sort.Strings(invalid)
+
+	return invalid
+}
+
+// checksum is the sha256 of every file path and checksum, in path order
+func (m *Manifest) checksum() string {
+	names := make([]string, 0, len(m.Files))
+	for name := range m.Files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s %s\n", name, m.Files[name].SHA256)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (f File) matches(dir string, name string) bool {
+	data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return false
+	}
+
+	return checksum(data) == f.SHA256
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFile writes data to a temporary file renamed over name, an interrupted run never leaves a
+// partially written file behind
+func writeFile(dir string, name string, data []byte) error {
+	file := filepath.Join(dir, filepath.FromSlash(name))
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return errors.Wrap(err, "failed to create directory")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", name)
+	}
+
+	return errors.Wrapf(os.Rename(tmp.Name(), file), "failed to write %s", name)
+}
@@ -0,0 +1,133 @@
+package funtranslations
+
+import (
+	"context"
+	"pokedex/pkg/adapter/upstream"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCircuitOpen is returned instead of translating while the circuit is open, it matches
+// upstream.ErrUnavailable as Funtranslations most likely still is
+var ErrCircuitOpen = errors.Wrap(upstream.ErrUnavailable, "translation circuit open")
+
+type (
+	// BreakerOptions configures a Breaker
+	BreakerOptions struct {
+		// Failures is the number of consecutive failed translations opening the circuit
+		Failures int
+		// Cooldown is how long the circuit stays open before a translation is attempted again
+		Cooldown time.Duration
+	}
+
+	// Breaker is a Translator failing fast with ErrCircuitOpen once Funtranslations failed Failures
+	// times in a row. After Cooldown a single translation is let through, the circuit is closed when
+	// it succeeds and open again otherwise. Rate limited translations are not failures, Funtranslations
+	// answered them
+	Breaker struct {
+		next Translator
+		opts BreakerOptions
+		now  func() time.Time
+
+		mu       sync.Mutex
+		failures int
+		// openUntil is zero while the circuit is closed
+		openUntil time.Time
+		probing   bool
+	}
+)
+
+// NewBreaker creates a new Breaker in front of next
+func NewBreaker(next Translator, opts BreakerOptions) (*Breaker, error) {
+	if next == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "next")
+	}
+
+	if opts.Failures < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "failures")
+	}
+
+	if opts.Cooldown <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "cooldown")
+	}
+
+	return &Breaker{
+		next: next,
+		opts: opts,
+		now:  time.Now,
+	}, nil
+}
+
+// TranslateToYoda converts English to Yoda speak unless the circuit is open
+func (b *Breaker) TranslateToYoda(ctx context.Context, text string) (string, error) {
+	return b.call(ctx, text, b.next.TranslateToYoda)
+}
+
+// TranslateToShakespeare converts English to Shakespeare unless the circuit is open
+func (b *Breaker) TranslateToShakespeare(ctx context.Context, text string) (string, error) {
+	return b.call(ctx, text, b.next.TranslateToShakespeare)
+}
+
+func (b *Breaker) call(ctx context.Context, text string, translate func(ctx context.Context, text string) (string, error)) (string, error) {
+	probe, err := b.allow()
+	if err != nil {
+		return "", err
+	}
+
+	res, err := translate(ctx, text)
+	b.record(probe, err, ctx.Err() != nil)
+
+	return res, err
+}
+
+// allow returns whether the translation can be attempted, and whether it is the one probing
+// Funtranslations once the cooldown elapsed
+func (b *Breaker) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return false, nil
+	}
+
+	if b.probing || b.now().Before(b.openUntil) {
+		return false, ErrCircuitOpen
+	}
+
+	b.probing = true
+
+	return true, nil
+}
+
+// record counts the outcome of a translation, translations interrupted by their caller are not
+func (b *Breaker) record(probe bool, err error, canceled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	}
+
+	switch {
+	case err == nil:
+		b.failures = 0
+		b.openUntil = time.Time{}
+	case canceled || !failure(err):
+	case probe:
+		b.openUntil = b.now().Add(b.opts.Cooldown)
+	default:
+		b.failures++
+		if b.failures >= b.opts.Failures && b.openUntil.IsZero() {
+			b.openUntil = b.now().Add(b.opts.Cooldown)
+		}
+	}
+}
+
+// failure reports whether err means Funtranslations is failing
+func failure(err error) bool {
+	return errors.Is(err, upstream.ErrUnavailable) ||
+		errors.Is(err, upstream.ErrTimeout) ||
+		errors.Is(err, upstream.ErrBadResponse)
+}
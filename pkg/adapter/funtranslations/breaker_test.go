@@ -0,0 +1,184 @@
+package funtranslations
+
+import (
+	"context"
+	"pokedex/pkg/adapter/upstream"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedTranslator returns the next of its errors on every call, then succeeds
+type scriptedTranslator struct {
+	errs  []error
+	calls int
+}
+
+func (s *scriptedTranslator) TranslateToYoda(ctx context.Context, text string) (string, error) {
+	s.calls++
+
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "yoda: " + text, nil
+}
+
+func (s *scriptedTranslator) TranslateToShakespeare(ctx context.Context, text string) (string, error) {
+	return s.TranslateToYoda(ctx, text)
+}
+
+func TestNewBreaker_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		next Translator
+		opts BreakerOptions
+		err  string
+	}{
+		"ReturnsErrorWhenNextNil": {
+			opts: BreakerOptions{Failures: 1, Cooldown: time.Second},
+			err:  "next: invalid parameter",
+		},
+		"ReturnsErrorWhenFailuresNotPositive": {
+			next: &scriptedTranslator{},
+			opts: BreakerOptions{Cooldown: time.Second},
+			err:  "failures: invalid parameter",
+		},
+		"ReturnsErrorWhenCooldownNotPositive": {
+			next: &scriptedTranslator{},
+			opts: BreakerOptions{Failures: 1},
+			err:  "cooldown: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := NewBreaker(tc.next, tc.opts)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, b)
+		})
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	t.Parallel()
+
+	unavailable := &upstream.ResponseError{Kind: upstream.ErrUnavailable, Service: "funtranslations"}
+	rateLimited := &upstream.ResponseError{Kind: upstream.ErrRateLimited, Service: "funtranslations"}
+
+	type call struct {
+		// after is the time elapsed since the previous call
+		after time.Duration
+		err   error
+	}
+
+	type testcase struct {
+		errs  []error
+		calls []call
+		// upstream is the number of translations reaching next
+		upstream int
+	}
+
+	tests := map[string]testcase{
+		"OpensAfterConsecutiveFailures": {
+			errs: []error{unavailable, unavailable},
+			calls: []call{
+				{err: unavailable},
+				{err: unavailable},
+				{err: ErrCircuitOpen},
+			},
+			upstream: 2,
+		},
+		"SuccessResetsFailures": {
+			errs: []error{unavailable, nil, unavailable},
+			calls: []call{
+				{err: unavailable},
+				{},
+				{err: unavailable},
+				{},
+			},
+			upstream: 4,
+		},
+		"RateLimitedIsNotAFailure": {
+			errs: []error{rateLimited, rateLimited, rateLimited},
+			calls: []call{
+				{err: rateLimited},
+				{err: rateLimited},
+				{err: rateLimited},
+			},
+			upstream: 3,
+		},
+		"ClosesWhenProbeSucceedsAfterCooldown": {
+			errs: []error{unavailable, unavailable},
+			calls: []call{
+				{err: unavailable},
+				{err: unavailable},
+				{after: 30 * time.Second, err: ErrCircuitOpen},
+				{after: 30 * time.Second},
+				{},
+			},
+			upstream: 4,
+		},
+		"OpensAgainWhenProbeFails": {
+			errs: []error{unavailable, unavailable, unavailable},
+			calls: []call{
+				{err: unavailable},
+				{err: unavailable},
+				{after: time.Minute, err: unavailable},
+				{err: ErrCircuitOpen},
+				{after: time.Minute},
+			},
+			upstream: 4,
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			next := &scriptedTranslator{errs: tc.errs}
+
+			b, err := NewBreaker(next, BreakerOptions{Failures: 2, Cooldown: time.Minute})
+			require.NoError(t, err)
+
+			now := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+			b.now = func() time.Time { return now }
+
+			for i, c := range tc.calls {
+				now = now.Add(c.after)
+
+				res, err := b.TranslateToYoda(context.Background(), "hello")
+				if c.err != nil {
+					require.Truef(t, errors.Is(err, c.err), "call %d: %v", i, err)
+
+					continue
+				}
+
+				require.NoErrorf(t, err, "call %d", i)
+				require.Equal(t, "yoda: hello", res)
+			}
+
+			require.Equal(t, tc.upstream, next.calls)
+		})
+	}
+}
+
+func TestErrCircuitOpen(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, ErrCircuitOpen, upstream.ErrUnavailable)
+}
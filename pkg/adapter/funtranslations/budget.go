@@ -0,0 +1,284 @@
+package funtranslations
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"pokedex/pkg/adapter/upstream"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const budgetKey = "funtranslations:budget:%s:%d"
+
+// ErrBudgetExhausted is returned instead of translating once a budget is exhausted, it matches
+// upstream.ErrRateLimited as translating would most likely be rate limited
+var ErrBudgetExhausted = errors.Wrap(upstream.ErrRateLimited, "translation budget exhausted")
+
+type (
+	// Usage counts translations per budget window, e.g. in a cache backend shared by the replicas
+	Usage interface {
+		IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+		Get(ctx context.Context, key string) ([]byte, bool, error)
+	}
+
+	// BudgetOptions configures the budgets of a Budget
+	BudgetOptions struct {
+		// Hourly and Daily limit the translations per hour and day in UTC, unlimited when 0
+		Hourly int
+		Daily  int
+		// Reserve is the share of each budget left unused, e.g. 0.1, so the budget is not exceeded
+		// when other clients use the same key
+		Reserve float64
+		// Quota returns the quota last reported by Funtranslations, when given translations are also
+		// skipped while it is exhausted
+		Quota func() (Quota, bool)
+	}
+
+	// Budget is a Translator keeping within hourly and daily translation budgets. Usage is counted
+	// before translating, in a store shared by the replicas, and translations over budget fail with
+	// ErrBudgetExhausted without calling Funtranslations. A translation is only counted against the
+	// budgets when all of them allow it
+	Budget struct {
+		next  Translator
+		usage Usage
+		now   func() time.Time
+
+		mu   sync.RWMutex
+		opts BudgetOptions
+	}
+
+	// BudgetStatus describes the usage of every budget and the quota last reported by Funtranslations
+	BudgetStatus struct {
+		Exhausted bool    `json:"exhausted"`
+		Hourly    *Window `json:"hourly,omitempty"`
+		Daily     *Window `json:"daily,omitempty"`
+		Upstream  *Quota  `json:"upstream,omitempty"`
+	}
+
+	// Window is the usage of a budget until it resets, Remaining is the number of translations left
+	// before they are skipped
+	Window struct {
+		Limit     int       `json:"limit"`
+		Used      int       `json:"used"`
+		Remaining int       `json:"remaining"`
+		ResetsAt  time.Time `json:"resets_at"`
+	}
+
+	window struct {
+		name     string
+		key      string
+		limit    int
+		allowed  int
+		resetsAt time.Time
+		ttl      time.Duration
+	}
+)
+
+// NewBudget creates a new Budget in front of next
+func NewBudget(next Translator, usage Usage, opts BudgetOptions) (*Budget, error) {
+	if next == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "next")
+	}
+
+	if usage == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "usage")
+	}
+
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Budget{
+		next:  next,
+		usage: usage,
+		opts:  opts,
+		now:   time.Now,
+	}, nil
+}
+
+// SetOptions replaces the budgets, the usage already counted in the current windows is kept
+func (b *Budget) SetOptions(opts BudgetOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.opts = opts
+
+	return nil
+}
+
+func (b *Budget) options() BudgetOptions {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.opts
+}
+
+func (o BudgetOptions) validate() error {
+	if o.Hourly < 0 {
+		return errors.Wrap(ErrInvalidParam, "hourly")
+	}
+
+	if o.Daily < 0 {
+		return errors.Wrap(ErrInvalidParam, "daily")
+	}
+
+	if o.Reserve < 0 || o.Reserve >= 1 {
+		return errors.Wrap(ErrInvalidParam, "reserve")
+	}
+
+	return nil
+}
+
+// TranslateToYoda converts English to Yoda speak within the budgets
+func (b *Budget) TranslateToYoda(ctx context.Context, text string) (string, error) {
+	if err := b.acquire(ctx); err != nil {
+		return "", err
+	}
+
+	return b.next.TranslateToYoda(ctx, text)
+}
+
+// TranslateToShakespeare converts English to Shakespeare within the budgets
+func (b *Budget) TranslateToShakespeare(ctx context.Context, text string) (string, error) {
+	if err := b.acquire(ctx); err != nil {
+		return "", err
+	}
+
+	return b.next.TranslateToShakespeare(ctx, text)
+}
+
+// Status returns the usage of the budgets
+func (b *Budget) Status(ctx context.Context) (BudgetStatus, error) {
+	now := b.now()
+	opts := b.options()
+	status := BudgetStatus{}
+
+	for _, w := range windows(opts, now) {
+		used, err := b.used(ctx, w.key)
+		if err != nil {
+			return BudgetStatus{}, errors.Wrapf(err, "unable to read %s budget usage", w.name)
+		}
+
+		win := &Window{
+			Limit:     w.limit,
+			Used:      used,
+			Remaining: w.allowed - used,
+			ResetsAt:  w.resetsAt,
+		}
+
+		if win.Remaining <= 0 {
+			win.Remaining = 0
+			status.Exhausted = true
+		}
+
+		if w.name == "hourly" {
+			status.Hourly = win
+		} else {
+			status.Daily = win
+		}
+	}
+
+	if q, ok := quota(opts); ok {
+		status.Upstream = &q
+		status.Exhausted = status.Exhausted || q.Exhausted(now)
+	}
+
+	return status, nil
+}
+
+// acquire counts a translation against every budget, it is released from all of them when one is
+// exhausted. The budgets are not enforced while the usage can not be counted
+func (b *Budget) acquire(ctx context.Context) error {
+	now := b.now()
+	opts := b.options()
+
+	if q, ok := quota(opts); ok && q.Exhausted(now) {
+		return errors.Wrap(ErrBudgetExhausted, "upstream quota")
+	}
+
+	var acquired []window
+
+	for _, w := range windows(opts, now) {
+		n, err := b.usage.IncrBy(ctx, w.key, 1, w.ttl)
+		if err != nil {
+			log.Warn().Err(err).Str("budget", w.name).Msg("unable to count translation against budget")
+
+			continue
+		}
+
+		acquired = append(acquired, w)
+
+		if n > int64(w.allowed) {
+			b.release(ctx, acquired)
+
+			return errors.Wrapf(ErrBudgetExhausted, "%s budget", w.name)
+		}
+	}
+
+	return nil
+}
+
+func (b *Budget) release(ctx context.Context, windows []window) {
+	for _, w := range windows {
+		if _, err := b.usage.IncrBy(ctx, w.key, -1, w.ttl); err != nil {
+			log.Warn().Err(err).Str("budget", w.name).Msg("unable to release translation from budget")
+		}
+	}
+}
+
+func (b *Budget) used(ctx context.Context, key string) (int, error) {
+	value, ok, err := b.usage.Get(ctx, key)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	return strconv.Atoi(string(value))
+}
+
+func quota(opts BudgetOptions) (Quota, bool) {
+	if opts.Quota == nil {
+		return Quota{}, false
+	}
+
+	return opts.Quota()
+}
+
+// windows returns the current window of every limited budget, windows are keyed by their start so
+// the replicas share them
+func windows(opts BudgetOptions, now time.Time) []window {
+	var (
+		windows []window
+		hour    = now.UTC().Truncate(time.Hour)
+		day     = time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
+	)
+
+	if opts.Hourly > 0 {
+		windows = append(windows, newWindow("hourly", opts.Hourly, opts.Reserve, hour, hour.Add(time.Hour)))
+	}
+
+	if opts.Daily > 0 {
+		windows = append(windows, newWindow("daily", opts.Daily, opts.Reserve, day, day.AddDate(0, 0, 1)))
+	}
+
+	return windows
+}
+
+func newWindow(name string, limit int, reserve float64, start time.Time, end time.Time) window {
+	return window{
+		name:     name,
+		key:      fmt.Sprintf(budgetKey, name, start.Unix()),
+		limit:    limit,
+		allowed:  int(math.Floor(float64(limit) * (1 - reserve))),
+		resetsAt: end,
+		ttl:      end.Sub(start),
+	}
+}
@@ -0,0 +1,264 @@
+package funtranslations
+
+import (
+	"context"
+	"pokedex/pkg/adapter/upstream"
+	"pokedex/pkg/cache"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// countingTranslator translates by prefixing the style, counting calls
+type countingTranslator struct {
+	calls int32
+}
+
+func (c *countingTranslator) TranslateToYoda(ctx context.Context, text string) (string, error) {
+	atomic.AddInt32(&c.calls, 1)
+
+	return "yoda: " + text, nil
+}
+
+func (c *countingTranslator) TranslateToShakespeare(ctx context.Context, text string) (string, error) {
+	atomic.AddInt32(&c.calls, 1)
+
+	return "shakespeare: " + text, nil
+}
+
+// failingUsage fails every call
+type failingUsage struct{}
+
+func (failingUsage) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return 0, errors.New("usage unavailable")
+}
+
+func (failingUsage) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("usage unavailable")
+}
+
+func TestNewBudget_Error(t *testing.T) {
+	t.Parallel()
+
+	usage, err := cache.NewMemory(10)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		next  Translator
+		usage Usage
+		opts  BudgetOptions
+		err   string
+	}{
+		"ReturnsErrorWhenNextNil": {
+			usage: usage,
+			err:   "next: invalid parameter",
+		},
+		"ReturnsErrorWhenUsageNil": {
+			next: &countingTranslator{},
+			err:  "usage: invalid parameter",
+		},
+		"ReturnsErrorWhenHourlyNegative": {
+			next:  &countingTranslator{},
+			usage: usage,
+			opts:  BudgetOptions{Hourly: -1},
+			err:   "hourly: invalid parameter",
+		},
+		"ReturnsErrorWhenReserveNotAShare": {
+			next:  &countingTranslator{},
+			usage: usage,
+			opts:  BudgetOptions{Reserve: 1},
+			err:   "reserve: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := NewBudget(tc.next, tc.usage, tc.opts)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, b)
+		})
+	}
+}
+
+func TestBudget(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		opts BudgetOptions
+		run  func(t *testing.T, b *Budget, next *countingTranslator, clock *time.Time)
+	}
+
+	translate := func(b *Budget) error {
+		_, err := b.TranslateToYoda(context.Background(), "text")
+
+		return err
+	}
+
+	quotaResetAt := time.Date(2022, 1, 1, 11, 0, 0, 0, time.UTC)
+
+	tests := map[string]testcase{
+		"SkipsTranslationsOnceHourlyBudgetExhausted": {
+			opts: BudgetOptions{Hourly: 2},
+			run: func(t *testing.T, b *Budget, next *countingTranslator, clock *time.Time) {
+				text, err := b.TranslateToYoda(context.Background(), "text")
+				require.NoError(t, err)
+				require.Equal(t, "yoda: text", text)
+
+				_, err = b.TranslateToShakespeare(context.Background(), "text")
+				require.NoError(t, err)
+
+				err = translate(b)
+				require.ErrorIs(t, err, ErrBudgetExhausted)
+				require.ErrorIs(t, err, upstream.ErrRateLimited)
+				require.EqualError(t, err, "hourly budget: translation budget exhausted: upstream rate limited")
+				require.Equal(t, int32(2), atomic.LoadInt32(&next.calls))
+
+				*clock = clock.Add(time.Hour)
+				require.NoError(t, translate(b))
+			},
+		},
+		"KeepsReserveUnused": {
+			opts: BudgetOptions{Hourly: 10, Reserve: 0.25},
+			run: func(t *testing.T, b *Budget, next *countingTranslator, clock *time.Time) {
+				for i := 0; i < 7; i++ {
+					require.NoError(t, translate(b))
+				}
+
+				require.ErrorIs(t, translate(b), ErrBudgetExhausted)
+
+				status, err := b.Status(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, BudgetStatus{
+					Exhausted: true,
+					Hourly: &Window{
+						Limit:     10,
+						Used:      7,
+						Remaining: 0,
+						ResetsAt:  time.Date(2022, 1, 1, 11, 0, 0, 0, time.UTC),
+					},
+				}, status)
+			},
+		},
+		"DoesNotCountSkippedTranslationsAgainstOtherBudgets": {
+			opts: BudgetOptions{Hourly: 1, Daily: 3},
+			run: func(t *testing.T, b *Budget, next *countingTranslator, clock *time.Time) {
+				require.NoError(t, translate(b))
+
+				for i := 0; i < 5; i++ {
+					require.ErrorIs(t, translate(b), ErrBudgetExhausted)
+				}
+
+				status, err := b.Status(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, 1, status.Daily.Used)
+				require.Equal(t, 2, status.Daily.Remaining)
+				require.Equal(t, time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC), status.Daily.ResetsAt)
+
+				*clock = clock.Add(time.Hour)
+				require.NoError(t, translate(b))
+
+				status, err = b.Status(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, 2, status.Daily.Used)
+			},
+		},
+		"AppliesBudgetsSetAfterwardsKeepingUsage": {
+			opts: BudgetOptions{Hourly: 1},
+			run: func(t *testing.T, b *Budget, next *countingTranslator, clock *time.Time) {
+				require.NoError(t, translate(b))
+				require.ErrorIs(t, translate(b), ErrBudgetExhausted)
+
+				require.NoError(t, b.SetOptions(BudgetOptions{Hourly: 2}))
+				require.NoError(t, translate(b))
+				require.ErrorIs(t, translate(b), ErrBudgetExhausted)
+
+				require.EqualError(t, b.SetOptions(BudgetOptions{Daily: -1}), "daily: invalid parameter")
+				require.ErrorIs(t, translate(b), ErrBudgetExhausted)
+
+				require.NoError(t, b.SetOptions(BudgetOptions{}))
+				require.NoError(t, translate(b))
+				require.Equal(t, int32(3), atomic.LoadInt32(&next.calls))
+			},
+		},
+		"SkipsTranslationsWhileUpstreamQuotaExhausted": {
+			opts: BudgetOptions{
+				Quota: func() (Quota, bool) {
+					return Quota{Limit: 60, Remaining: 0, ResetAt: &quotaResetAt}, true
+				},
+			},
+			run: func(t *testing.T, b *Budget, next *countingTranslator, clock *time.Time) {
+				require.ErrorIs(t, translate(b), ErrBudgetExhausted)
+				require.Equal(t, int32(0), atomic.LoadInt32(&next.calls))
+
+				status, err := b.Status(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, BudgetStatus{Exhausted: true, Upstream: &Quota{Limit: 60, Remaining: 0, ResetAt: &quotaResetAt}}, status)
+			},
+		},
+		"TranslatesAgainOnceUpstreamQuotaResets": {
+			opts: BudgetOptions{
+				Quota: func() (Quota, bool) {
+					return Quota{Limit: 60, Remaining: 0, ResetAt: &quotaResetAt}, true
+				},
+			},
+			run: func(t *testing.T, b *Budget, next *countingTranslator, clock *time.Time) {
+				require.ErrorIs(t, translate(b), ErrBudgetExhausted)
+
+				*clock = quotaResetAt
+				require.NoError(t, translate(b))
+				require.Equal(t, int32(1), atomic.LoadInt32(&next.calls))
+
+				status, err := b.Status(context.Background())
+				require.NoError(t, err)
+				require.False(t, status.Exhausted)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			usage, err := cache.NewMemory(10)
+			require.NoError(t, err)
+
+			next := &countingTranslator{}
+
+			b, err := NewBudget(next, usage, tc.opts)
+			require.NoError(t, err)
+
+			clock := time.Date(2022, 1, 1, 10, 30, 0, 0, time.UTC)
+			b.now = func() time.Time { return clock }
+
+			tc.run(t, b, next, &clock)
+		})
+	}
+}
+
+func TestBudget_UsageUnavailable(t *testing.T) {
+	t.Parallel()
+
+	next := &countingTranslator{}
+
+	b, err := NewBudget(next, failingUsage{}, BudgetOptions{Hourly: 1})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := b.TranslateToYoda(context.Background(), "text")
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(3), atomic.LoadInt32(&next.calls))
+
+	_, err = b.Status(context.Background())
+	require.EqualError(t, err, "unable to read hourly budget usage: usage unavailable")
+}
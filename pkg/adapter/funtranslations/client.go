@@ -1,16 +1,20 @@
 package funtranslations
 
 import (
-	"context"
-	"encoding/json"
-	"io/ioutil"
-	"net/http"
+	"pokedex/pkg/http/client"
+	"pokedex/pkg/secret"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
 const (
-	path = "%s/translate/%s.json"
+	service = "funtranslations"
+
+	path = "/translate/%s.json"
+
+	secretHeader = "X-Funtranslations-Api-Secret"
 )
 
 var ErrInvalidParam = errors.New("invalid parameter")
@@ -18,43 +22,63 @@ var ErrInvalidParam = errors.New("invalid parameter")
 type (
 	// Client represents a Funtranslations client for making request
 	Client struct {
-		httpClient *http.Client
-		url        string
+		http *client.Client
+		now  func() time.Time
+
+		mu     sync.RWMutex
+		secret secret.Source
+		quota  *Quota
 	}
 )
 
-// New creates a new Funtranslations client
-func New(url string) (*Client, error) {
+// New creates a new Funtranslations client, options configure the underlying http client
+func New(url string, opts ...client.Option) (*Client, error) {
 	if url == "" {
 		return nil, errors.Wrap(ErrInvalidParam, "url")
 	}
 
-	return &Client{
-		httpClient: &http.Client{},
-		url:        url,
-	}, nil
-}
+	c := &Client{now: time.Now}
 
-func (c *Client) execute(ctx context.Context, req *http.Request, responseVal interface{}) (*http.Response, error) {
-	req.Header.Set("Content-Type", "application/json")
+	opts = append(opts, client.WithMiddleware(client.Auth(secretHeader, c.secretSource)))
 
-	res, err := c.httpClient.Do(req)
+	httpClient, err := client.New(service, url, opts...)
 	if err != nil {
-		return nil, errors.Wrap(err, "error sending request")
+		return nil, errors.Wrap(err, "failed to create http client")
 	}
 
-	if res.StatusCode == http.StatusOK {
-		defer res.Body.Close()
+	c.http = httpClient
 
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error reading body")
-		}
+	return c, nil
+}
 
-		if err := json.Unmarshal(body, responseVal); err != nil {
-			return res, errors.Wrap(err, "error unmarshaling response")
-		}
+// Reconfigure changes the url and timeout used by subsequent requests, it is safe to call while
+// requests are in flight
+func (c *Client) Reconfigure(url string, timeout time.Duration) error {
+	if url == "" {
+		return errors.Wrap(ErrInvalidParam, "url")
 	}
 
-	return res, nil
+	return c.http.Reconfigure(url, timeout)
+}
+
+// SetDecoding changes the response size limit and strict mode used by subsequent requests, it is
+// safe to call while requests are in flight
+func (c *Client) SetDecoding(maxResponseSize int64, strict bool) error {
+	return c.http.SetDecoding(maxResponseSize, strict)
+}
+
+// SetSecret changes the source of the API secret sent with subsequent requests, required by the
+// paid tier. A nil source disables it
+func (c *Client) SetSecret(source secret.Source) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.secret = source
+}
+
+func (c *Client) secretSource() secret.Source {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.secret
 }
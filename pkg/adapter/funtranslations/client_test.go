@@ -1,12 +1,9 @@
 package funtranslations
 
 import (
-	"context"
 	"net/http"
-	"net/http/httptest"
 	"testing"
 
-	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -58,103 +55,3 @@ func TestNew_Success(t *testing.T) {
 		})
 	}
 }
-
-func TestExecute_Error(t *testing.T) {
-	t.Parallel()
-
-	cases := map[string]struct {
-		checkErr func(t *testing.T, err error)
-		server   *httptest.Server
-	}{
-		"ReturnsErrorWhenInvalidJSONResponse": {
-			checkErr: func(t *testing.T, err error) {
-				require.Error(t, err)
-			},
-			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				_, err := w.Write([]byte("not-a-json"))
-				require.NoError(t, err)
-
-				w.WriteHeader(http.StatusTeapot)
-			})),
-		},
-	}
-
-	for description, testCase := range cases {
-		tc := testCase
-
-		t.Run(description, func(t *testing.T) {
-			t.Parallel()
-
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			defer tc.server.Close()
-
-			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
-
-			req, err := http.NewRequest(http.MethodPost, tc.server.URL, nil)
-			require.NoError(t, err)
-
-			var val TranslateResponse
-			res, err := client.execute(ctx, req, &val)
-
-			res.Body.Close()
-
-			tc.checkErr(t, err)
-		})
-	}
-}
-
-func TestExecute_Success(t *testing.T) {
-	t.Parallel()
-
-	cases := map[string]struct {
-		checkErr func(t *testing.T, err error)
-		server   *httptest.Server
-	}{
-		"ReturnsNilWhenValidJSONResponse": {
-			checkErr: func(t *testing.T, err error) {
-				require.NoError(t, err)
-			},
-			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				_, err := w.Write([]byte(`{"name":"mewtwo"}`))
-				require.NoError(t, err)
-
-				w.WriteHeader(http.StatusTeapot)
-			})),
-		},
-	}
-
-	for description, testCase := range cases {
-		tc := testCase
-
-		t.Run(description, func(t *testing.T) {
-			t.Parallel()
-
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			defer tc.server.Close()
-
-			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
-
-			req, err := http.NewRequest(http.MethodPost, tc.server.URL, nil)
-			require.NoError(t, err)
-
-			var val TranslateResponse
-			res, err := client.execute(ctx, req, &val)
-
-			res.Body.Close()
-
-			tc.checkErr(t, err)
-		})
-	}
-}
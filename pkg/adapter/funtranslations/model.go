@@ -1,5 +1,7 @@
 package funtranslations
 
+import "github.com/pkg/errors"
+
 type (
 	// TranslateRequest request with a text for Funtranslations API
 	TranslateRequest struct {
@@ -18,3 +20,12 @@ type (
 		Translation string `json:"translation"`
 	}
 )
+
+// Validate checks the fields the service relies on are present, used by the strict mode of the client
+func (r *TranslateResponse) Validate() error {
+	if r.Contents.Translated == "" {
+		return errors.New("missing contents.translated")
+	}
+
+	return nil
+}
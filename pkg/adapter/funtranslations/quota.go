@@ -0,0 +1,114 @@
+package funtranslations
+
+import (
+	"context"
+	"net/http"
+	"pokedex/pkg/adapter/upstream"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	limitHeader     = "X-RateLimit-Limit"
+	remainingHeader = "X-RateLimit-Remaining"
+)
+
+// Quota is the translation quota last reported by Funtranslations. Limit and Remaining are -1 when
+// the responses did not carry them. ResetAt is set once no translation is left, from the Retry-After
+// hint when one was given and otherwise at the next hour as the quota is replenished hourly
+type Quota struct {
+	Limit     int        `json:"limit"`
+	Remaining int        `json:"remaining"`
+	ResetAt   *time.Time `json:"reset_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Exhausted reports whether no translation is left at now, the quota is only reported again by a
+// translation so it is no longer exhausted after ResetAt
+func (q Quota) Exhausted(now time.Time) bool {
+	return q.ResetAt != nil && now.Before(*q.ResetAt)
+}
+
+// Quota returns the quota reported by the last translation, false before any translation
+func (c *Client) Quota() (Quota, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.quota == nil {
+		return Quota{}, false
+	}
+
+	return *c.quota, true
+}
+
+// Ping checks Funtranslations is reachable without using the quota
+func (c *Client) Ping(ctx context.Context) error {
+	return c.http.Ping(ctx, "/")
+}
+
+// Check fails when Funtranslations is unreachable or its quota is exhausted until it resets, the
+// quota is returned as details once known. It is meant as a readiness check
+func (c *Client) Check(ctx context.Context) (interface{}, error) {
+	if err := c.Ping(ctx); err != nil {
+		return nil, err
+	}
+
+	q, ok := c.Quota()
+	if !ok {
+		return nil, nil
+	}
+
+	if q.Exhausted(c.now()) {
+		return q, errors.New("quota exhausted")
+	}
+
+	return q, nil
+}
+
+// recordQuota updates the quota from the response to a translation, header is nil when no
+// response was received
+func (c *Client) recordQuota(header http.Header, err error) {
+	if header == nil {
+		return
+	}
+
+	var respErr *upstream.ResponseError
+	errors.As(err, &respErr)
+
+	now := c.now()
+	q := Quota{
+		Limit:     headerInt(header, limitHeader),
+		Remaining: headerInt(header, remainingHeader),
+		UpdatedAt: now,
+	}
+
+	resetAt := now.Truncate(time.Hour).Add(time.Hour)
+
+	if respErr != nil && errors.Is(respErr, upstream.ErrRateLimited) {
+		q.Remaining = 0
+
+		if respErr.RetryAfter > 0 {
+			resetAt = now.Add(respErr.RetryAfter)
+		}
+	}
+
+	if q.Remaining == 0 {
+		q.ResetAt = &resetAt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.quota = &q
+}
+
+func headerInt(header http.Header, key string) int {
+	n, err := strconv.Atoi(header.Get(key))
+	if err != nil {
+		return -1
+	}
+
+	return n
+}
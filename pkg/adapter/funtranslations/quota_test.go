@@ -0,0 +1,164 @@
+package funtranslations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Quota(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		handler   http.HandlerFunc
+		exhausted bool
+		check     func(t *testing.T, q Quota)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsRemainingFromHeaders": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-RateLimit-Limit", "60")
+				w.Header().Set("X-RateLimit-Remaining", "59")
+
+				_, _ = w.Write([]byte(`{"contents": {"translated": "translated"}}`))
+			},
+			check: func(t *testing.T, q Quota) {
+				require.Equal(t, 60, q.Limit)
+				require.Equal(t, 59, q.Remaining)
+				require.Nil(t, q.ResetAt)
+			},
+		},
+		"ReturnsUnknownWhenHeadersMissing": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				_, _ = w.Write([]byte(`{"contents": {"translated": "translated"}}`))
+			},
+			check: func(t *testing.T, q Quota) {
+				require.Equal(t, -1, q.Limit)
+				require.Equal(t, -1, q.Remaining)
+			},
+		},
+		"ReturnsExhaustedUntilRetryAfterWhenRateLimited": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			exhausted: true,
+			check: func(t *testing.T, q Quota) {
+				require.Equal(t, 0, q.Remaining)
+				require.NotNil(t, q.ResetAt)
+				require.WithinDuration(t, time.Now().Add(time.Hour), *q.ResetAt, time.Minute)
+				require.False(t, q.Exhausted(q.ResetAt.Add(time.Second)))
+			},
+		},
+		"ReturnsExhaustedUntilNextHourWhenRateLimitedWithoutRetryAfter": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			exhausted: true,
+			check: func(t *testing.T, q Quota) {
+				require.Equal(t, 0, q.Remaining)
+				require.NotNil(t, q.ResetAt)
+				require.Equal(t, q.UpdatedAt.Truncate(time.Hour).Add(time.Hour), *q.ResetAt)
+				require.False(t, q.Exhausted(q.ResetAt.Add(time.Second)))
+			},
+		},
+		"ReturnsExhaustedUntilNextHourWhenNoneRemaining": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-RateLimit-Limit", "60")
+				w.Header().Set("X-RateLimit-Remaining", "0")
+
+				_, _ = w.Write([]byte(`{"contents": {"translated": "translated"}}`))
+			},
+			exhausted: true,
+			check: func(t *testing.T, q Quota) {
+				require.NotNil(t, q.ResetAt)
+				require.Equal(t, q.UpdatedAt.Truncate(time.Hour).Add(time.Hour), *q.ResetAt)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			c, err := New(server.URL)
+			require.NoError(t, err)
+
+			_, ok := c.Quota()
+			require.False(t, ok)
+
+			_, _ = c.TranslateToYoda(context.Background(), "text")
+
+			q, ok := c.Quota()
+			require.True(t, ok)
+			require.Equal(t, tc.exhausted, q.Exhausted(time.Now()))
+			tc.check(t, q)
+		})
+	}
+}
+
+func TestClient_Check(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			return
+		}
+
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	require.NoError(t, err)
+
+	now := time.Date(2022, 1, 1, 10, 30, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	_, err = c.Check(context.Background())
+	require.NoError(t, err)
+
+	_, _ = c.TranslateToYoda(context.Background(), "text")
+
+	_, err = c.Check(context.Background())
+	require.EqualError(t, err, "quota exhausted")
+
+	now = time.Date(2022, 1, 1, 11, 0, 0, 0, time.UTC)
+
+	details, err := c.Check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, details.(Quota).Remaining)
+}
+
+func TestClient_Ping(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/", r.URL.Path)
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Ping(context.Background()))
+
+	_, ok := c.Quota()
+	require.False(t, ok)
+}
@@ -1,11 +1,10 @@
 package funtranslations
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"pokedex/pkg/http/client"
 
 	"github.com/pkg/errors"
 )
@@ -30,27 +29,17 @@ func (c *Client) TranslateToShakespeare(ctx context.Context, text string) (strin
 }
 
 func (c *Client) translate(ctx context.Context, text string, to string) (string, error) {
-	data, err := json.Marshal(TranslateRequest{Text: text})
-	if err != nil {
-		return "", errors.Wrapf(err, "error marshalling translate to %s request", to)
-	}
+	var (
+		raw    = &TranslateResponse{}
+		header http.Header
+	)
 
-	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf(path, c.url, to), bytes.NewBuffer(data))
-	if err != nil {
-		return "", errors.Wrap(err, "error creating http request")
-	}
+	err := c.http.DoJSON(ctx, http.MethodPost, fmt.Sprintf(path, to), TranslateRequest{Text: text}, raw, client.WithResponseHeader(&header))
+	c.recordQuota(header, err)
 
-	raw := &TranslateResponse{}
-
-	// requires nolint rule as body on this stage is already closed and linter doesn't pick that up
-	res, err := c.execute(ctx, httpReq, raw) // nolint:bodyclose
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to execute translate to %s request", to)
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return "", errors.Errorf("invalid response code: %s", res.Status)
-	}
-
 	return raw.Contents.Translated, nil
 }
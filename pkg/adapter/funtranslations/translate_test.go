@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"pokedex/pkg/secret"
 	"strings"
 	"testing"
 
@@ -22,6 +23,7 @@ func TestTranslate_Error(t *testing.T) {
 		"ReturnsErrorWhenFailedToExecute": {
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 
 				_, err := w.Write([]byte("not-a-json"))
@@ -51,10 +53,8 @@ func TestTranslate_Error(t *testing.T) {
 			defer tc.server.Close()
 
 			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
+			client, err := New(tc.server.URL)
+			require.NoError(t, err)
 
 			res, err := client.TranslateToYoda(ctx, "text to translate")
 
@@ -76,6 +76,7 @@ func TestTranslate_Success(t *testing.T) {
 		"ReturnsTranslationWhenNoErrorOccured": {
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 
 				_, err := w.Write([]byte(`{"contents": { "translated": "text translated to Yoda"}}`))
@@ -97,10 +98,8 @@ func TestTranslate_Success(t *testing.T) {
 			defer tc.server.Close()
 
 			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
+			client, err := New(tc.server.URL)
+			require.NoError(t, err)
 
 			res, err := client.TranslateToYoda(ctx, "text to translate")
 
@@ -122,6 +121,7 @@ func TestTranslateToYoda_Success(t *testing.T) {
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
 				require.True(t, strings.Contains(r.RequestURI, "yoda"))
+				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 
 				_, err := w.Write([]byte(`{"contents": { "translated": "text translated to Yoda"}}`))
@@ -142,12 +142,10 @@ func TestTranslateToYoda_Success(t *testing.T) {
 			defer tc.server.Close()
 
 			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
+			client, err := New(tc.server.URL)
+			require.NoError(t, err)
 
-			_, err := client.TranslateToYoda(ctx, "text to translate")
+			_, err = client.TranslateToYoda(ctx, "text to translate")
 
 			require.NoError(t, err)
 		})
@@ -166,6 +164,7 @@ func TestTranslateToShakespeare_Success(t *testing.T) {
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
 				require.True(t, strings.Contains(r.RequestURI, "shakespeare"))
+				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 
 				_, err := w.Write([]byte(`{"contents": { "translated": "text translated to Shakespeare"}}`))
@@ -186,14 +185,93 @@ func TestTranslateToShakespeare_Success(t *testing.T) {
 			defer tc.server.Close()
 
 			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
+			client, err := New(tc.server.URL)
+			require.NoError(t, err)
 
-			_, err := client.TranslateToShakespeare(ctx, "text to translate")
+			_, err = client.TranslateToShakespeare(ctx, "text to translate")
 
 			require.NoError(t, err)
 		})
 	}
 }
+
+func TestTranslate_Secret(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		source   secret.Source
+		server   *httptest.Server
+		checkErr func(t *testing.T, err error)
+	}
+
+	tests := map[string]testcase{
+		"SendsSecretHeader": {
+			source: secret.Static("s3cr3t"),
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "s3cr3t", r.Header.Get("X-Funtranslations-Api-Secret"))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+
+				_, err := w.Write([]byte(`{"contents": { "translated": "text translated to Yoda"}}`))
+				require.NoError(t, err)
+			})),
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"OmitsSecretHeaderWhenNoSource": {
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, ok := r.Header["X-Funtranslations-Api-Secret"]
+				require.False(t, ok)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+
+				_, err := w.Write([]byte(`{"contents": { "translated": "text translated to Yoda"}}`))
+				require.NoError(t, err)
+			})),
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"RedactsSecretFromErrors": {
+			source: secret.Static("s3cr3t"),
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/s3cr3t" {
+					http.Redirect(w, r, "/s3cr3t", http.StatusFound)
+
+					return
+				}
+
+				// the transport error names the redirected url
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				conn.Close()
+			})),
+			checkErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "[REDACTED]")
+				require.NotContains(t, err.Error(), "s3cr3t")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			defer tc.server.Close()
+
+			ctx := context.Background()
+			client, err := New(tc.server.URL)
+			require.NoError(t, err)
+
+			client.SetSecret(tc.source)
+
+			_, err = client.TranslateToYoda(ctx, "text to translate")
+
+			tc.checkErr(t, err)
+		})
+	}
+}
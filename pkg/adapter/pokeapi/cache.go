@@ -0,0 +1,139 @@
+package pokeapi
+
+import (
+	"context"
+	"pokedex/pkg/cache"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cacheVersion is the version of the cached Pokemon serialisation, it must be bumped when Pokemon
+// changes in a way older replicas could not read
+const cacheVersion = 1
+
+// Cache serves Pokemons from a cache backend, Pokemons fetched on a miss are cached for ttl.
+// Unknown Pokemons are not cached. Every Pokemon returned is a copy the caller may modify
+type Cache struct {
+	next     PokemonFetcher
+	store    *cache.Store
+	requests *cache.Counter
+
+	mu  sync.RWMutex
+	ttl time.Duration
+}
+
+// uncounted fetches through the cache without counting requests
+type uncounted struct {
+	c *Cache
+}
+
+// NewCache creates a new Cache in front of next. Fetched Pokemons are counted in requests, when
+// given, to find the most requested ones
+func NewCache(next PokemonFetcher, backend cache.Backend, ttl time.Duration, requests *cache.Counter) (*Cache, error) {
+	if next == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "next")
+	}
+
+	if backend == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "backend")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "ttl")
+	}
+
+	store, err := cache.NewStore(backend, "pokemon", cacheVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cache{
+		next:     next,
+		store:    store,
+		ttl:      ttl,
+		requests: requests,
+	}, nil
+}
+
+// FetchByName returns Pokemon details by a given name
+func (c *Cache) FetchByName(ctx context.Context, name string) (*Pokemon, error) {
+	pok, err := c.fetch(ctx, name)
+	if err == nil && pok != nil && c.requests != nil {
+		c.requests.Inc(name)
+	}
+
+	return pok, err
+}
+
+// Uncounted returns a fetcher going through the cache without counting requests, e.g. to warm it up
+func (c *Cache) Uncounted() PokemonFetcher {
+	return uncounted{c: c}
+}
+
+// Lookup returns the cached Pokemon without fetching it on a miss
+func (c *Cache) Lookup(ctx context.Context, name string) (*Pokemon, bool) {
+	var pok Pokemon
+	if !c.store.Peek(ctx, name, &pok) {
+		return nil, false
+	}
+
+	return &pok, true
+}
+
+// Purge removes the cached Pokemons whose name matches, it returns the number removed
+func (c *Cache) Purge(ctx context.Context, match func(name string) bool) int {
+	return c.store.Delete(ctx, match)
+}
+
+// Stats returns the content and usage of the cache
+func (c *Cache) Stats(ctx context.Context) cache.Stats {
+	return c.store.Stats(ctx)
+}
+
+// Ping checks the cache backend is reachable
+func (c *Cache) Ping(ctx context.Context) error {
+	return c.store.Ping(ctx)
+}
+
+// SetTTL changes how long Pokemons cached afterwards are kept
+func (c *Cache) SetTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.Wrap(ErrInvalidParam, "ttl")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ttl = ttl
+
+	return nil
+}
+
+func (c *Cache) getTTL() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.ttl
+}
+
+func (c *Cache) fetch(ctx context.Context, name string) (*Pokemon, error) {
+	var cached Pokemon
+	if c.store.Get(ctx, name, &cached) {
+		return &cached, nil
+	}
+
+	pok, err := c.next.FetchByName(ctx, name)
+	if err != nil || pok == nil {
+		return pok, err
+	}
+
+	c.store.Set(ctx, name, pok, c.getTTL())
+
+	return pok, nil
+}
+
+func (u uncounted) FetchByName(ctx context.Context, name string) (*Pokemon, error) {
+	return u.c.fetch(ctx, name)
+}
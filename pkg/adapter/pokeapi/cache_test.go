@@ -0,0 +1,221 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"pokedex/pkg/cache"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCache_Error(t *testing.T) {
+	t.Parallel()
+
+	store, err := cache.NewMemory(10)
+	require.NoError(t, err)
+
+	next := fetcherFunc(func(ctx context.Context, name string) (*Pokemon, error) { return nil, nil })
+
+	tests := map[string]struct {
+		next    PokemonFetcher
+		backend cache.Backend
+		ttl     time.Duration
+		err     string
+	}{
+		"ReturnsErrorWhenNextNil": {
+			backend: store,
+			ttl:     time.Hour,
+			err:     "next: invalid parameter",
+		},
+		"ReturnsErrorWhenBackendNil": {
+			next: next,
+			ttl:  time.Hour,
+			err:  "backend: invalid parameter",
+		},
+		"ReturnsErrorWhenTTLNotPositive": {
+			next:    next,
+			backend: store,
+			err:     "ttl: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCache(tc.next, tc.backend, tc.ttl, nil)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, c)
+		})
+	}
+}
+
+func TestCache_FetchByName(t *testing.T) {
+	t.Parallel()
+
+	errFetch := errors.New("fetch failed")
+
+	type testcase struct {
+		next func(calls *int) fetcherFunc
+		run  func(t *testing.T, c *Cache, requests *cache.Counter, calls *int)
+	}
+
+	tests := map[string]testcase{
+		"FetchesOnceAndCountsRequests": {
+			next: func(calls *int) fetcherFunc {
+				return func(ctx context.Context, name string) (*Pokemon, error) {
+					*calls++
+
+					return &Pokemon{Name: name, Description: "live"}, nil
+				}
+			},
+			run: func(t *testing.T, c *Cache, requests *cache.Counter, calls *int) {
+				for i := 0; i < 3; i++ {
+					pok, err := c.FetchByName(context.Background(), "mewtwo")
+					require.NoError(t, err)
+					require.Equal(t, "live", pok.Description)
+				}
+
+				require.Equal(t, 1, *calls)
+				require.Equal(t, []cache.NameCount{{Name: "mewtwo", Count: 3}}, requests.Top(10))
+				require.Equal(t, cache.Stats{Entries: 1, Hits: 2, Misses: 1}, c.Stats(context.Background()))
+			},
+		},
+		"ReturnsCopiesOfCachedPokemon": {
+			next: func(calls *int) fetcherFunc {
+				return func(ctx context.Context, name string) (*Pokemon, error) {
+					return &Pokemon{Name: name, Description: "live"}, nil
+				}
+			},
+			run: func(t *testing.T, c *Cache, requests *cache.Counter, calls *int) {
+				pok, err := c.FetchByName(context.Background(), "mewtwo")
+				require.NoError(t, err)
+				pok.Description = "changed"
+
+				pok, err = c.FetchByName(context.Background(), "mewtwo")
+				require.NoError(t, err)
+				require.Equal(t, "live", pok.Description)
+				pok.Description = "changed"
+
+				cached, ok := c.Lookup(context.Background(), "mewtwo")
+				require.True(t, ok)
+				require.Equal(t, "live", cached.Description)
+			},
+		},
+		"DoesNotCacheOrCountUnknownPokemonsAndErrors": {
+			next: func(calls *int) fetcherFunc {
+				return func(ctx context.Context, name string) (*Pokemon, error) {
+					*calls++
+
+					if name == "missingno" {
+						return nil, nil
+					}
+
+					return nil, errFetch
+				}
+			},
+			run: func(t *testing.T, c *Cache, requests *cache.Counter, calls *int) {
+				for i := 0; i < 2; i++ {
+					pok, err := c.FetchByName(context.Background(), "missingno")
+					require.NoError(t, err)
+					require.Nil(t, pok)
+
+					_, err = c.FetchByName(context.Background(), "mewtwo")
+					require.ErrorIs(t, err, errFetch)
+				}
+
+				require.Equal(t, 4, *calls)
+				require.Empty(t, requests.Top(10))
+				require.Equal(t, 0, c.Stats(context.Background()).Entries)
+			},
+		},
+		"UncountedDoesNotCountRequests": {
+			next: func(calls *int) fetcherFunc {
+				return func(ctx context.Context, name string) (*Pokemon, error) {
+					*calls++
+
+					return &Pokemon{Name: name}, nil
+				}
+			},
+			run: func(t *testing.T, c *Cache, requests *cache.Counter, calls *int) {
+				_, err := c.Uncounted().FetchByName(context.Background(), "mewtwo")
+				require.NoError(t, err)
+
+				_, err = c.FetchByName(context.Background(), "mewtwo")
+				require.NoError(t, err)
+
+				require.Equal(t, 1, *calls)
+				require.Equal(t, []cache.NameCount{{Name: "mewtwo", Count: 1}}, requests.Top(10))
+			},
+		},
+		"CachesForTTLSetAfterwards": {
+			next: func(calls *int) fetcherFunc {
+				return func(ctx context.Context, name string) (*Pokemon, error) {
+					*calls++
+
+					return &Pokemon{Name: name}, nil
+				}
+			},
+			run: func(t *testing.T, c *Cache, requests *cache.Counter, calls *int) {
+				require.EqualError(t, c.SetTTL(0), "ttl: invalid parameter")
+				require.NoError(t, c.SetTTL(time.Millisecond))
+
+				_, err := c.FetchByName(context.Background(), "mewtwo")
+				require.NoError(t, err)
+				time.Sleep(5 * time.Millisecond)
+
+				_, err = c.FetchByName(context.Background(), "mewtwo")
+				require.NoError(t, err)
+
+				require.Equal(t, 2, *calls)
+			},
+		},
+		"PurgesMatchingPokemons": {
+			next: func(calls *int) fetcherFunc {
+				return func(ctx context.Context, name string) (*Pokemon, error) {
+					return &Pokemon{Name: name}, nil
+				}
+			},
+			run: func(t *testing.T, c *Cache, requests *cache.Counter, calls *int) {
+				for _, name := range []string{"mew", "mewtwo", "ditto"} {
+					_, err := c.FetchByName(context.Background(), name)
+					require.NoError(t, err)
+				}
+
+				n := c.Purge(context.Background(), func(name string) bool { return strings.HasPrefix(name, "mew") })
+				require.Equal(t, 2, n)
+
+				_, ok := c.Lookup(context.Background(), "mew")
+				require.False(t, ok)
+				_, ok = c.Lookup(context.Background(), "ditto")
+				require.True(t, ok)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			store, err := cache.NewMemory(10)
+			require.NoError(t, err)
+
+			requests, err := cache.NewCounter(10)
+			require.NoError(t, err)
+
+			calls := 0
+
+			c, err := NewCache(tc.next(&calls), store, time.Hour, requests)
+			require.NoError(t, err)
+
+			tc.run(t, c, requests, &calls)
+		})
+	}
+}
@@ -2,15 +2,16 @@ package pokeapi
 
 import (
 	"context"
-	"encoding/json"
-	"io/ioutil"
-	"net/http"
+	"pokedex/pkg/http/client"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
 const (
-	path = "%s/api/v2/%s/%s"
+	service = "pokeapi"
+
+	path = "/api/v2/%s/%s"
 )
 
 var ErrInvalidParam = errors.New("invalid parameter")
@@ -18,43 +19,43 @@ var ErrInvalidParam = errors.New("invalid parameter")
 type (
 	// Client represents a PokeAPI client for making request.
 	Client struct {
-		httpClient *http.Client
-		url        string
+		http *client.Client
 	}
 )
 
-// New creates a new PokeAPI client
-func New(url string) (*Client, error) {
+// New creates a new PokeAPI client, options configure the underlying http client
+func New(url string, opts ...client.Option) (*Client, error) {
 	if url == "" {
 		return nil, errors.Wrap(ErrInvalidParam, "url")
 	}
 
+	httpClient, err := client.New(service, url, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create http client")
+	}
+
 	return &Client{
-		httpClient: &http.Client{},
-		url:        url,
+		http: httpClient,
 	}, nil
 }
 
-func (c *Client) execute(ctx context.Context, req *http.Request, responseVal interface{}) (*http.Response, error) {
-	req.Header.Set("Content-Type", "application/json")
-
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "error sending request")
+// Reconfigure changes the url and timeout used by subsequent requests, it is safe to call while
+// requests are in flight
+func (c *Client) Reconfigure(url string, timeout time.Duration) error {
+	if url == "" {
+		return errors.Wrap(ErrInvalidParam, "url")
 	}
 
-	if res.StatusCode == http.StatusOK {
-		defer res.Body.Close()
-
-		body, err := ioutil.ReadAll(res.Body)
-		if err != nil {
-			return nil, errors.Wrap(err, "error reading body")
-		}
+	return c.http.Reconfigure(url, timeout)
+}
 
-		if err := json.Unmarshal(body, responseVal); err != nil {
-			return res, errors.Wrap(err, "error unmarshaling response")
-		}
-	}
+// SetDecoding changes the response size limit and strict mode used by subsequent requests, it is
+// safe to call while requests are in flight
+func (c *Client) SetDecoding(maxResponseSize int64, strict bool) error {
+	return c.http.SetDecoding(maxResponseSize, strict)
+}
 
-	return res, nil
+// Ping checks PokeAPI is reachable by requesting the api index
+func (c *Client) Ping(ctx context.Context) error {
+	return c.http.Ping(ctx, "/api/v2/")
 }
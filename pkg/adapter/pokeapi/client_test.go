@@ -5,8 +5,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
-	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,102 +59,59 @@ func TestNew_Success(t *testing.T) {
 	}
 }
 
-func TestExecute_Error(t *testing.T) {
+func TestReconfigure(t *testing.T) {
 	t.Parallel()
 
-	cases := map[string]struct {
-		checkErr func(t *testing.T, err error)
-		server   *httptest.Server
-	}{
-		"ReturnsErrorWhenInvalidJSONResponse": {
-			checkErr: func(t *testing.T, err error) {
-				require.Error(t, err)
-			},
-			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				_, err := w.Write([]byte("not-a-json"))
-				require.NoError(t, err)
-
-				w.WriteHeader(http.StatusTeapot)
-			})),
-		},
-	}
-
-	for description, testCase := range cases {
-		tc := testCase
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
 
-		t.Run(description, func(t *testing.T) {
-			t.Parallel()
+		_, err := w.Write([]byte(`{"name":"first"}`))
+		require.NoError(t, err)
+	}))
+	defer first.Close()
 
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
+	second := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
 
-			defer tc.server.Close()
+		_, err := w.Write([]byte(`{"name":"second"}`))
+		require.NoError(t, err)
+	}))
+	defer second.Close()
 
-			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
+	client, err := New(first.URL)
+	require.NoError(t, err)
 
-			req, err := http.NewRequest(http.MethodPost, tc.server.URL, nil)
-			require.NoError(t, err)
+	pok, err := client.FetchByName(context.Background(), "mewtwo")
+	require.NoError(t, err)
+	require.Equal(t, "first", pok.Name)
 
-			var val PokemonResponse
-			res, err := client.execute(ctx, req, &val)
+	err = client.Reconfigure(second.URL, time.Second)
+	require.NoError(t, err)
 
-			res.Body.Close()
+	pok, err = client.FetchByName(context.Background(), "mewtwo")
+	require.NoError(t, err)
+	require.Equal(t, "second", pok.Name)
 
-			tc.checkErr(t, err)
-		})
-	}
+	err = client.Reconfigure("", time.Second)
+	require.EqualError(t, err, "url: invalid parameter")
 }
 
-func TestExecute_Success(t *testing.T) {
+func TestPing(t *testing.T) {
 	t.Parallel()
 
-	cases := map[string]struct {
-		checkErr func(t *testing.T, err error)
-		server   *httptest.Server
-	}{
-		"ReturnsNilWhenValidJSONResponse": {
-			checkErr: func(t *testing.T, err error) {
-				require.NoError(t, err)
-			},
-			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				_, err := w.Write([]byte(`{"name":"mewtwo"}`))
-				require.NoError(t, err)
-
-				w.WriteHeader(http.StatusTeapot)
-			})),
-		},
-	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v2/", r.URL.Path)
 
-	for description, testCase := range cases {
-		tc := testCase
-
-		t.Run(description, func(t *testing.T) {
-			t.Parallel()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"pokemon":"https://pokeapi.co/api/v2/pokemon/"}`))
+	}))
 
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
+	c, err := New(server.URL)
+	require.NoError(t, err)
 
-			defer tc.server.Close()
+	require.NoError(t, c.Ping(context.Background()))
 
-			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
+	server.Close()
 
-			req, err := http.NewRequest(http.MethodPost, tc.server.URL, nil)
-			require.NoError(t, err)
-
-			var val PokemonResponse
-			res, err := client.execute(ctx, req, &val)
-
-			res.Body.Close()
-
-			tc.checkErr(t, err)
-		})
-	}
+	require.Error(t, c.Ping(context.Background()))
 }
@@ -0,0 +1,40 @@
+package pokeapi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Fallback fetches Pokemons from a primary fetcher, e.g. a Snapshot, and only asks the secondary
+// one, e.g. the live API, when the primary does not know the Pokemon
+type Fallback struct {
+	primary   PokemonFetcher
+	secondary PokemonFetcher
+}
+
+// NewFallback creates a new Fallback fetcher
+func NewFallback(primary PokemonFetcher, secondary PokemonFetcher) (*Fallback, error) {
+	if primary == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "primary")
+	}
+
+	if secondary == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "secondary")
+	}
+
+	return &Fallback{
+		primary:   primary,
+		secondary: secondary,
+	}, nil
+}
+
+// FetchByName returns Pokemon details by a given name
+func (f *Fallback) FetchByName(ctx context.Context, name string) (*Pokemon, error) {
+	pok, err := f.primary.FetchByName(ctx, name)
+	if err != nil || pok != nil {
+		return pok, err
+	}
+
+	return f.secondary.FetchByName(ctx, name)
+}
@@ -0,0 +1,81 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fetcherFunc func(ctx context.Context, name string) (*Pokemon, error)
+
+func (fn fetcherFunc) FetchByName(ctx context.Context, name string) (*Pokemon, error) {
+	return fn(ctx, name)
+}
+
+func TestFallback_FetchByName(t *testing.T) {
+	t.Parallel()
+
+	primaryPokemon := &Pokemon{Name: "mewtwo", Description: "snapshot"}
+	secondaryPokemon := &Pokemon{Name: "mewtwo", Description: "live"}
+	errPrimary := errors.New("primary failed")
+
+	tests := map[string]struct {
+		primary  fetcherFunc
+		expected *Pokemon
+		err      error
+		called   bool
+	}{
+		"ReturnsPrimaryPokemonWhenFound": {
+			primary: func(ctx context.Context, name string) (*Pokemon, error) {
+				return primaryPokemon, nil
+			},
+			expected: primaryPokemon,
+		},
+		"ReturnsSecondaryPokemonWhenPrimaryMisses": {
+			primary: func(ctx context.Context, name string) (*Pokemon, error) {
+				return nil, nil
+			},
+			expected: secondaryPokemon,
+			called:   true,
+		},
+		"ReturnsErrorWithoutSecondaryWhenPrimaryFails": {
+			primary: func(ctx context.Context, name string) (*Pokemon, error) {
+				return nil, errPrimary
+			},
+			err: errPrimary,
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			called := false
+			secondary := fetcherFunc(func(ctx context.Context, name string) (*Pokemon, error) {
+				called = true
+
+				return secondaryPokemon, nil
+			})
+
+			f, err := NewFallback(tc.primary, secondary)
+			require.NoError(t, err)
+
+			pok, err := f.FetchByName(context.Background(), "mewtwo")
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.expected, pok)
+			require.Equal(t, tc.called, called)
+		})
+	}
+}
+
+func TestNewFallback_Error(t *testing.T) {
+	t.Parallel()
+
+	f, err := NewFallback(nil, nil)
+	require.EqualError(t, err, "primary: invalid parameter")
+	require.Nil(t, f)
+}
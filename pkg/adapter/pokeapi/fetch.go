@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"pokedex/pkg/adapter/upstream"
 
 	"github.com/pkg/errors"
 )
@@ -16,34 +18,34 @@ type PokemonFetcher interface {
 
 // FetchByName returns Pokemon details by a given name.
 func (c *Client) FetchByName(ctx context.Context, name string) (*Pokemon, error) {
-	httpReq, err := http.NewRequest(http.MethodGet, fmt.Sprintf(path, c.url, "pokemon-species", name), nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating http request")
-	}
-
-	var p *Pokemon
 	raw := &PokemonResponse{}
 
-	// requires nolint rule as body on this stage is already closed and linter doesn't pick that up
-	res, err := c.execute(ctx, httpReq, raw) // nolint:bodyclose
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to execute fetch by name request")
+	err := c.http.DoJSON(ctx, http.MethodGet, fmt.Sprintf(path, "pokemon-species", url.PathEscape(name)), nil, raw)
+	if upstream.StatusCode(err) == http.StatusNotFound {
+		return nil, nil
 	}
 
-	if res.StatusCode == http.StatusNotFound {
-		return p, nil
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute fetch by name request")
 	}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, errors.Errorf("invalid response code: %s", res.Status)
-	}
+	return toPokemon(raw), nil
+}
 
-	p = &Pokemon{
+func toPokemon(raw *PokemonResponse) *Pokemon {
+	pok := &Pokemon{
 		Name:        raw.Name,
+		Number:      raw.ID,
 		Habitat:     raw.Habitat.Name,
+		Generation:  raw.Generation.Name,
 		IsLegendary: raw.IsLegendary,
+		IsMythical:  raw.IsMythical,
 		Description: raw.GetEnglishDescription(),
 	}
 
-	return p, nil
+	if raw.EvolvesFrom != nil {
+		pok.EvolvesFrom = raw.EvolvesFrom.Name
+	}
+
+	return pok
 }
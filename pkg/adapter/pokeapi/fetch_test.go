@@ -21,6 +21,7 @@ func TestFetchByName_Error(t *testing.T) {
 		"ReturnsErrorWhenFailedToExecute": {
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 
 				_, err := w.Write([]byte("not-a-json"))
@@ -50,10 +51,8 @@ func TestFetchByName_Error(t *testing.T) {
 			defer tc.server.Close()
 
 			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
+			client, err := New(tc.server.URL)
+			require.NoError(t, err)
 
 			res, err := client.FetchByName(ctx, "mewtwo")
 
@@ -87,6 +86,7 @@ func TestFetchByName_Success(t *testing.T) {
 		"ReturnsPokemonIfPokemonFound": {
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusOK)
 
 				_, err := w.Write([]byte(`
@@ -128,10 +128,8 @@ func TestFetchByName_Success(t *testing.T) {
 			defer tc.server.Close()
 
 			ctx := context.Background()
-			client := Client{
-				httpClient: tc.server.Client(),
-				url:        tc.server.URL,
-			}
+			client, err := New(tc.server.URL)
+			require.NoError(t, err)
 
 			res, err := client.FetchByName(ctx, "mewtwo")
 
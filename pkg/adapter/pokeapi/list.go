@@ -0,0 +1,77 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"pokedex/pkg/adapter/upstream"
+	"pokedex/pkg/http/client"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	listPath = "/api/v2/%s/?offset=%d&limit=%d"
+
+	listPageSize = 1000
+)
+
+// ErrNotModified is returned by FetchResource when the resource still matches the given ETag
+var ErrNotModified = errors.New("not modified")
+
+// Resource is a raw PokeAPI resource
+type Resource struct {
+	Data json.RawMessage
+	ETag string
+}
+
+// List returns every named resource of the given kind, e.g. pokemon-species, following pagination
+func (c *Client) List(ctx context.Context, resource string) (*ResourceList, error) {
+	list := &ResourceList{}
+
+	for {
+		page := &ResourceList{}
+
+		err := c.http.DoJSON(ctx, http.MethodGet, fmt.Sprintf(listPath, url.PathEscape(resource), len(list.Results), listPageSize), nil, page)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s", resource)
+		}
+
+		list.Count = page.Count
+		list.Results = append(list.Results, page.Results...)
+
+		if len(page.Results) == 0 || len(list.Results) >= page.Count {
+			return list, nil
+		}
+	}
+}
+
+// FetchResource returns a resource by kind and id or name without decoding it. When etag is set the
+// request is conditional and ErrNotModified is returned if the resource did not change
+func (c *Client) FetchResource(ctx context.Context, resource string, id string, etag string) (*Resource, error) {
+	var (
+		data   json.RawMessage
+		header http.Header
+	)
+
+	opts := []client.CallOption{client.WithResponseHeader(&header)}
+	if etag != "" {
+		opts = append(opts, client.WithRequestHeader("If-None-Match", etag))
+	}
+
+	err := c.http.DoJSON(ctx, http.MethodGet, fmt.Sprintf(path, url.PathEscape(resource), url.PathEscape(id))+"/", nil, &data, opts...)
+	if upstream.StatusCode(err) == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch %s %s", resource, id)
+	}
+
+	return &Resource{
+		Data: data,
+		ETag: header.Get("ETag"),
+	}, nil
+}
@@ -0,0 +1,82 @@
+package pokeapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestList_Success(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v2/pokemon-species/", r.URL.Path)
+		require.Equal(t, "1000", r.URL.Query().Get("limit"))
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		results := ""
+		for i := offset; i < offset+1000 && i < 1500; i++ {
+			if results != "" {
+				results += ","
+			}
+
+			results += fmt.Sprintf(`{"name":"p%d","url":"/api/v2/pokemon-species/%d/"}`, i, i)
+		}
+
+		_, err = fmt.Fprintf(w, `{"count":1500,"results":[%s]}`, results)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	list, err := client.List(context.Background(), "pokemon-species")
+	require.NoError(t, err)
+	require.Equal(t, 1500, list.Count)
+	require.Len(t, list.Results, 1500)
+	require.Equal(t, "p1499", list.Results[1499].Name)
+}
+
+func TestFetchResource_Success(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v2/evolution-chain/1/", r.URL.Path)
+
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, err := w.Write([]byte(`{"id":1}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL)
+	require.NoError(t, err)
+
+	res, err := client.FetchResource(context.Background(), "evolution-chain", "1", "")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":1}`, string(res.Data))
+	require.Equal(t, `"v1"`, res.ETag)
+
+	res, err = client.FetchResource(context.Background(), "evolution-chain", "1", `"v1"`)
+	require.ErrorIs(t, err, ErrNotModified)
+	require.Nil(t, res)
+}
@@ -1,14 +1,60 @@
 package pokeapi
 
-import "strings"
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 type (
 	// PokemonResponse raw response from PokeAPI
 	PokemonResponse struct {
+		ID                int               `json:"id"`
 		Name              string            `json:"name"`
 		FlavorTextEntries FlavorTextEntries `json:"flavor_text_entries"`
 		Habitat           Habitat           `json:"habitat"`
+		Generation        NamedResource     `json:"generation"`
 		IsLegendary       bool              `json:"is_legendary"`
+		IsMythical        bool              `json:"is_mythical"`
+		EvolvesFrom       *NamedResource    `json:"evolves_from_species"`
+		Varieties         []Variety         `json:"varieties"`
+	}
+
+	// Variety is a Pokemon of a species, e.g. a regional form
+	Variety struct {
+		IsDefault bool          `json:"is_default"`
+		Pokemon   NamedResource `json:"pokemon"`
+	}
+
+	// PokemonDetailsResponse raw pokemon resource from PokeAPI, only the types and stats are used
+	PokemonDetailsResponse struct {
+		Types []PokemonType `json:"types"`
+		Stats []PokemonStat `json:"stats"`
+	}
+
+	// PokemonStat is a base stat of a Pokemon e.g. hp
+	PokemonStat struct {
+		BaseStat int           `json:"base_stat"`
+		Stat     NamedResource `json:"stat"`
+	}
+
+	// PokemonType is a type of a Pokemon in its slot order
+	PokemonType struct {
+		Slot int           `json:"slot"`
+		Type NamedResource `json:"type"`
+	}
+
+	// ResourceList is a PokeAPI list of named resources, as stored in a resource index.json
+	ResourceList struct {
+		Count   int             `json:"count"`
+		Results []NamedResource `json:"results"`
+	}
+
+	// NamedResource is a reference to a PokeAPI resource
+	NamedResource struct {
+		Name string `json:"name"`
+		Url  string `json:"url"`
 	}
 
 	// FlavorTextEntries an arry of FlavorTextEntry
@@ -30,15 +76,73 @@ type (
 		Name string `json:"name"`
 	}
 
-	// Pokemon contains basic information about Pokemon
+	// Pokemon contains basic information about Pokemon. Types and stats are only known when the
+	// Pokemon comes from a snapshot containing pokemon resources
 	Pokemon struct {
 		Name        string
+		Number      int `json:",omitempty"`
 		Description string
 		Habitat     string
+		Generation  string         `json:",omitempty"`
+		Types       []string       `json:",omitempty"`
+		Stats       map[string]int `json:",omitempty"`
 		IsLegendary bool
+		IsMythical  bool
+		EvolvesFrom string `json:",omitempty"`
 	}
 )
 
+// Validate checks the fields the service relies on are present, used by the strict mode of the client
+func (r *PokemonResponse) Validate() error {
+	if r.Name == "" {
+		return errors.New("missing name")
+	}
+
+	return nil
+}
+
+// DefaultVariety returns the default Pokemon of the species, false when there is none
+func (r *PokemonResponse) DefaultVariety() (NamedResource, bool) {
+	for _, v := range r.Varieties {
+		if v.IsDefault {
+			return v.Pokemon, true
+		}
+	}
+
+	return NamedResource{}, false
+}
+
+// TypeNames returns the names of the types in slot order
+func (r *PokemonDetailsResponse) TypeNames() []string {
+	types := make([]PokemonType, len(r.Types))
+	copy(types, r.Types)
+
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Slot < types[j].Slot
+	})
+
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, t.Type.Name)
+	}
+
+	return names
+}
+
+// BaseStats returns the base stats by stat name
+func (r *PokemonDetailsResponse) BaseStats() map[string]int {
+	if len(r.Stats) == 0 {
+		return nil
+	}
+
+	stats := make(map[string]int, len(r.Stats))
+	for _, s := range r.Stats {
+		stats[s.Stat.Name] = s.BaseStat
+	}
+
+	return stats
+}
+
 // GetEnglishDescription returns the first english description available
 func (r *PokemonResponse) GetEnglishDescription() string {
 	var desc string
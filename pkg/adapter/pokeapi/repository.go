@@ -0,0 +1,103 @@
+package pokeapi
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	// PokemonRepository stores Pokemons and finds them by their indexed fields without calling PokeAPI
+	PokemonRepository interface {
+		PokemonFetcher
+
+		// Save inserts or replaces the given Pokemons
+		Save(ctx context.Context, pokemons ...*Pokemon) error
+		// Query returns the Pokemons matching every set field of the query ordered by number
+		Query(ctx context.Context, q Query) ([]*Pokemon, error)
+	}
+
+	// Query filters Pokemons by their indexed fields and base stat ranges, zero values are ignored
+	Query struct {
+		Name       string
+		Number     int
+		Type       string
+		Habitat    string
+		Generation string
+		Legendary  *bool
+		Mythical   *bool
+		Stats      []StatRange
+	}
+
+	// StatRange matches Pokemons with a base stat between Min and Max inclusive
+	StatRange struct {
+		Stat string
+		Min  int
+		Max  int
+	}
+)
+
+// Matches reports whether the Pokemon has the stat within the range
+func (r StatRange) Matches(pok *Pokemon) bool {
+	value, ok := pok.Stats[r.Stat]
+
+	return ok && value >= r.Min && value <= r.Max
+}
+
+// readThrough serves Pokemons from a repository and saves the ones fetched on a miss
+type readThrough struct {
+	repo PokemonRepository
+	next PokemonFetcher
+}
+
+// ReadThrough returns a fetcher reading from repo first, Pokemons fetched from next on a miss are
+// saved so the repository fills up as it is used
+func ReadThrough(repo PokemonRepository, next PokemonFetcher) PokemonFetcher {
+	return &readThrough{
+		repo: repo,
+		next: next,
+	}
+}
+
+// FetchByName returns Pokemon details by a given name
+func (r *readThrough) FetchByName(ctx context.Context, name string) (*Pokemon, error) {
+	pok, err := r.repo.FetchByName(ctx, name)
+	if err != nil || pok != nil {
+		return pok, err
+	}
+
+	pok, err = r.next.FetchByName(ctx, name)
+	if err != nil || pok == nil {
+		return pok, err
+	}
+
+	if err := r.repo.Save(ctx, pok); err != nil {
+		log.Warn().Err(err).Str("name", name).Msg("failed to save pokemon")
+	}
+
+	return pok, nil
+}
+
+// Populate saves every Pokemon of the snapshot to repo
+func Populate(ctx context.Context, repo PokemonRepository, snapshot *Snapshot) (int, error) {
+	names := snapshot.Names()
+	pokemons := make([]*Pokemon, 0, len(names))
+
+	for _, name := range names {
+		pok, err := snapshot.FetchByName(ctx, name)
+		if err != nil {
+			return 0, err
+		}
+
+		if pok != nil {
+			pokemons = append(pokemons, pok)
+		}
+	}
+
+	if err := repo.Save(ctx, pokemons...); err != nil {
+		return 0, errors.Wrap(err, "failed to save snapshot pokemons")
+	}
+
+	return len(pokemons), nil
+}
@@ -0,0 +1,72 @@
+package pokeapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memRepository map[string]*Pokemon
+
+func (m memRepository) FetchByName(ctx context.Context, name string) (*Pokemon, error) {
+	return m[name], nil
+}
+
+func (m memRepository) Save(ctx context.Context, pokemons ...*Pokemon) error {
+	for _, p := range pokemons {
+		m[p.Name] = p
+	}
+
+	return nil
+}
+
+func (m memRepository) Query(ctx context.Context, q Query) ([]*Pokemon, error) {
+	return nil, nil
+}
+
+func TestReadThrough_FetchByName(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	live := fetcherFunc(func(ctx context.Context, name string) (*Pokemon, error) {
+		calls++
+
+		if name != "mewtwo" {
+			return nil, nil
+		}
+
+		return &Pokemon{Name: name}, nil
+	})
+
+	repo := memRepository{}
+	f := ReadThrough(repo, live)
+
+	for i := 0; i < 2; i++ {
+		pok, err := f.FetchByName(context.Background(), "mewtwo")
+		require.NoError(t, err)
+		require.Equal(t, "mewtwo", pok.Name)
+	}
+
+	require.Equal(t, 1, calls)
+	require.Contains(t, repo, "mewtwo")
+
+	pok, err := f.FetchByName(context.Background(), "missingno")
+	require.NoError(t, err)
+	require.Nil(t, pok)
+	require.NotContains(t, repo, "missingno")
+}
+
+func TestPopulate(t *testing.T) {
+	t.Parallel()
+
+	snapshot, err := NewSnapshot(writeSnapshotDir(t, t.TempDir()))
+	require.NoError(t, err)
+
+	repo := memRepository{}
+
+	n, err := Populate(context.Background(), repo, snapshot)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"psychic", "ghost"}, repo["mewtwo"].Types)
+}
@@ -0,0 +1,333 @@
+package pokeapi
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/url"
+	"os"
+	slashpath "path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	speciesResource = "pokemon-species"
+
+	indexFile = "index.json"
+)
+
+type (
+	// Snapshot fetches Pokemons from a local PokeAPI data snapshot laid out like the api-data
+	// repository, e.g. data/api/v2/pokemon-species/150/index.json. The snapshot is either a
+	// directory, a .zip or a .tar.gz archive
+	Snapshot struct {
+		files   fileReader
+		prefix  string
+		names   map[string]string
+		modTime time.Time
+	}
+
+	fileReader interface {
+		ReadFile(name string) ([]byte, error)
+		// Find returns the path of a file ending with suffix
+		Find(suffix string) (string, bool)
+	}
+
+	fsReader struct {
+		fsys fs.FS
+	}
+
+	memReader map[string][]byte
+)
+
+// NewSnapshot opens the snapshot at the given path and indexes its Pokemon species by name
+func NewSnapshot(snapshotPath string) (*Snapshot, error) {
+	if snapshotPath == "" {
+		return nil, errors.Wrap(ErrInvalidParam, "path")
+	}
+
+	files, err := openSnapshot(snapshotPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open snapshot %s", snapshotPath)
+	}
+
+	modTime, err := lastModified(snapshotPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to stat snapshot %s", snapshotPath)
+	}
+
+	s := &Snapshot{files: files, modTime: modTime}
+
+	s.prefix, err = findPrefix(files)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := files.ReadFile(s.resourcePath(speciesResource))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read species index")
+	}
+
+	list := &ResourceList{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal species index")
+	}
+
+	s.names = make(map[string]string, len(list.Results))
+	for _, r := range list.Results {
+		s.names[r.Name] = s.resourceFile(r.Url)
+	}
+
+	return s, nil
+}
+
+// FetchByName returns Pokemon details by a given name, nil is returned when the snapshot does not
+// contain the Pokemon. Types and stats are set when the snapshot contains the default pokemon of the
+// species
+func (s *Snapshot) FetchByName(ctx context.Context, name string) (*Pokemon, error) {
+	file, ok := s.names[name]
+	if !ok {
+		return nil, nil
+	}
+
+	raw := &PokemonResponse{}
+	if err := s.read(file, raw); err != nil {
+		return nil, err
+	}
+
+	pok := toPokemon(raw)
+
+	variety, ok := raw.DefaultVariety()
+	if !ok {
+		return pok, nil
+	}
+
+	details := &PokemonDetailsResponse{}
+
+	err := s.read(s.resourceFile(variety.Url), details)
+	if errors.Is(err, fs.ErrNotExist) {
+		return pok, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	pok.Types = details.TypeNames()
+	pok.Stats = details.BaseStats()
+
+	return pok, nil
+}
+
+// ModTime returns the modification time of the snapshot archive, or of the newest file of a snapshot
+// directory, when it was opened
+func (s *Snapshot) ModTime() time.Time {
+	return s.modTime
+}
+
+// Len returns the number of Pokemon species in the snapshot
+func (s *Snapshot) Len() int {
+	return len(s.names)
+}
+
+// Names returns the names of the Pokemon species in the snapshot in alphabetical order
+func (s *Snapshot) Names() []string {
+	names := make([]string, 0, len(s.names))
+	for name := range s.names {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (s *Snapshot) read(file string, out interface{}) error {
+	data, err := s.files.ReadFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read snapshot file %s", file)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal snapshot file %s", file)
+	}
+
+	return nil
+}
+
+// resourceFile returns the file of a resource url, e.g. /api/v2/pokemon/150/ or an absolute url
+func (s *Snapshot) resourceFile(resourceURL string) string {
+	if u, err := url.Parse(resourceURL); err == nil {
+		resourceURL = u.Path
+	}
+
+	return slashpath.Join(s.prefix, strings.Trim(resourceURL, "/"), indexFile)
+}
+
+func (s *Snapshot) resourcePath(resource string) string {
+	return slashpath.Join(s.prefix, "api/v2", resource, indexFile)
+}
+
+// findPrefix locates the api/v2 tree, archives usually nest it under one or more directories
+func findPrefix(files fileReader) (string, error) {
+	suffix := slashpath.Join("api/v2", speciesResource, indexFile)
+
+	name, ok := files.Find(suffix)
+	if !ok {
+		return "", errors.Errorf("snapshot does not contain %s", suffix)
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(name, suffix), "/"), nil
+}
+
+// lastModified returns the modification time of an archive, or the newest one of a directory and
+// everything in it as rewriting a file does not change the modification time of its directory
+func lastModified(snapshotPath string) (time.Time, error) {
+	var newest time.Time
+
+	err := filepath.WalkDir(snapshotPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+
+		return nil
+	})
+
+	return newest, err
+}
+
+func openSnapshot(snapshotPath string) (fileReader, error) {
+	info, err := os.Stat(snapshotPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case info.IsDir():
+		return fsReader{fsys: os.DirFS(snapshotPath)}, nil
+	case strings.HasSuffix(snapshotPath, ".zip"):
+		r, err := zip.OpenReader(snapshotPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return fsReader{fsys: r}, nil
+	case strings.HasSuffix(snapshotPath, ".tar.gz"), strings.HasSuffix(snapshotPath, ".tgz"):
+		return readTarGz(snapshotPath)
+	}
+
+	return nil, errors.New("unsupported snapshot format, expected a directory, .zip or .tar.gz")
+}
+
+// readTarGz loads the json files of a tar.gz archive in memory as tar does not allow random access
+func readTarGz(archivePath string) (fileReader, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := memReader{}
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag != tar.TypeReg || !strings.HasSuffix(hdr.Name, ".json") {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+
+		files[slashpath.Clean(strings.TrimPrefix(hdr.Name, "./"))] = data
+	}
+}
+
+func (r fsReader) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(r.fsys, name)
+}
+
+// Find looks for suffix at the root, under data/ and under the same paths in every top level
+// directory, as in the api-data repository and its GitHub archives
+func (r fsReader) Find(suffix string) (string, bool) {
+	candidates := []string{"", "data"}
+
+	if entries, err := fs.ReadDir(r.fsys, "."); err == nil {
+		for _, e := range entries {
+			if e.IsDir() {
+				candidates = append(candidates, e.Name(), slashpath.Join(e.Name(), "data"))
+			}
+		}
+	}
+
+	for _, c := range candidates {
+		name := slashpath.Join(c, suffix)
+
+		if _, err := fs.Stat(r.fsys, name); err == nil {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+func (r memReader) ReadFile(name string) ([]byte, error) {
+	data, ok := r[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return data, nil
+}
+
+// Find returns the shortest path ending with suffix
+func (r memReader) Find(suffix string) (string, bool) {
+	found := ""
+
+	for name := range r {
+		if name != suffix && !strings.HasSuffix(name, "/"+suffix) {
+			continue
+		}
+
+		if found == "" || len(name) < len(found) {
+			found = name
+		}
+	}
+
+	return found, found != ""
+}
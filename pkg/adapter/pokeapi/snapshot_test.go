@@ -0,0 +1,188 @@
+package pokeapi
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var snapshotFiles = map[string]string{
+	"data/api/v2/pokemon-species/index.json": `{
+		"count": 1,
+		"results": [{"name": "mewtwo", "url": "/api/v2/pokemon-species/150/"}]
+	}`,
+	"data/api/v2/pokemon-species/150/index.json": `{
+		"id": 150,
+		"name": "mewtwo",
+		"is_legendary": true,
+		"habitat": {"name": "rare"},
+		"generation": {"name": "generation-i"},
+		"varieties": [{"is_default": true, "pokemon": {"name": "mewtwo", "url": "/api/v2/pokemon/150/"}}],
+		"flavor_text_entries": [{"flavor_text": "It was created\nby a scientist.", "language": {"name": "en"}}]
+	}`,
+	"data/api/v2/pokemon/150/index.json": `{
+		"types": [{"slot": 2, "type": {"name": "ghost"}}, {"slot": 1, "type": {"name": "psychic"}}],
+		"stats": [{"base_stat": 106, "stat": {"name": "hp"}}, {"base_stat": 130, "stat": {"name": "speed"}}]
+	}`,
+}
+
+func TestSnapshot_FetchByName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(t *testing.T, dir string) string{
+		"ReadsDirectory":     writeSnapshotDir,
+		"ReadsNestedZip":     writeSnapshotZip,
+		"ReadsNestedTarGzip": writeSnapshotTarGz,
+	}
+
+	for description, testCase := range tests {
+		write := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			snapshot, err := NewSnapshot(write(t, t.TempDir()))
+			require.NoError(t, err)
+			require.Equal(t, 1, snapshot.Len())
+
+			pok, err := snapshot.FetchByName(context.Background(), "mewtwo")
+			require.NoError(t, err)
+			require.Equal(t, &Pokemon{
+				Name:        "mewtwo",
+				Number:      150,
+				Description: "It was created by a scientist.",
+				Habitat:     "rare",
+				Generation:  "generation-i",
+				Types:       []string{"psychic", "ghost"},
+				Stats:       map[string]int{"hp": 106, "speed": 130},
+				IsLegendary: true,
+			}, pok)
+			require.Equal(t, []string{"mewtwo"}, snapshot.Names())
+
+			pok, err = snapshot.FetchByName(context.Background(), "missingno")
+			require.NoError(t, err)
+			require.Nil(t, pok)
+		})
+	}
+}
+
+func TestSnapshot_ModTime(t *testing.T) {
+	t.Parallel()
+
+	dir := writeSnapshotDir(t, t.TempDir())
+	rewritten := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	// rewriting a file in place does not change the modification time of its directories
+	file := filepath.Join(dir, "data", "api", "v2", "pokemon", "150", "index.json")
+	require.NoError(t, os.Chtimes(file, rewritten, rewritten))
+
+	snapshot, err := NewSnapshot(dir)
+	require.NoError(t, err)
+	require.True(t, snapshot.ModTime().Equal(rewritten), snapshot.ModTime())
+}
+
+func TestNewSnapshot_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path func(t *testing.T) string
+		err  string
+	}{
+		"ReturnsErrorWhenPathEmpty": {
+			path: func(t *testing.T) string { return "" },
+			err:  "path: invalid parameter",
+		},
+		"ReturnsErrorWhenSpeciesIndexMissing": {
+			path: func(t *testing.T) string { return t.TempDir() },
+			err:  "snapshot does not contain api/v2/pokemon-species/index.json",
+		},
+		"ReturnsErrorWhenFormatUnsupported": {
+			path: func(t *testing.T) string {
+				file := filepath.Join(t.TempDir(), "snapshot.rar")
+				require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+
+				return file
+			},
+			err: "unsupported snapshot format",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			snapshot, err := NewSnapshot(tc.path(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+			require.Nil(t, snapshot)
+		})
+	}
+}
+
+func writeSnapshotDir(t *testing.T, dir string) string {
+	for name, content := range snapshotFiles {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0700))
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+	}
+
+	return dir
+}
+
+func writeSnapshotZip(t *testing.T, dir string) string {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+
+	for name, content := range snapshotFiles {
+		w, err := zw.Create("api-data-master/" + name)
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+
+	file := filepath.Join(dir, "snapshot.zip")
+	require.NoError(t, ioutil.WriteFile(file, buf.Bytes(), 0600))
+
+	return file
+}
+
+func writeSnapshotTarGz(t *testing.T, dir string) string {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range snapshotFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     "./api-data-master/" + name,
+			Mode:     0600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	file := filepath.Join(dir, "snapshot.tar.gz")
+	require.NoError(t, ioutil.WriteFile(file, buf.Bytes(), 0600))
+
+	return file
+}
@@ -0,0 +1,373 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"pokedex/pkg/adapter/pokeapi"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	openTimeout = time.Second
+
+	// schemaVersion is incremented when indexes change, indexes of an older store are rebuilt on open
+	schemaVersion = 2
+
+	indexNumber     = "number"
+	indexType       = "type"
+	indexHabitat    = "habitat"
+	indexGeneration = "generation"
+	indexLegendary  = "legendary"
+	indexMythical   = "mythical"
+)
+
+var (
+	ErrInvalidParam = errors.New("invalid parameter")
+
+	pokemonBucket = []byte("pokemon")
+	indexBucket   = []byte("index")
+	metaBucket    = []byte("meta")
+
+	versionKey = []byte("version")
+
+	indexes = []string{indexNumber, indexType, indexHabitat, indexGeneration, indexLegendary, indexMythical}
+)
+
+type (
+	// Store is an embedded bbolt database of Pokemons implementing pokeapi.PokemonRepository. Pokemons
+	// are stored by name with an index per queryable field, an index entry key is the field value
+	// and the Pokemon name separated by a zero byte
+	Store struct {
+		db *bolt.DB
+	}
+
+	filter struct {
+		index string
+		value string
+	}
+)
+
+// Open opens or creates the database at the given path
+func Open(path string) (*Store, error) {
+	if path == "" {
+		return nil, errors.Wrap(ErrInvalidParam, "path")
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open store %s", path)
+	}
+
+	err = db.Update(migrate)
+	if err != nil {
+		db.Close()
+
+		return nil, errors.Wrap(err, "failed to create buckets")
+	}
+
+	return &Store{db: db}, nil
+}
+
+// migrate creates the buckets and rebuilds the indexes when the schema version changed
+func migrate(tx *bolt.Tx) error {
+	pokemons, err := tx.CreateBucketIfNotExists(pokemonBucket)
+	if err != nil {
+		return err
+	}
+
+	meta, err := tx.CreateBucketIfNotExists(metaBucket)
+	if err != nil {
+		return err
+	}
+
+	version := strconv.Itoa(schemaVersion)
+	if string(meta.Get(versionKey)) == version && tx.Bucket(indexBucket) != nil {
+		return nil
+	}
+
+	if tx.Bucket(indexBucket) != nil {
+		if err := tx.DeleteBucket(indexBucket); err != nil {
+			return err
+		}
+	}
+
+	idx, err := tx.CreateBucket(indexBucket)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range indexes {
+		if _, err := idx.CreateBucket([]byte(name)); err != nil {
+			return err
+		}
+	}
+
+	err = pokemons.ForEach(func(k, v []byte) error {
+		pok, err := decode(v)
+		if err != nil {
+			return err
+		}
+
+		return index(idx, pok)
+	})
+	if err != nil {
+		return err
+	}
+
+	return meta.Put(versionKey, []byte(version))
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// FetchByName returns Pokemon details by a given name, nil is returned when the Pokemon is not stored
+func (s *Store) FetchByName(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+	var pok *pokeapi.Pokemon
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+
+		pok, err = get(tx, name)
+
+		return err
+	})
+
+	return pok, err
+}
+
+// Save inserts or replaces the given Pokemons in a single transaction. Types already stored are
+// kept when a Pokemon is saved without them, as the live API does not return them
+func (s *Store) Save(ctx context.Context, pokemons ...*pokeapi.Pokemon) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, pok := range pokemons {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if pok == nil || pok.Name == "" {
+				return errors.Wrap(ErrInvalidParam, "name")
+			}
+
+			if err := put(tx, pok); err != nil {
+				return errors.Wrapf(err, "failed to save %s", pok.Name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Query returns the Pokemons matching every set field of the query ordered by number then name.
+// Indexed fields are looked up in their index, stat ranges are checked on the matching Pokemons
+func (s *Store) Query(ctx context.Context, q pokeapi.Query) ([]*pokeapi.Pokemon, error) {
+	var pokemons []*pokeapi.Pokemon
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		names, all := matching(tx, q)
+
+		if all {
+			return tx.Bucket(pokemonBucket).ForEach(func(k, v []byte) error {
+				pok, err := decode(v)
+				if err != nil {
+					return err
+				}
+
+				if inRanges(pok, q.Stats) {
+					pokemons = append(pokemons, pok)
+				}
+
+				return nil
+			})
+		}
+
+		for name := range names {
+			pok, err := get(tx, name)
+			if err != nil {
+				return err
+			}
+
+			if pok != nil && inRanges(pok, q.Stats) {
+				pokemons = append(pokemons, pok)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(pokemons, func(i, j int) bool {
+		if pokemons[i].Number != pokemons[j].Number {
+			return pokemons[i].Number < pokemons[j].Number
+		}
+
+		return pokemons[i].Name < pokemons[j].Name
+	})
+
+	return pokemons, nil
+}
+
+// inRanges reports whether the Pokemon matches every stat range, stats are not indexed
+func inRanges(pok *pokeapi.Pokemon, ranges []pokeapi.StatRange) bool {
+	for _, r := range ranges {
+		if !r.Matches(pok) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matching returns the names matching the query, all is true when the query has no filter
+func matching(tx *bolt.Tx, q pokeapi.Query) (names map[string]bool, all bool) {
+	if q.Name != "" {
+		names = map[string]bool{q.Name: true}
+	}
+
+	for _, f := range filters(q) {
+		found := map[string]bool{}
+
+		c := tx.Bucket(indexBucket).Bucket([]byte(f.index)).Cursor()
+		prefix := append([]byte(f.value), 0)
+
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			name := string(k[len(prefix):])
+
+			if names == nil || names[name] {
+				found[name] = true
+			}
+		}
+
+		names = found
+	}
+
+	return names, names == nil
+}
+
+func filters(q pokeapi.Query) []filter {
+	var fs []filter
+
+	if q.Number != 0 {
+		fs = append(fs, filter{indexNumber, numberKey(q.Number)})
+	}
+
+	if q.Type != "" {
+		fs = append(fs, filter{indexType, q.Type})
+	}
+
+	if q.Habitat != "" {
+		fs = append(fs, filter{indexHabitat, q.Habitat})
+	}
+
+	if q.Generation != "" {
+		fs = append(fs, filter{indexGeneration, q.Generation})
+	}
+
+	if q.Legendary != nil {
+		fs = append(fs, filter{indexLegendary, strconv.FormatBool(*q.Legendary)})
+	}
+
+	if q.Mythical != nil {
+		fs = append(fs, filter{indexMythical, strconv.FormatBool(*q.Mythical)})
+	}
+
+	return fs
+}
+
+// entries returns the index entries of a Pokemon
+func entries(pok *pokeapi.Pokemon) []filter {
+	fs := []filter{
+		{indexNumber, numberKey(pok.Number)},
+		{indexHabitat, pok.Habitat},
+		{indexGeneration, pok.Generation},
+		{indexLegendary, strconv.FormatBool(pok.IsLegendary)},
+		{indexMythical, strconv.FormatBool(pok.IsMythical)},
+	}
+
+	for _, t := range pok.Types {
+		fs = append(fs, filter{indexType, t})
+	}
+
+	return fs
+}
+
+// numberKey pads numbers so index entries sort numerically
+func numberKey(number int) string {
+	return fmt.Sprintf("%08d", number)
+}
+
+func (f filter) key(name string) []byte {
+	return []byte(f.value + "\x00" + name)
+}
+
+func get(tx *bolt.Tx, name string) (*pokeapi.Pokemon, error) {
+	data := tx.Bucket(pokemonBucket).Get([]byte(name))
+	if data == nil {
+		return nil, nil
+	}
+
+	return decode(data)
+}
+
+func put(tx *bolt.Tx, pok *pokeapi.Pokemon) error {
+	record := *pok
+
+	existing, err := get(tx, pok.Name)
+	if err != nil {
+		return err
+	}
+
+	idx := tx.Bucket(indexBucket)
+
+	if existing != nil {
+		if len(record.Types) == 0 {
+			record.Types = existing.Types
+		}
+
+		for _, e := range entries(existing) {
+			if err := idx.Bucket([]byte(e.index)).Delete(e.key(existing.Name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(pokemonBucket).Put([]byte(record.Name), data); err != nil {
+		return err
+	}
+
+	return index(idx, &record)
+}
+
+func index(idx *bolt.Bucket, pok *pokeapi.Pokemon) error {
+	for _, e := range entries(pok) {
+		if err := idx.Bucket([]byte(e.index)).Put(e.key(pok.Name), []byte{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decode(data []byte) (*pokeapi.Pokemon, error) {
+	pok := &pokeapi.Pokemon{}
+	if err := json.Unmarshal(data, pok); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal pokemon")
+	}
+
+	return pok, nil
+}
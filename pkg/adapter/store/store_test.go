@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"pokedex/pkg/adapter/pokeapi"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	charizard = &pokeapi.Pokemon{Name: "charizard", Number: 6, Habitat: "mountain", Generation: "generation-i", Types: []string{"fire", "flying"}}
+	moltres   = &pokeapi.Pokemon{Name: "moltres", Number: 146, Habitat: "rare", Generation: "generation-i", Types: []string{"fire", "flying"}, IsLegendary: true}
+	mewtwo    = &pokeapi.Pokemon{Name: "mewtwo", Number: 150, Habitat: "rare", Generation: "generation-i", Types: []string{"psychic"}, Stats: map[string]int{"hp": 106}, IsLegendary: true}
+	mew       = &pokeapi.Pokemon{Name: "mew", Number: 151, Habitat: "rare", Generation: "generation-i", Types: []string{"psychic"}, Stats: map[string]int{"hp": 100}, IsMythical: true}
+	entei     = &pokeapi.Pokemon{Name: "entei", Number: 244, Habitat: "grassland", Generation: "generation-ii", Types: []string{"fire"}, Stats: map[string]int{"hp": 115}, IsLegendary: true}
+)
+
+func openStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "pokedex.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+
+	require.NoError(t, s.Save(context.Background(), entei, mewtwo, mew, charizard, moltres))
+
+	return s
+}
+
+func names(pokemons []*pokeapi.Pokemon) []string {
+	n := make([]string, 0, len(pokemons))
+	for _, p := range pokemons {
+		n = append(n, p.Name)
+	}
+
+	return n
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	legendary := true
+	notLegendary := false
+	mythical := true
+
+	tests := map[string]struct {
+		query    pokeapi.Query
+		expected []string
+	}{
+		"ReturnsAllOrderedByNumberWhenNoFilter": {
+			expected: []string{"charizard", "moltres", "mewtwo", "mew", "entei"},
+		},
+		"ReturnsByType": {
+			query:    pokeapi.Query{Type: "fire"},
+			expected: []string{"charizard", "moltres", "entei"},
+		},
+		"ReturnsByTypeAndLegendary": {
+			query:    pokeapi.Query{Type: "fire", Legendary: &legendary},
+			expected: []string{"moltres", "entei"},
+		},
+		"ReturnsNotLegendary": {
+			query:    pokeapi.Query{Legendary: &notLegendary},
+			expected: []string{"charizard", "mew"},
+		},
+		"ReturnsByHabitatAndGeneration": {
+			query:    pokeapi.Query{Habitat: "rare", Generation: "generation-i"},
+			expected: []string{"moltres", "mewtwo", "mew"},
+		},
+		"ReturnsMythical": {
+			query:    pokeapi.Query{Mythical: &mythical},
+			expected: []string{"mew"},
+		},
+		"ReturnsByStatRange": {
+			query:    pokeapi.Query{Stats: []pokeapi.StatRange{{Stat: "hp", Min: 100, Max: 110}}},
+			expected: []string{"mewtwo", "mew"},
+		},
+		"ReturnsByTypeAndStatRange": {
+			query:    pokeapi.Query{Type: "fire", Stats: []pokeapi.StatRange{{Stat: "hp", Min: 110, Max: 200}}},
+			expected: []string{"entei"},
+		},
+		"ReturnsByNumber": {
+			query:    pokeapi.Query{Number: 150},
+			expected: []string{"mewtwo"},
+		},
+		"ReturnsByName": {
+			query:    pokeapi.Query{Name: "entei", Type: "fire"},
+			expected: []string{"entei"},
+		},
+		"ReturnsEmptyWhenNothingMatches": {
+			query:    pokeapi.Query{Type: "fire", Habitat: "sea"},
+			expected: []string{},
+		},
+	}
+
+	s := openStore(t)
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			pokemons, err := s.Query(context.Background(), tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, names(pokemons))
+		})
+	}
+}
+
+func TestSave_ReplacesIndexEntries(t *testing.T) {
+	t.Parallel()
+
+	s := openStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, s.Save(ctx, &pokeapi.Pokemon{Name: "mewtwo", Number: 150, Habitat: "cave", Generation: "generation-i", IsLegendary: true}))
+
+	pok, err := s.FetchByName(ctx, "mewtwo")
+	require.NoError(t, err)
+	require.Equal(t, "cave", pok.Habitat)
+	require.Equal(t, []string{"psychic"}, pok.Types, "types are kept when saved without them")
+
+	pokemons, err := s.Query(ctx, pokeapi.Query{Habitat: "rare"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"moltres", "mew"}, names(pokemons))
+}
+
+func TestOpen_RebuildsIndexesOfOlderSchema(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "pokedex.db")
+
+	s, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, s.Save(context.Background(), mew))
+	require.NoError(t, s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(indexBucket).DeleteBucket([]byte(indexMythical)); err != nil {
+			return err
+		}
+
+		return tx.Bucket(metaBucket).Put(versionKey, []byte("1"))
+	}))
+	require.NoError(t, s.Close())
+
+	s, err = Open(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	mythical := true
+
+	pokemons, err := s.Query(context.Background(), pokeapi.Query{Mythical: &mythical})
+	require.NoError(t, err)
+	require.Equal(t, []string{"mew"}, names(pokemons))
+}
+
+func TestFetchByName_ReturnsNilWhenMissing(t *testing.T) {
+	t.Parallel()
+
+	pok, err := openStore(t).FetchByName(context.Background(), "missingno")
+	require.NoError(t, err)
+	require.Nil(t, pok)
+}
+
+func TestSave_Error(t *testing.T) {
+	t.Parallel()
+
+	err := openStore(t).Save(context.Background(), &pokeapi.Pokemon{})
+	require.EqualError(t, err, "name: invalid parameter")
+}
+
+func TestOpen_Error(t *testing.T) {
+	t.Parallel()
+
+	s, err := Open("")
+	require.EqualError(t, err, "path: invalid parameter")
+	require.Nil(t, s)
+}
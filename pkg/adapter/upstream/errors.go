@@ -0,0 +1,175 @@
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Sentinel errors describing why an upstream call failed
+const (
+	ErrUnavailable Error = iota + 1
+	ErrTimeout
+	ErrBadResponse
+	ErrRateLimited
+	ErrResponseTooLarge
+	ErrUnexpectedContentType
+	ErrUnexpectedSchema
+)
+
+// Error is a sentinel upstream error
+type Error uint
+
+func (e Error) Error() string {
+	switch e {
+	case ErrUnavailable:
+		return "upstream unavailable"
+	case ErrTimeout:
+		return "upstream timeout"
+	case ErrBadResponse:
+		return "upstream bad response"
+	case ErrRateLimited:
+		return "upstream rate limited"
+	case ErrResponseTooLarge:
+		return "upstream response too large"
+	case ErrUnexpectedContentType:
+		return "upstream response has unexpected content type"
+	case ErrUnexpectedSchema:
+		return "upstream response has unexpected schema"
+	}
+
+	return "unknown upstream error"
+}
+
+// ResponseError is returned by adapters when an upstream call fails, it matches its Kind with errors.Is
+type ResponseError struct {
+	Kind       Error
+	Service    string
+	StatusCode int
+	RetryAfter time.Duration
+	// Body holds the beginning of the response body, if any, to help diagnose the failure
+	Body string
+	Err  error
+}
+
+func (e *ResponseError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.Service, e.Kind)
+
+	if e.StatusCode != 0 {
+		msg = fmt.Sprintf("%s (status %d)", msg, e.StatusCode)
+	}
+
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %s", msg, e.Err)
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the given kind, oversized, wrong content type and unexpected
+// schema responses are also bad responses
+func (e *ResponseError) Is(target error) bool {
+	kind, ok := target.(Error)
+	if !ok {
+		return false
+	}
+
+	if kind == ErrBadResponse {
+		switch e.Kind {
+		case ErrResponseTooLarge, ErrUnexpectedContentType, ErrUnexpectedSchema:
+			return true
+		}
+	}
+
+	return kind == e.Kind
+}
+
+// FromTransport classifies an error returned while sending a request
+func FromTransport(service string, err error) error {
+	kind := ErrUnavailable
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		kind = ErrTimeout
+	}
+
+	return &ResponseError{Kind: kind, Service: service, Err: err}
+}
+
+// FromStatus classifies an unexpected response status code
+func FromStatus(service string, res *http.Response) error {
+	e := &ResponseError{
+		Kind:       ErrBadResponse,
+		Service:    service,
+		StatusCode: res.StatusCode,
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		e.Kind = ErrRateLimited
+		e.RetryAfter = RetryAfter(res.Header.Get("Retry-After"))
+	case http.StatusServiceUnavailable:
+		e.Kind = ErrUnavailable
+		e.RetryAfter = RetryAfter(res.Header.Get("Retry-After"))
+	case http.StatusGatewayTimeout:
+		e.Kind = ErrTimeout
+	}
+
+	return e
+}
+
+// FromDecode classifies a response body that could not be decoded
+func FromDecode(service string, res *http.Response, err error) error {
+	return FromBody(service, res, ErrBadResponse, err)
+}
+
+// FromBody classifies a response body rejected for the given reason, e.g. ErrResponseTooLarge
+func FromBody(service string, res *http.Response, kind Error, err error) error {
+	e := &ResponseError{Kind: kind, Service: service, Err: err}
+
+	if res != nil {
+		e.StatusCode = res.StatusCode
+	}
+
+	return e
+}
+
+// RetryAfter parses a Retry-After header given either in seconds or as a http date, 0 is returned
+// when it is missing, invalid or in the past
+func RetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// StatusCode returns the upstream response status code carried by err, or 0 when there is none
+func StatusCode(err error) int {
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.StatusCode
+	}
+
+	return 0
+}
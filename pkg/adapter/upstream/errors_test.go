@@ -0,0 +1,105 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromTransport(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		err  error
+		kind Error
+	}
+
+	tests := map[string]testcase{
+		"ReturnsTimeoutWhenDeadlineExceeded": {
+			err:  errors.Wrap(context.DeadlineExceeded, "Get"),
+			kind: ErrTimeout,
+		},
+		"ReturnsUnavailableWhenConnectionFailed": {
+			err:  errors.New("connection refused"),
+			kind: ErrUnavailable,
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			err := FromTransport("pokeapi", tc.err)
+
+			require.True(t, errors.Is(err, tc.kind))
+			require.True(t, errors.Is(err, tc.err))
+		})
+	}
+}
+
+func TestFromStatus(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		status     int
+		retryAfter string
+		kind       Error
+		checkErr   func(t *testing.T, err *ResponseError)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsRateLimitedWithRetryAfter": {
+			status:     http.StatusTooManyRequests,
+			retryAfter: "120",
+			kind:       ErrRateLimited,
+			checkErr: func(t *testing.T, err *ResponseError) {
+				require.Equal(t, 2*time.Minute, err.RetryAfter)
+			},
+		},
+		"ReturnsUnavailableWhenServiceUnavailable": {
+			status: http.StatusServiceUnavailable,
+			kind:   ErrUnavailable,
+		},
+		"ReturnsTimeoutWhenGatewayTimeout": {
+			status: http.StatusGatewayTimeout,
+			kind:   ErrTimeout,
+		},
+		"ReturnsBadResponseWhenUnexpectedStatus": {
+			status: http.StatusTeapot,
+			kind:   ErrBadResponse,
+			checkErr: func(t *testing.T, err *ResponseError) {
+				require.Equal(t, "funtranslations: upstream bad response (status 418)", err.Error())
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			res := &http.Response{StatusCode: tc.status, Header: http.Header{}}
+			if tc.retryAfter != "" {
+				res.Header.Set("Retry-After", tc.retryAfter)
+			}
+
+			err := FromStatus("funtranslations", res)
+			require.True(t, errors.Is(err, tc.kind))
+
+			var respErr *ResponseError
+			require.True(t, errors.As(err, &respErr))
+			require.Equal(t, tc.status, respErr.StatusCode)
+
+			if tc.checkErr != nil {
+				tc.checkErr(t, respErr)
+			}
+		})
+	}
+}
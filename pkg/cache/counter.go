@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// Counter counts requests per name to find the most requested ones. It tracks at most maxNames
+	// names, when full the least requested half is dropped
+	Counter struct {
+		mu       sync.Mutex
+		maxNames int
+		counts   map[string]uint64
+	}
+
+	// NameCount is the number of requests of a name
+	NameCount struct {
+		Name  string `json:"name"`
+		Count uint64 `json:"count"`
+	}
+)
+
+// NewCounter creates a counter tracking at most maxNames names
+func NewCounter(maxNames int) (*Counter, error) {
+	if maxNames < 2 {
+		return nil, errors.Wrap(ErrInvalidParam, "max names")
+	}
+
+	return &Counter{
+		maxNames: maxNames,
+		counts:   map[string]uint64{},
+	}, nil
+}
+
+// Inc counts a request of name
+func (c *Counter) Inc(name string) {
+	c.Add(name, 1)
+}
+
+// Add counts n requests of name, e.g. to restore recorded counts
+func (c *Counter) Add(name string, n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.counts[name]; !ok && len(c.counts) >= c.maxNames {
+		for _, nc := range c.sorted()[c.maxNames/2:] {
+			delete(c.counts, nc.Name)
+		}
+	}
+
+	c.counts[name] += n
+}
+
+// Top returns the n most requested names, most requested first, names with the same count are
+// sorted by name
+func (c *Counter) Top(n int) []NameCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	top := c.sorted()
+	if n < len(top) {
+		top = top[:n]
+	}
+
+	return top
+}
+
+func (c *Counter) sorted() []NameCount {
+	counts := make([]NameCount, 0, len(c.counts))
+	for name, count := range c.counts {
+		counts = append(counts, NameCount{Name: name, Count: count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+
+		return counts[i].Name < counts[j].Name
+	})
+
+	return counts
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCounter_Error(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewCounter(1)
+	require.EqualError(t, err, "max names: invalid parameter")
+	require.Nil(t, c)
+}
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		maxNames int
+		names    []string
+		top      int
+		expected []NameCount
+	}
+
+	tests := map[string]testcase{
+		"ReturnsMostRequestedFirst": {
+			maxNames: 10,
+			names:    []string{"mew", "ditto", "mewtwo", "mewtwo", "ditto", "mewtwo"},
+			top:      2,
+			expected: []NameCount{{Name: "mewtwo", Count: 3}, {Name: "ditto", Count: 2}},
+		},
+		"SortsSameCountByName": {
+			maxNames: 10,
+			names:    []string{"mewtwo", "ditto", "mew"},
+			top:      10,
+			expected: []NameCount{{Name: "ditto", Count: 1}, {Name: "mew", Count: 1}, {Name: "mewtwo", Count: 1}},
+		},
+		"DropsLeastRequestedHalfWhenFull": {
+			maxNames: 4,
+			names:    []string{"mew", "mew", "mew", "ditto", "ditto", "mewtwo", "eevee", "pikachu"},
+			top:      10,
+			expected: []NameCount{{Name: "mew", Count: 3}, {Name: "ditto", Count: 2}, {Name: "pikachu", Count: 1}},
+		},
+		"ReturnsEmptyWhenNothingCounted": {
+			maxNames: 4,
+			top:      10,
+			expected: []NameCount{},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := NewCounter(tc.maxNames)
+			require.NoError(t, err)
+
+			for _, name := range tc.names {
+				c.Inc(name)
+			}
+
+			require.Equal(t, tc.expected, c.Top(tc.top))
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// Fallback uses primary, e.g. a remote cache, and switches to secondary, e.g. an in-process cache,
+// once primary fails. Primary is tried again after retry, so a remote cache being down costs one
+// failed call per retry interval rather than one per request. Entries written to secondary are not
+// copied back to primary
+type Fallback struct {
+	primary   Backend
+	secondary Backend
+	retry     time.Duration
+
+	mu        sync.Mutex
+	downUntil time.Time
+	now       func() time.Time
+}
+
+// NewFallback creates a new Fallback
+func NewFallback(primary Backend, secondary Backend, retry time.Duration) (*Fallback, error) {
+	if primary == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "primary")
+	}
+
+	if secondary == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "secondary")
+	}
+
+	if retry <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "retry")
+	}
+
+	return &Fallback{
+		primary:   primary,
+		secondary: secondary,
+		retry:     retry,
+		now:       time.Now,
+	}, nil
+}
+
+// Get returns the value of key from primary, or from secondary while primary is down
+func (f *Fallback) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if f.up() {
+		value, ok, err := f.primary.Get(ctx, key)
+		if err == nil {
+			return value, ok, nil
+		}
+
+		f.down(err)
+	}
+
+	return f.secondary.Get(ctx, key)
+}
+
+// Set caches value in primary, or in secondary while primary is down
+func (f *Fallback) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if f.up() {
+		err := f.primary.Set(ctx, key, value, ttl)
+		if err == nil {
+			return nil
+		}
+
+		f.down(err)
+	}
+
+	return f.secondary.Set(ctx, key, value, ttl)
+}
+
+// IncrBy increments key in primary, or in secondary while primary is down. Counts kept in
+// secondary are local to the replica
+func (f *Fallback) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if f.up() {
+		n, err := f.primary.IncrBy(ctx, key, delta, ttl)
+		if err == nil {
+			return n, nil
+		}
+
+		f.down(err)
+	}
+
+	return f.secondary.IncrBy(ctx, key, delta, ttl)
+}
+
+// Delete removes keys from both backends so entries cached while primary was down are removed too,
+// it returns the number removed from primary unless it is down
+func (f *Fallback) Delete(ctx context.Context, keys ...string) (int, error) {
+	removed, err := f.secondary.Delete(ctx, keys...)
+	if err != nil {
+		return 0, err
+	}
+
+	if f.up() {
+		n, err := f.primary.Delete(ctx, keys...)
+		if err == nil {
+			return n, nil
+		}
+
+		f.down(err)
+	}
+
+	return removed, nil
+}
+
+// Keys returns the keys of primary, or of secondary while primary is down
+func (f *Fallback) Keys(ctx context.Context, prefix string) ([]string, error) {
+	if f.up() {
+		keys, err := f.primary.Keys(ctx, prefix)
+		if err == nil {
+			return keys, nil
+		}
+
+		f.down(err)
+	}
+
+	return f.secondary.Keys(ctx, prefix)
+}
+
+// Ping checks primary is reachable, the service keeps working without it
+func (f *Fallback) Ping(ctx context.Context) error {
+	return f.primary.Ping(ctx)
+}
+
+// Evictions returns the evictions of secondary when it counts them
+func (f *Fallback) Evictions() uint64 {
+	if ec, ok := f.secondary.(evictionCounter); ok {
+		return ec.Evictions()
+	}
+
+	return 0
+}
+
+func (f *Fallback) up() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return !f.now().Before(f.downUntil)
+}
+
+func (f *Fallback) down(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// a canceled request says nothing about primary
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	now := f.now()
+	if now.Before(f.downUntil) {
+		return
+	}
+
+	f.downUntil = now.Add(f.retry)
+
+	log.Warn().Err(err).Dur("retry", f.retry).Msg("cache backend unavailable, falling back")
+}
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFallback_Error(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewMemory(1)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		primary   Backend
+		secondary Backend
+		retry     time.Duration
+		err       string
+	}{
+		"ReturnsErrorWhenPrimaryNil": {
+			secondary: m,
+			retry:     time.Second,
+			err:       "primary: invalid parameter",
+		},
+		"ReturnsErrorWhenSecondaryNil": {
+			primary: m,
+			retry:   time.Second,
+			err:     "secondary: invalid parameter",
+		},
+		"ReturnsErrorWhenRetryNotPositive": {
+			primary:   m,
+			secondary: m,
+			err:       "retry: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := NewFallback(tc.primary, tc.secondary, tc.retry)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, f)
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		run func(t *testing.T, f *Fallback, primary *switchBackend, secondary *Memory, clock *time.Time)
+	}
+
+	tests := map[string]testcase{
+		"UsesPrimaryWhileUp": {
+			run: func(t *testing.T, f *Fallback, primary *switchBackend, secondary *Memory, clock *time.Time) {
+				set(t, f, "mewtwo", "150", time.Hour)
+
+				requireValue(t, primary, "mewtwo", "150")
+				requireKeys(t, secondary, "")
+				requireKeys(t, f, "", "mewtwo")
+				require.NoError(t, f.Ping(context.Background()))
+			},
+		},
+		"UsesSecondaryWhilePrimaryDown": {
+			run: func(t *testing.T, f *Fallback, primary *switchBackend, secondary *Memory, clock *time.Time) {
+				primary.setDown(true)
+
+				set(t, f, "mewtwo", "150", time.Hour)
+				requireValue(t, f, "mewtwo", "150")
+				requireKeys(t, f, "", "mewtwo")
+				requireValue(t, secondary, "mewtwo", "150")
+				require.ErrorIs(t, f.Ping(context.Background()), errBackendDown)
+
+				n, err := f.IncrBy(context.Background(), "quota", 1, time.Hour)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), n)
+				requireValue(t, secondary, "quota", "1")
+			},
+		},
+		"RetriesPrimaryAfterRetryInterval": {
+			run: func(t *testing.T, f *Fallback, primary *switchBackend, secondary *Memory, clock *time.Time) {
+				primary.setDown(true)
+				set(t, f, "mew", "151", time.Hour)
+
+				// primary is not tried again before the retry interval
+				primary.setDown(false)
+				set(t, f, "mewtwo", "150", time.Hour)
+				requireKeys(t, primary, "")
+
+				*clock = clock.Add(time.Minute)
+				set(t, f, "ditto", "132", time.Hour)
+
+				requireKeys(t, primary, "", "ditto")
+				requireKeys(t, secondary, "", "mew", "mewtwo")
+			},
+		},
+		"DeletesFromBothBackends": {
+			run: func(t *testing.T, f *Fallback, primary *switchBackend, secondary *Memory, clock *time.Time) {
+				set(t, primary, "mewtwo", "150", time.Hour)
+				set(t, secondary, "mewtwo", "150", time.Hour)
+				set(t, secondary, "mew", "151", time.Hour)
+
+				n, err := f.Delete(context.Background(), "mewtwo", "mew")
+				require.NoError(t, err)
+				require.Equal(t, 1, n)
+
+				requireKeys(t, primary, "")
+				requireKeys(t, secondary, "")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			primary := newSwitchBackend(t)
+
+			secondary, err := NewMemory(10)
+			require.NoError(t, err)
+
+			f, err := NewFallback(primary, secondary, time.Minute)
+			require.NoError(t, err)
+
+			clock := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			f.now = func() time.Time { return clock }
+
+			tc.run(t, f, primary, secondary, &clock)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// History records the request counts of a Counter in a file so the most requested names survive a
+// restart, e.g. to warm up the caches after a deploy
+type History struct {
+	path    string
+	counter *Counter
+}
+
+// NewHistory creates a History recording counter in the file at path
+func NewHistory(path string, counter *Counter) (*History, error) {
+	if path == "" {
+		return nil, errors.Wrap(ErrInvalidParam, "path")
+	}
+
+	if counter == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "counter")
+	}
+
+	return &History{
+		path:    path,
+		counter: counter,
+	}, nil
+}
+
+// Load adds the recorded counts to the counter, a missing file is not an error
+func (h *History) Load() error {
+	data, err := ioutil.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to read request history")
+	}
+
+	var counts []NameCount
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return errors.Wrapf(err, "invalid request history %s", h.path)
+	}
+
+	for _, nc := range counts {
+		h.counter.Add(nc.Name, nc.Count)
+	}
+
+	return nil
+}
+
+// Save records the current counts, the file is replaced atomically so a crash never leaves it
+// half written
+func (h *History) Save() error {
+	data, err := json.Marshal(h.counter.Top(h.counter.maxNames))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request history")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(h.path), filepath.Base(h.path)+".*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create request history")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return errors.Wrap(err, "failed to write request history")
+	}
+
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write request history")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), h.path), "failed to replace request history")
+}
+
+// Run saves the counts every interval until ctx is done, then saves them a last time
+func (h *History) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := h.Save(); err != nil {
+				log.Error().Err(err).Msg("failed to save request history")
+			}
+
+			return
+		case <-ticker.C:
+			if err := h.Save(); err != nil {
+				log.Error().Err(err).Msg("failed to save request history")
+			}
+		}
+	}
+}
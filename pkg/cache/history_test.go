@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHistory_Error(t *testing.T) {
+	t.Parallel()
+
+	counter, err := NewCounter(10)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		path    string
+		counter *Counter
+		err     string
+	}{
+		"ReturnsErrorWhenPathEmpty": {
+			counter: counter,
+			err:     "path: invalid parameter",
+		},
+		"ReturnsErrorWhenCounterNil": {
+			path: "history.json",
+			err:  "counter: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			h, err := NewHistory(tc.path, tc.counter)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, h)
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		run func(t *testing.T, path string)
+	}
+
+	tests := map[string]testcase{
+		"RestoresSavedCounts": {
+			run: func(t *testing.T, path string) {
+				counter, err := NewCounter(10)
+				require.NoError(t, err)
+
+				for _, name := range []string{"mewtwo", "mewtwo", "ditto"} {
+					counter.Inc(name)
+				}
+
+				h, err := NewHistory(path, counter)
+				require.NoError(t, err)
+				require.NoError(t, h.Save())
+
+				restored, err := NewCounter(10)
+				require.NoError(t, err)
+				restored.Inc("ditto")
+
+				h, err = NewHistory(path, restored)
+				require.NoError(t, err)
+				require.NoError(t, h.Load())
+
+				require.Equal(t, []NameCount{{Name: "ditto", Count: 2}, {Name: "mewtwo", Count: 2}}, restored.Top(10))
+			},
+		},
+		"LoadsNothingWhenFileMissing": {
+			run: func(t *testing.T, path string) {
+				counter, err := NewCounter(10)
+				require.NoError(t, err)
+
+				h, err := NewHistory(path, counter)
+				require.NoError(t, err)
+				require.NoError(t, h.Load())
+
+				require.Empty(t, counter.Top(10))
+			},
+		},
+		"ReturnsErrorWhenFileInvalid": {
+			run: func(t *testing.T, path string) {
+				require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+
+				counter, err := NewCounter(10)
+				require.NoError(t, err)
+
+				h, err := NewHistory(path, counter)
+				require.NoError(t, err)
+				require.Contains(t, h.Load().Error(), "invalid request history")
+			},
+		},
+		"SavesWhenRunStops": {
+			run: func(t *testing.T, path string) {
+				counter, err := NewCounter(10)
+				require.NoError(t, err)
+				counter.Inc("mewtwo")
+
+				h, err := NewHistory(path, counter)
+				require.NoError(t, err)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				h.Run(ctx, time.Hour)
+
+				data, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+				require.JSONEq(t, `[{"name":"mewtwo","count":1}]`, string(data))
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.run(t, filepath.Join(t.TempDir(), "history.json"))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Package cache holds the cache backends, the typed store on top of them and the request counter
+// shared by the Pokemon and translation caches
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidParam = errors.New("invalid parameter")
+
+type (
+	// Stats describes the content and usage of a cache
+	Stats struct {
+		Entries   int    `json:"entries"`
+		Hits      uint64 `json:"hits"`
+		Misses    uint64 `json:"misses"`
+		Evictions uint64 `json:"evictions"`
+		Errors    uint64 `json:"errors"`
+	}
+
+	// Memory is an in-process least recently used Backend with a ttl per entry, safe for concurrent
+	// use. Expired entries are dropped when they are read or evicted
+	Memory struct {
+		mu         sync.Mutex
+		maxEntries int
+		entries    map[string]*list.Element
+		order      *list.List
+		evictions  uint64
+		now        func() time.Time
+	}
+
+	entry struct {
+		key     string
+		value   []byte
+		expires time.Time
+	}
+)
+
+// NewMemory creates a cache holding at most maxEntries entries
+func NewMemory(maxEntries int) (*Memory, error) {
+	if maxEntries < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "max entries")
+	}
+
+	return &Memory{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+		now:        time.Now,
+	}, nil
+}
+
+// Get returns the value cached for key and refreshes its recency
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if m.expired(el) {
+		m.remove(el)
+
+		return nil, false, nil
+	}
+
+	m.order.MoveToFront(el)
+
+	return el.Value.(*entry).value, true, nil
+}
+
+// Set caches value for key for ttl, the least recently used entry is evicted when the cache is full
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expires := m.now().Add(ttl)
+
+	if el, ok := m.entries[key]; ok {
+		e := el.Value.(*entry)
+		e.value = value
+		e.expires = expires
+		m.order.MoveToFront(el)
+
+		return nil
+	}
+
+	if m.order.Len() >= m.maxEntries {
+		m.evict()
+	}
+
+	m.entries[key] = m.order.PushFront(&entry{key: key, value: value, expires: expires})
+
+	return nil
+}
+
+// IncrBy adds delta to the integer value of key, it fails when the value is not an integer
+func (m *Memory) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok || m.expired(el) {
+		if ok {
+			m.remove(el)
+		}
+
+		if m.order.Len() >= m.maxEntries {
+			m.evict()
+		}
+
+		value := []byte(strconv.FormatInt(delta, 10))
+		m.entries[key] = m.order.PushFront(&entry{key: key, value: value, expires: m.now().Add(ttl)})
+
+		return delta, nil
+	}
+
+	e := el.Value.(*entry)
+
+	n, err := strconv.ParseInt(string(e.value), 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("value of %s is not an integer", key)
+	}
+
+	n += delta
+	e.value = []byte(strconv.FormatInt(n, 10))
+	m.order.MoveToFront(el)
+
+	return n, nil
+}
+
+// Delete removes the entries of keys, it returns the number of entries removed
+func (m *Memory) Delete(ctx context.Context, keys ...string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+
+	for _, key := range keys {
+		el, ok := m.entries[key]
+		if !ok {
+			continue
+		}
+
+		if !m.expired(el) {
+			removed++
+		}
+
+		m.remove(el)
+	}
+
+	return removed, nil
+}
+
+// Keys returns the keys starting with prefix of the entries that have not expired, sorted
+func (m *Memory) Keys(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := []string{}
+	for key, el := range m.entries {
+		if strings.HasPrefix(key, prefix) && !m.expired(el) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// Ping always succeeds
+func (m *Memory) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Evictions returns the number of entries evicted before they expired
+func (m *Memory) Evictions() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.evictions
+}
+
+// evict drops the least recently used entry, an expired entry is not counted as an eviction
+func (m *Memory) evict() {
+	el := m.order.Back()
+	if !m.expired(el) {
+		m.evictions++
+	}
+
+	m.remove(el)
+}
+
+func (m *Memory) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(*entry).key)
+}
+
+func (m *Memory) expired(el *list.Element) bool {
+	return !m.now().Before(el.Value.(*entry).expires)
+}
@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemory_Error(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewMemory(0)
+	require.EqualError(t, err, "max entries: invalid parameter")
+	require.Nil(t, m)
+}
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		maxEntries int
+		run        func(t *testing.T, m *Memory, clock *time.Time)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsCachedValue": {
+			maxEntries: 2,
+			run: func(t *testing.T, m *Memory, clock *time.Time) {
+				set(t, m, "mewtwo", "150", time.Hour)
+
+				requireValue(t, m, "mewtwo", "150")
+				requireMissing(t, m, "mew")
+			},
+		},
+		"ExpiresEntriesAfterTTL": {
+			maxEntries: 2,
+			run: func(t *testing.T, m *Memory, clock *time.Time) {
+				set(t, m, "mewtwo", "150", time.Minute)
+				*clock = clock.Add(time.Minute)
+
+				requireKeys(t, m, "")
+				requireMissing(t, m, "mewtwo")
+			},
+		},
+		"EvictsLeastRecentlyUsedWhenFull": {
+			maxEntries: 2,
+			run: func(t *testing.T, m *Memory, clock *time.Time) {
+				set(t, m, "mew", "151", time.Hour)
+				set(t, m, "mewtwo", "150", time.Hour)
+				requireValue(t, m, "mew", "151")
+				set(t, m, "ditto", "132", time.Hour)
+
+				requireKeys(t, m, "", "ditto", "mew")
+				require.Equal(t, uint64(1), m.Evictions())
+			},
+		},
+		"DoesNotCountExpiredEntryAsEviction": {
+			maxEntries: 1,
+			run: func(t *testing.T, m *Memory, clock *time.Time) {
+				set(t, m, "mew", "151", time.Minute)
+				*clock = clock.Add(time.Hour)
+				set(t, m, "ditto", "132", time.Hour)
+
+				requireKeys(t, m, "", "ditto")
+				require.Equal(t, uint64(0), m.Evictions())
+			},
+		},
+		"SetReplacesExistingValue": {
+			maxEntries: 1,
+			run: func(t *testing.T, m *Memory, clock *time.Time) {
+				set(t, m, "mew", "1", time.Hour)
+				set(t, m, "mew", "151", time.Hour)
+
+				requireValue(t, m, "mew", "151")
+				require.Equal(t, uint64(0), m.Evictions())
+			},
+		},
+		"IncrementsIntegerValuesKeepingTheirExpiry": {
+			maxEntries: 2,
+			run: func(t *testing.T, m *Memory, clock *time.Time) {
+				n, err := m.IncrBy(context.Background(), "hour", 1, time.Hour)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), n)
+
+				*clock = clock.Add(30 * time.Minute)
+
+				n, err = m.IncrBy(context.Background(), "hour", 2, 24*time.Hour)
+				require.NoError(t, err)
+				require.Equal(t, int64(3), n)
+
+				*clock = clock.Add(30 * time.Minute)
+
+				n, err = m.IncrBy(context.Background(), "hour", -1, time.Hour)
+				require.NoError(t, err)
+				require.Equal(t, int64(-1), n)
+
+				set(t, m, "mewtwo", "mewtwo", time.Hour)
+				_, err = m.IncrBy(context.Background(), "mewtwo", 1, time.Hour)
+				require.EqualError(t, err, "value of mewtwo is not an integer")
+			},
+		},
+		"ListsAndDeletesKeysByPrefix": {
+			maxEntries: 10,
+			run: func(t *testing.T, m *Memory, clock *time.Time) {
+				for i := 0; i < 3; i++ {
+					set(t, m, fmt.Sprintf("mew-%d", i), "151", time.Hour)
+				}
+				set(t, m, "ditto", "132", time.Hour)
+
+				requireKeys(t, m, "mew", "mew-0", "mew-1", "mew-2")
+
+				n, err := m.Delete(context.Background(), "mew-0", "mew-1", "mew-2", "eevee")
+				require.NoError(t, err)
+				require.Equal(t, 3, n)
+				requireKeys(t, m, "", "ditto")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := NewMemory(tc.maxEntries)
+			require.NoError(t, err)
+
+			clock := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			m.now = func() time.Time { return clock }
+
+			tc.run(t, m, &clock)
+		})
+	}
+}
+
+func set(t *testing.T, b Backend, key string, value string, ttl time.Duration) {
+	t.Helper()
+
+	require.NoError(t, b.Set(context.Background(), key, []byte(value), ttl))
+}
+
+func requireValue(t *testing.T, b Backend, key string, value string) {
+	t.Helper()
+
+	v, ok, err := b.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, value, string(v))
+}
+
+func requireMissing(t *testing.T, b Backend, key string) {
+	t.Helper()
+
+	_, ok, err := b.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func requireKeys(t *testing.T, b Backend, prefix string, keys ...string) {
+	t.Helper()
+
+	got, err := b.Keys(context.Background(), prefix)
+	require.NoError(t, err)
+	require.ElementsMatch(t, keys, got)
+}
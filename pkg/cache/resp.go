@@ -0,0 +1,530 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// scanCount is the number of keys a single SCAN is asked to look at
+	scanCount = 1000
+
+	// maxBulkLen and maxArrayLen bound the replies read, so a corrupted length can not make the
+	// client allocate arbitrary amounts of memory
+	maxBulkLen  = 64 << 20
+	maxArrayLen = 1 << 20
+)
+
+type (
+	// RESPOptions configures a RESP backend
+	RESPOptions struct {
+		// Address is the host:port of the server
+		Address string
+		// Password authenticates the connections when not empty
+		Password string
+		// DB is the database selected on the connections
+		DB int
+		// Prefix is prepended to every key, followed by a colon, so several services can share a server
+		Prefix string
+		// DialTimeout bounds connecting and authenticating
+		DialTimeout time.Duration
+		// Timeout bounds a single command, unless the context has an earlier deadline
+		Timeout time.Duration
+		// PoolSize is the number of idle connections kept open
+		PoolSize int
+	}
+
+	// RESP is a Backend speaking the Redis serialisation protocol, e.g. to Redis, Valkey or KeyDB.
+	// Connections are opened on demand and kept in a pool, it is safe for concurrent use
+	RESP struct {
+		opts   RESPOptions
+		prefix string
+		pool   chan *respConn
+	}
+
+	// RESPError is an error reply of the server
+	RESPError string
+
+	respConn struct {
+		conn net.Conn
+		r    *bufio.Reader
+	}
+)
+
+// NewRESP creates a new RESP backend, it does not connect until the first command
+func NewRESP(opts RESPOptions) (*RESP, error) {
+	if opts.Address == "" {
+		return nil, errors.Wrap(ErrInvalidParam, "address")
+	}
+
+	if opts.DB < 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "db")
+	}
+
+	if opts.DialTimeout <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "dial timeout")
+	}
+
+	if opts.Timeout <= 0 {
+		return nil, errors.Wrap(ErrInvalidParam, "timeout")
+	}
+
+	if opts.PoolSize < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "pool size")
+	}
+
+	prefix := ""
+	if opts.Prefix != "" {
+		prefix = opts.Prefix + ":"
+	}
+
+	return &RESP{
+		opts:   opts,
+		prefix: prefix,
+		pool:   make(chan *respConn, opts.PoolSize),
+	}, nil
+}
+
+func (e RESPError) Error() string {
+	return string(e)
+}
+
+// Get returns the value of key, false when it is not cached
+func (r *RESP) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := r.do(ctx, "GET", r.prefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if reply == nil {
+		return nil, false, nil
+	}
+
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, errors.Errorf("unexpected reply to GET: %T", reply)
+	}
+
+	return value, true, nil
+}
+
+// Set caches value for key for ttl, rounded up to the millisecond
+func (r *RESP) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := r.do(ctx, "SET", r.prefix+key, string(value), "PX", strconv.FormatInt(milliseconds(ttl), 10))
+
+	return err
+}
+
+// IncrBy adds delta to the integer value of key, the expiry is set when the increment created it.
+// The key is created with its expiry and incremented in a single transaction, so it can not be
+// left without one
+func (r *RESP) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	replies, err := r.pipeline(ctx,
+		[]string{"MULTI"},
+		[]string{"SET", r.prefix + key, "0", "PX", strconv.FormatInt(milliseconds(ttl), 10), "NX"},
+		[]string{"INCRBY", r.prefix + key, strconv.FormatInt(delta, 10)},
+		[]string{"EXEC"},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	exec, ok := replies[3].([]interface{})
+	if !ok || len(exec) != 2 {
+		return 0, errors.Errorf("unexpected reply to EXEC: %v", replies[3])
+	}
+
+	if respErr, ok := exec[1].(RESPError); ok {
+		return 0, errors.Wrap(respErr, "INCRBY failed")
+	}
+
+	n, ok := exec[1].(int64)
+	if !ok {
+		return 0, errors.Errorf("unexpected reply to INCRBY: %T", exec[1])
+	}
+
+	return n, nil
+}
+
+// Delete removes keys and returns the number of entries removed
+func (r *RESP) Delete(ctx context.Context, keys ...string) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	args := []string{"DEL"}
+	for _, key := range keys {
+		args = append(args, r.prefix+key)
+	}
+
+	reply, err := r.do(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, errors.Errorf("unexpected reply to DEL: %T", reply)
+	}
+
+	return int(n), nil
+}
+
+// Keys returns the keys starting with prefix, it walks the whole keyspace with SCAN
+func (r *RESP) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var (
+		cursor  = "0"
+		pattern = escapePattern(r.prefix+prefix) + "*"
+		seen    = map[string]bool{}
+		keys    = []string{}
+	)
+
+	for {
+		reply, err := r.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(scanCount))
+		if err != nil {
+			return nil, err
+		}
+
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, errors.Errorf("unexpected reply to SCAN: %v", reply)
+		}
+
+		next, ok := page[0].([]byte)
+		if !ok {
+			return nil, errors.Errorf("unexpected SCAN cursor: %v", page[0])
+		}
+
+		found, ok := page[1].([]interface{})
+		if !ok {
+			return nil, errors.Errorf("unexpected SCAN keys: %v", page[1])
+		}
+
+		// SCAN may return a key more than once
+		for _, k := range found {
+			key, ok := k.([]byte)
+			if !ok || seen[string(key)] {
+				continue
+			}
+
+			seen[string(key)] = true
+			keys = append(keys, strings.TrimPrefix(string(key), r.prefix))
+		}
+
+		cursor = string(next)
+		if cursor == "0" {
+			return keys, nil
+		}
+	}
+}
+
+// Ping checks the server is reachable
+func (r *RESP) Ping(ctx context.Context) error {
+	_, err := r.do(ctx, "PING")
+
+	return err
+}
+
+// Close closes the idle connections
+func (r *RESP) Close() error {
+	for {
+		select {
+		case c := <-r.pool:
+			_ = c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do sends a command and reads its reply, an error reply is returned as a RESPError and keeps the
+// connection while any other error closes it. A pooled connection closed by the server, e.g. when it
+// restarted, is replaced by a new one and the command sent again
+func (r *RESP) do(ctx context.Context, args ...string) (interface{}, error) {
+	replies, err := r.pipeline(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return replies[0], nil
+}
+
+// pipeline sends commands at once on a single connection and reads their replies like do, the
+// first error reply is returned once every reply has been read
+func (r *RESP) pipeline(ctx context.Context, cmds ...[]string) ([]interface{}, error) {
+	c, pooled, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replies, err := c.pipeline(ctx, r.opts.Timeout, cmds...)
+
+	if err != nil && pooled && stale(err) {
+		_ = c.conn.Close()
+
+		if c, err = r.dial(ctx); err != nil {
+			return nil, err
+		}
+
+		replies, err = c.pipeline(ctx, r.opts.Timeout, cmds...)
+	}
+
+	var respErr RESPError
+	if err != nil && !errors.As(err, &respErr) {
+		_ = c.conn.Close()
+
+		return nil, errors.Wrapf(err, "%s failed", cmds[0][0])
+	}
+
+	r.release(c)
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s failed", cmds[0][0])
+	}
+
+	return replies, nil
+}
+
+// conn returns an idle connection from the pool, or a new one when there is none
+func (r *RESP) conn(ctx context.Context) (*respConn, bool, error) {
+	select {
+	case c := <-r.pool:
+		return c, true, nil
+	default:
+	}
+
+	c, err := r.dial(ctx)
+
+	return c, false, err
+}
+
+// dial opens a new connection, authenticated and with the database selected
+func (r *RESP) dial(ctx context.Context) (*respConn, error) {
+	dialer := net.Dialer{Timeout: r.opts.DialTimeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", r.opts.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to connect to cache")
+	}
+
+	c := &respConn{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+	}
+
+	if r.opts.Password != "" {
+		// the error is not wrapped, it may quote the password
+		if _, err := c.do(ctx, r.opts.DialTimeout, "AUTH", r.opts.Password); err != nil {
+			_ = conn.Close()
+
+			return nil, errors.New("unable to authenticate to cache")
+		}
+	}
+
+	if r.opts.DB != 0 {
+		if _, err := c.do(ctx, r.opts.DialTimeout, "SELECT", strconv.Itoa(r.opts.DB)); err != nil {
+			_ = conn.Close()
+
+			return nil, errors.Wrap(err, "unable to select cache database")
+		}
+	}
+
+	return c, nil
+}
+
+// staleError is returned when the server had closed a connection before the commands were sent, the
+// commands were not run then
+type staleError struct {
+	error
+}
+
+func (e staleError) Unwrap() error {
+	return e.error
+}
+
+// stale reports whether the commands failed on a connection closed before they were sent
+func stale(err error) bool {
+	var staleErr staleError
+
+	return errors.As(err, &staleErr)
+}
+
+// release puts a connection back in the pool, it is closed when the pool is full
+func (r *RESP) release(c *respConn) {
+	select {
+	case r.pool <- c:
+	default:
+		_ = c.conn.Close()
+	}
+}
+
+func (c *respConn) do(ctx context.Context, timeout time.Duration, args ...string) (interface{}, error) {
+	replies, err := c.pipeline(ctx, timeout, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return replies[0], nil
+}
+
+func (c *respConn) pipeline(ctx context.Context, timeout time.Duration, cmds ...[]string) ([]interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var buf []byte
+	for _, args := range cmds {
+		buf = append(buf, "*"+strconv.Itoa(len(args))+"\r\n"...)
+		for _, arg := range args {
+			buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+		}
+	}
+
+	// a failed write did not send every command, an incomplete command is not run and neither is a
+	// transaction without its EXEC. Timeouts are not stale as the server may still read the commands
+	if _, err := c.conn.Write(buf); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil, err
+		}
+
+		return nil, staleError{err}
+	}
+
+	// the connection was closed before the commands were read when it ends before any reply, once
+	// a reply started or the connection was reset the commands may have been run
+	if _, err := c.r.Peek(1); errors.Is(err, io.EOF) {
+		return nil, staleError{err}
+	}
+
+	var (
+		replies  = make([]interface{}, len(cmds))
+		firstErr error
+	)
+
+	// every reply is read even after an error reply so the connection can be reused
+	for i := range replies {
+		reply, err := readReply(c.r)
+
+		var respErr RESPError
+		if err != nil && !errors.As(err, &respErr) {
+			return nil, err
+		}
+
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+
+		replies[i] = reply
+	}
+
+	return replies, firstErr
+}
+
+// readReply reads a RESP2 reply: a simple string as a string, a bulk string as []byte, an integer as
+// int64, an array as []interface{}, a null bulk string or array as nil and an error as a RESPError
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.Errorf("malformed reply %q", line)
+	}
+
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, RESPError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+
+		if n > maxBulkLen {
+			return nil, errors.Errorf("bulk string of %d bytes exceeds %d", n, maxBulkLen)
+		}
+
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+
+		if n > maxArrayLen {
+			return nil, errors.Errorf("array of %d items exceeds %d", n, maxArrayLen)
+		}
+
+		items := make([]interface{}, n)
+		for i := range items {
+			item, err := readReply(r)
+
+			// an error within an array is an item, the rest of the array still has to be read
+			var respErr RESPError
+			if err != nil && !errors.As(err, &respErr) {
+				return nil, err
+			}
+
+			if err != nil {
+				item = respErr
+			}
+
+			items[i] = item
+		}
+
+		return items, nil
+	}
+
+	return nil, errors.Errorf("unknown reply type %q", kind)
+}
+
+// milliseconds returns ttl in milliseconds rounded up, at least 1
+func milliseconds(ttl time.Duration) int64 {
+	ms := int64((ttl + time.Millisecond - 1) / time.Millisecond)
+	if ms < 1 {
+		return 1
+	}
+
+	return ms
+}
+
+// escapePattern escapes the glob characters of a SCAN pattern
+func escapePattern(s string) string {
+	var b strings.Builder
+
+	for _, ch := range s {
+		switch ch {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+
+		b.WriteRune(ch)
+	}
+
+	return b.String()
+}
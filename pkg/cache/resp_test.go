@@ -0,0 +1,557 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// respServer is an in-process stand-in for a Redis protocol server, it implements the commands
+// used by RESP. SCAN returns two keys per page to exercise the cursor
+type respServer struct {
+	lis      net.Listener
+	password string
+
+	mu    sync.Mutex
+	data  map[string]respEntry
+	dbs   map[string]bool
+	conns map[net.Conn]bool
+	// resetAfter is a command run once without replying, the connection is reset instead
+	resetAfter string
+}
+
+type respEntry struct {
+	value []byte
+	// expires is zero for keys without expiry
+	expires time.Time
+}
+
+func (e respEntry) expired() bool {
+	return !e.expires.IsZero() && !time.Now().Before(e.expires)
+}
+
+// respSession is the state of a connection, queue holds the commands sent after MULTI
+type respSession struct {
+	authed bool
+	multi  bool
+	queue  [][]string
+}
+
+func newRESPServer(t *testing.T, password string) *respServer {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &respServer{
+		lis:      lis,
+		password: password,
+		data:     map[string]respEntry{},
+		dbs:      map[string]bool{},
+		conns:    map[net.Conn]bool{},
+	}
+
+	go s.serve()
+	t.Cleanup(func() { _ = lis.Close() })
+
+	return s
+}
+
+func (s *respServer) addr() string {
+	return s.lis.Addr().String()
+}
+
+func (s *respServer) serve() {
+	for {
+		conn, err := s.lis.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+// dropConns closes the open connections, as a restarting server would
+func (s *respServer) dropConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+func (s *respServer) handle(conn net.Conn) {
+	s.mu.Lock()
+	s.conns[conn] = true
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	session := &respSession{authed: s.password == ""}
+
+	for {
+		cmd, err := readReply(r)
+		if err != nil {
+			return
+		}
+
+		items, _ := cmd.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+
+		reply := s.exec(args, session)
+
+		if s.reset(args[0]) {
+			_ = conn.(*net.TCPConn).SetLinger(0)
+
+			return
+		}
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// reset reports whether the connection is reset after running name
+func (s *respServer) reset(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.EqualFold(name, s.resetAfter) {
+		return false
+	}
+
+	s.resetAfter = ""
+
+	return true
+}
+
+func (s *respServer) exec(args []string, session *respSession) string {
+	if len(args) == 0 {
+		return "-ERR empty command\r\n"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.ToUpper(args[0])
+
+	if name == "AUTH" {
+		if len(args) != 2 || args[1] != s.password {
+			return "-WRONGPASS invalid password\r\n"
+		}
+
+		session.authed = true
+
+		return "+OK\r\n"
+	}
+
+	if !session.authed {
+		return "-NOAUTH Authentication required.\r\n"
+	}
+
+	switch {
+	case name == "MULTI":
+		session.multi = true
+
+		return "+OK\r\n"
+	case name == "EXEC":
+		reply := "*" + strconv.Itoa(len(session.queue)) + "\r\n"
+		for _, queued := range session.queue {
+			reply += s.run(queued)
+		}
+
+		session.multi, session.queue = false, nil
+
+		return reply
+	case session.multi:
+		session.queue = append(session.queue, args)
+
+		return "+QUEUED\r\n"
+	}
+
+	return s.run(args)
+}
+
+// run executes a command, the caller holds s.mu
+func (s *respServer) run(args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SELECT":
+		s.dbs[args[1]] = true
+
+		return "+OK\r\n"
+	case "GET":
+		e, ok := s.data[args[1]]
+		if !ok || e.expired() {
+			return "$-1\r\n"
+		}
+
+		return bulk(string(e.value))
+	case "SET":
+		ms, err := strconv.Atoi(args[4])
+		if err != nil || strings.ToUpper(args[3]) != "PX" {
+			return "-ERR syntax error\r\n"
+		}
+
+		if e, ok := s.data[args[1]]; ok && !e.expired() && len(args) == 6 && strings.ToUpper(args[5]) == "NX" {
+			return "$-1\r\n"
+		}
+
+		s.data[args[1]] = respEntry{
+			value:   []byte(args[2]),
+			expires: time.Now().Add(time.Duration(ms) * time.Millisecond),
+		}
+
+		return "+OK\r\n"
+	case "INCRBY":
+		delta, _ := strconv.ParseInt(args[2], 10, 64)
+
+		e, ok := s.data[args[1]]
+		if !ok || e.expired() {
+			e = respEntry{value: []byte("0")}
+		}
+
+		n, err := strconv.ParseInt(string(e.value), 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+
+		e.value = []byte(strconv.FormatInt(n+delta, 10))
+		s.data[args[1]] = e
+
+		return ":" + strconv.FormatInt(n+delta, 10) + "\r\n"
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.data[key]; ok {
+				delete(s.data, key)
+				n++
+			}
+		}
+
+		return ":" + strconv.Itoa(n) + "\r\n"
+	case "SCAN":
+		return s.scan(args)
+	}
+
+	return "-ERR unknown command '" + args[0] + "'\r\n"
+}
+
+// scan only supports patterns ending with the single wildcard RESP sends
+func (s *respServer) scan(args []string) string {
+	cursor, _ := strconv.Atoi(args[1])
+	prefix := strings.ReplaceAll(strings.TrimSuffix(args[3], "*"), `\`, "")
+
+	keys := []string{}
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	end := cursor + 2
+	next := strconv.Itoa(end)
+	if end >= len(keys) {
+		end = len(keys)
+		next = "0"
+	}
+
+	reply := "*2\r\n" + bulk(next) + "*" + strconv.Itoa(end-cursor) + "\r\n"
+	for _, key := range keys[cursor:end] {
+		reply += bulk(key)
+	}
+
+	return reply
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func newTestRESP(t *testing.T, address string, password string) *RESP {
+	t.Helper()
+
+	r, err := NewRESP(RESPOptions{
+		Address:     address,
+		Password:    password,
+		DB:          2,
+		Prefix:      "pokedex",
+		DialTimeout: time.Second,
+		Timeout:     time.Second,
+		PoolSize:    2,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = r.Close() })
+
+	return r
+}
+
+func TestNewRESP_Error(t *testing.T) {
+	t.Parallel()
+
+	valid := RESPOptions{Address: "localhost:6379", DialTimeout: time.Second, Timeout: time.Second, PoolSize: 1}
+
+	tests := map[string]struct {
+		opts func(o RESPOptions) RESPOptions
+		err  string
+	}{
+		"ReturnsErrorWhenAddressEmpty": {
+			opts: func(o RESPOptions) RESPOptions { o.Address = ""; return o },
+			err:  "address: invalid parameter",
+		},
+		"ReturnsErrorWhenDBNegative": {
+			opts: func(o RESPOptions) RESPOptions { o.DB = -1; return o },
+			err:  "db: invalid parameter",
+		},
+		"ReturnsErrorWhenTimeoutNotPositive": {
+			opts: func(o RESPOptions) RESPOptions { o.Timeout = 0; return o },
+			err:  "timeout: invalid parameter",
+		},
+		"ReturnsErrorWhenPoolSizeNotPositive": {
+			opts: func(o RESPOptions) RESPOptions { o.PoolSize = 0; return o },
+			err:  "pool size: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := NewRESP(tc.opts(valid))
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, r)
+		})
+	}
+}
+
+func TestRESP(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		run func(t *testing.T, r *RESP, server *respServer)
+	}
+
+	tests := map[string]testcase{
+		"SetsGetsAndDeletesPrefixedKeys": {
+			run: func(t *testing.T, r *RESP, server *respServer) {
+				set(t, r, "pokemon:v1:mewtwo", "150", time.Hour)
+
+				requireValue(t, r, "pokemon:v1:mewtwo", "150")
+				requireMissing(t, r, "pokemon:v1:mew")
+
+				server.mu.Lock()
+				require.Contains(t, server.data, "pokedex:pokemon:v1:mewtwo")
+				require.True(t, server.dbs["2"])
+				server.mu.Unlock()
+
+				n, err := r.Delete(context.Background(), "pokemon:v1:mewtwo", "pokemon:v1:mew")
+				require.NoError(t, err)
+				require.Equal(t, 1, n)
+				requireMissing(t, r, "pokemon:v1:mewtwo")
+			},
+		},
+		"ExpiresEntriesAfterTTL": {
+			run: func(t *testing.T, r *RESP, server *respServer) {
+				set(t, r, "pokemon:v1:mewtwo", "150", time.Millisecond)
+
+				require.Eventually(t, func() bool {
+					_, ok, err := r.Get(context.Background(), "pokemon:v1:mewtwo")
+
+					return err == nil && !ok
+				}, time.Second, time.Millisecond)
+			},
+		},
+		"IncrementsAndSetsExpiryOnCreation": {
+			run: func(t *testing.T, r *RESP, server *respServer) {
+				n, err := r.IncrBy(context.Background(), "quota:hour", 1, time.Minute)
+				require.NoError(t, err)
+				require.Equal(t, int64(1), n)
+
+				n, err = r.IncrBy(context.Background(), "quota:hour", 2, time.Minute)
+				require.NoError(t, err)
+				require.Equal(t, int64(3), n)
+
+				server.mu.Lock()
+				require.WithinDuration(t, time.Now().Add(time.Minute), server.data["pokedex:quota:hour"].expires, time.Second)
+				server.mu.Unlock()
+
+				requireValue(t, r, "quota:hour", "3")
+			},
+		},
+		"KeepsExpiryOfExistingCounter": {
+			run: func(t *testing.T, r *RESP, server *respServer) {
+				set(t, r, "quota:day", "5", time.Hour)
+
+				n, err := r.IncrBy(context.Background(), "quota:day", 1, time.Minute)
+				require.NoError(t, err)
+				require.Equal(t, int64(6), n)
+
+				server.mu.Lock()
+				require.WithinDuration(t, time.Now().Add(time.Hour), server.data["pokedex:quota:day"].expires, time.Second)
+				server.mu.Unlock()
+			},
+		},
+		"ReturnsErrorWhenCounterNotAnInteger": {
+			run: func(t *testing.T, r *RESP, server *respServer) {
+				set(t, r, "quota:day", "mewtwo", time.Hour)
+
+				_, err := r.IncrBy(context.Background(), "quota:day", 1, time.Minute)
+				require.EqualError(t, err, "INCRBY failed: ERR value is not an integer or out of range")
+
+				require.NoError(t, r.Ping(context.Background()))
+			},
+		},
+		"ListsKeysAcrossScanPages": {
+			run: func(t *testing.T, r *RESP, server *respServer) {
+				for _, name := range []string{"mew", "mewtwo", "ditto", "eevee", "zubat"} {
+					set(t, r, "pokemon:v1:"+name, name, time.Hour)
+				}
+				set(t, r, "translation:v1:yoda:mew", "translated", time.Hour)
+
+				requireKeys(t, r, "pokemon:v1:",
+					"pokemon:v1:ditto", "pokemon:v1:eevee", "pokemon:v1:mew", "pokemon:v1:mewtwo", "pokemon:v1:zubat")
+				requireKeys(t, r, "translation:", "translation:v1:yoda:mew")
+			},
+		},
+		"ReturnsErrorReplies": {
+			run: func(t *testing.T, r *RESP, server *respServer) {
+				_, err := r.do(context.Background(), "FLUSHALL")
+				require.EqualError(t, err, "FLUSHALL failed: ERR unknown command 'FLUSHALL'")
+
+				require.NoError(t, r.Ping(context.Background()))
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			server := newRESPServer(t, "s3cr3t")
+
+			tc.run(t, newTestRESP(t, server.addr(), "s3cr3t"), server)
+		})
+	}
+}
+
+func TestRESP_Error(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ReturnsErrorWhenPasswordWrongWithoutQuotingIt", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRESPServer(t, "s3cr3t")
+
+		err := newTestRESP(t, server.addr(), "wrong").Ping(context.Background())
+		require.EqualError(t, err, "unable to authenticate to cache")
+	})
+
+	t.Run("ReconnectsWhenPooledConnectionClosed", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRESPServer(t, "")
+		r := newTestRESP(t, server.addr(), "")
+		require.NoError(t, r.Set(context.Background(), "pokemon:v1:mewtwo", []byte("{}"), time.Minute))
+
+		server.dropConns()
+
+		value, ok, err := r.Get(context.Background(), "pokemon:v1:mewtwo")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, []byte("{}"), value)
+	})
+
+	t.Run("DoesNotRetryWhenConnectionResetAfterCommandSent", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRESPServer(t, "")
+		r := newTestRESP(t, server.addr(), "")
+		require.NoError(t, r.Ping(context.Background()))
+
+		server.mu.Lock()
+		server.resetAfter = "EXEC"
+		server.mu.Unlock()
+
+		_, err := r.IncrBy(context.Background(), "quota:hour", 1, time.Minute)
+		require.Error(t, err)
+
+		requireValue(t, r, "quota:hour", "1")
+	})
+
+	t.Run("ReturnsErrorWhenServerDown", func(t *testing.T) {
+		t.Parallel()
+
+		server := newRESPServer(t, "")
+		r := newTestRESP(t, server.addr(), "")
+		require.NoError(t, r.Ping(context.Background()))
+
+		require.NoError(t, server.lis.Close())
+		require.NoError(t, r.Close())
+
+		_, _, err := r.Get(context.Background(), "pokemon:v1:mewtwo")
+		require.ErrorContains(t, err, "unable to connect to cache")
+	})
+}
+
+func TestReadReply_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		reply string
+		err   string
+	}{
+		"ReturnsErrorWhenBulkStringTooLarge": {
+			reply: "$1099511627776\r\n",
+			err:   "bulk string of 1099511627776 bytes exceeds 67108864",
+		},
+		"ReturnsErrorWhenArrayTooLarge": {
+			reply: "*1099511627776\r\n",
+			err:   "array of 1099511627776 items exceeds 1048576",
+		},
+		"ReturnsErrorWhenMalformed": {
+			reply: "+OK\n",
+			err:   `malformed reply "+OK\n"`,
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := readReply(bufio.NewReader(strings.NewReader(tc.reply)))
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type (
+	// Backend stores serialised values by key, e.g. in-process or in a remote cache shared by the
+	// replicas of the service
+	Backend interface {
+		// Get returns the value of key, false when it is not cached
+		Get(ctx context.Context, key string) ([]byte, bool, error)
+		// Set caches value for key for ttl
+		Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+		// IncrBy adds delta to the integer value of key and returns the result, a missing key is
+		// created with ttl while an existing one keeps its expiry
+		IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+		// Delete removes keys and returns the number of entries removed
+		Delete(ctx context.Context, keys ...string) (int, error)
+		// Keys returns the cached keys starting with prefix
+		Keys(ctx context.Context, prefix string) ([]string, error)
+		// Ping checks the backend is reachable
+		Ping(ctx context.Context) error
+	}
+
+	// Store caches values of one kind in a Backend, serialised as JSON under versioned keys e.g.
+	// pokemon:v1:mewtwo. Bumping the version when the serialised type changes makes the replicas
+	// ignore the entries written by the previous one. Backend errors are logged and counted, a value
+	// that can not be read is a miss and one that can not be written is not cached
+	Store struct {
+		backend Backend
+		kind    string
+		prefix  string
+		hits    uint64
+		misses  uint64
+		errors  uint64
+	}
+
+	// evictionCounter is implemented by backends counting their evictions
+	evictionCounter interface {
+		Evictions() uint64
+	}
+)
+
+// NewStore creates a Store for values of kind, written with the given version of their serialisation
+func NewStore(backend Backend, kind string, version int) (*Store, error) {
+	if backend == nil {
+		return nil, errors.Wrap(ErrInvalidParam, "backend")
+	}
+
+	if kind == "" || strings.Contains(kind, ":") {
+		return nil, errors.Wrap(ErrInvalidParam, "kind")
+	}
+
+	if version < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "version")
+	}
+
+	return &Store{
+		backend: backend,
+		kind:    kind,
+		prefix:  fmt.Sprintf("%s:v%d:", kind, version),
+	}, nil
+}
+
+// Get decodes the value cached for key into value, counting a hit or a miss
+func (s *Store) Get(ctx context.Context, key string, value interface{}) bool {
+	ok := s.Peek(ctx, key, value)
+	if ok {
+		atomic.AddUint64(&s.hits, 1)
+	} else {
+		atomic.AddUint64(&s.misses, 1)
+	}
+
+	return ok
+}
+
+// Peek decodes the value cached for key into value without counting it
+func (s *Store) Peek(ctx context.Context, key string, value interface{}) bool {
+	data, ok, err := s.backend.Get(ctx, s.prefix+key)
+	if err != nil {
+		s.fail(err, "get", key)
+
+		return false
+	}
+
+	if !ok {
+		return false
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		s.fail(err, "decode", key)
+
+		return false
+	}
+
+	return true
+}
+
+// Set caches value for key for ttl
+func (s *Store) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		s.fail(err, "encode", key)
+
+		return
+	}
+
+	if err := s.backend.Set(ctx, s.prefix+key, data, ttl); err != nil {
+		s.fail(err, "set", key)
+	}
+}
+
+// Delete removes the entries whose key matches, it returns the number of entries removed
+func (s *Store) Delete(ctx context.Context, match func(key string) bool) int {
+	keys, err := s.keys(ctx)
+	if err != nil {
+		s.fail(err, "keys", "")
+
+		return 0
+	}
+
+	matched := []string{}
+	for _, key := range keys {
+		if match(key) {
+			matched = append(matched, s.prefix+key)
+		}
+	}
+
+	if len(matched) == 0 {
+		return 0
+	}
+
+	removed, err := s.backend.Delete(ctx, matched...)
+	if err != nil {
+		s.fail(err, "delete", "")
+	}
+
+	return removed
+}
+
+// Stats returns the number of cached entries and the usage counters of the store, evictions are
+// only known for the in-process backend
+func (s *Store) Stats(ctx context.Context) Stats {
+	stats := Stats{
+		Hits:   atomic.LoadUint64(&s.hits),
+		Misses: atomic.LoadUint64(&s.misses),
+	}
+
+	keys, err := s.keys(ctx)
+	if err != nil {
+		s.fail(err, "keys", "")
+	}
+
+	stats.Entries = len(keys)
+	stats.Errors = atomic.LoadUint64(&s.errors)
+
+	if ec, ok := s.backend.(evictionCounter); ok {
+		stats.Evictions = ec.Evictions()
+	}
+
+	return stats
+}
+
+// Ping checks the backend is reachable
+func (s *Store) Ping(ctx context.Context) error {
+	return s.backend.Ping(ctx)
+}
+
+// keys returns the keys of the store, without their prefix
+func (s *Store) keys(ctx context.Context) ([]string, error) {
+	keys, err := s.backend.Keys(ctx, s.prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, s.prefix)
+	}
+
+	return keys, nil
+}
+
+func (s *Store) fail(err error, op string, key string) {
+	atomic.AddUint64(&s.errors, 1)
+
+	log.Warn().Err(err).Str("cache", s.kind).Str("op", op).Str("key", key).Msg("cache operation failed")
+}
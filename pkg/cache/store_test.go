@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errBackendDown = errors.New("backend down")
+
+// switchBackend is a Memory that fails every call while down
+type switchBackend struct {
+	*Memory
+
+	mu   sync.Mutex
+	down bool
+}
+
+func newSwitchBackend(t *testing.T) *switchBackend {
+	t.Helper()
+
+	m, err := NewMemory(10)
+	require.NoError(t, err)
+
+	return &switchBackend{Memory: m}
+}
+
+func (b *switchBackend) setDown(down bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.down = down
+}
+
+func (b *switchBackend) err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.down {
+		return errBackendDown
+	}
+
+	return nil
+}
+
+func (b *switchBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if err := b.err(); err != nil {
+		return nil, false, err
+	}
+
+	return b.Memory.Get(ctx, key)
+}
+
+func (b *switchBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := b.err(); err != nil {
+		return err
+	}
+
+	return b.Memory.Set(ctx, key, value, ttl)
+}
+
+func (b *switchBackend) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if err := b.err(); err != nil {
+		return 0, err
+	}
+
+	return b.Memory.IncrBy(ctx, key, delta, ttl)
+}
+
+func (b *switchBackend) Delete(ctx context.Context, keys ...string) (int, error) {
+	if err := b.err(); err != nil {
+		return 0, err
+	}
+
+	return b.Memory.Delete(ctx, keys...)
+}
+
+func (b *switchBackend) Keys(ctx context.Context, prefix string) ([]string, error) {
+	if err := b.err(); err != nil {
+		return nil, err
+	}
+
+	return b.Memory.Keys(ctx, prefix)
+}
+
+func (b *switchBackend) Ping(ctx context.Context) error {
+	return b.err()
+}
+
+func TestNewStore_Error(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewMemory(1)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		backend Backend
+		kind    string
+		version int
+		err     string
+	}{
+		"ReturnsErrorWhenBackendNil": {
+			kind:    "pokemon",
+			version: 1,
+			err:     "backend: invalid parameter",
+		},
+		"ReturnsErrorWhenKindHasSeparator": {
+			backend: m,
+			kind:    "pokemon:species",
+			version: 1,
+			err:     "kind: invalid parameter",
+		},
+		"ReturnsErrorWhenVersionNotPositive": {
+			backend: m,
+			kind:    "pokemon",
+			err:     "version: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := NewStore(tc.backend, tc.kind, tc.version)
+			require.EqualError(t, err, tc.err)
+			require.Nil(t, s)
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	type pokemon struct {
+		Name   string
+		Number int
+	}
+
+	type testcase struct {
+		run func(t *testing.T, s *Store, backend *switchBackend)
+	}
+
+	tests := map[string]testcase{
+		"SerialisesValuesUnderVersionedKeysAndCountsHitsAndMisses": {
+			run: func(t *testing.T, s *Store, backend *switchBackend) {
+				s.Set(context.Background(), "mewtwo", pokemon{Name: "mewtwo", Number: 150}, time.Hour)
+				requireValue(t, backend, "pokemon:v2:mewtwo", `{"Name":"mewtwo","Number":150}`)
+
+				var pok pokemon
+				require.True(t, s.Get(context.Background(), "mewtwo", &pok))
+				require.Equal(t, pokemon{Name: "mewtwo", Number: 150}, pok)
+				require.False(t, s.Get(context.Background(), "mew", &pok))
+				require.True(t, s.Peek(context.Background(), "mewtwo", &pok))
+
+				require.Equal(t, Stats{Entries: 1, Hits: 1, Misses: 1}, s.Stats(context.Background()))
+			},
+		},
+		"IgnoresEntriesOfOtherVersions": {
+			run: func(t *testing.T, s *Store, backend *switchBackend) {
+				set(t, backend, "pokemon:v1:mewtwo", `{"name":"mewtwo"}`, time.Hour)
+
+				var pok pokemon
+				require.False(t, s.Get(context.Background(), "mewtwo", &pok))
+				require.Equal(t, 0, s.Stats(context.Background()).Entries)
+			},
+		},
+		"TreatsUnreadableEntriesAsMisses": {
+			run: func(t *testing.T, s *Store, backend *switchBackend) {
+				set(t, backend, "pokemon:v2:mewtwo", `not json`, time.Hour)
+
+				var pok pokemon
+				require.False(t, s.Get(context.Background(), "mewtwo", &pok))
+				require.Equal(t, Stats{Entries: 1, Misses: 1, Errors: 1}, s.Stats(context.Background()))
+			},
+		},
+		"CountsBackendErrorsAsMisses": {
+			run: func(t *testing.T, s *Store, backend *switchBackend) {
+				backend.setDown(true)
+
+				s.Set(context.Background(), "mewtwo", pokemon{Name: "mewtwo"}, time.Hour)
+
+				var pok pokemon
+				require.False(t, s.Get(context.Background(), "mewtwo", &pok))
+				require.Equal(t, 0, s.Delete(context.Background(), func(key string) bool { return true }))
+				require.ErrorIs(t, s.Ping(context.Background()), errBackendDown)
+
+				require.Equal(t, Stats{Misses: 1, Errors: 4}, s.Stats(context.Background()))
+			},
+		},
+		"DeletesMatchingKeys": {
+			run: func(t *testing.T, s *Store, backend *switchBackend) {
+				for _, name := range []string{"mew", "mewtwo", "ditto"} {
+					s.Set(context.Background(), name, pokemon{Name: name}, time.Hour)
+				}
+				set(t, backend, "translation:v1:mew", `"translated"`, time.Hour)
+
+				n := s.Delete(context.Background(), func(key string) bool { return key != "ditto" })
+				require.Equal(t, 2, n)
+
+				requireKeys(t, backend, "", "pokemon:v2:ditto", "translation:v1:mew")
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			backend := newSwitchBackend(t)
+
+			s, err := NewStore(backend, "pokemon", 2)
+			require.NoError(t, err)
+
+			tc.run(t, s, backend)
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Package client is a Go client for the pokedex REST API. Errors returned by the service are
+// decoded into *ResponseError, which matches the sentinel errors of this package with errors.Is
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"pokedex/pkg/adapter/pokeapi"
+	httpclient "pokedex/pkg/http/client"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTimeout  = 10 * time.Second
+	defaultAttempts = 3
+	defaultBackoff  = 100 * time.Millisecond
+
+	maxBodySize = 4 << 20
+)
+
+var ErrInvalidParam = errors.New("invalid parameter")
+
+type (
+	// Client calls the pokedex REST API. Requests failing with a transport error or a 502, 503 or 504
+	// response are retried
+	Client struct {
+		baseURL    string
+		httpClient *http.Client
+		attempts   int
+		backoff    time.Duration
+		middleware []httpclient.Middleware
+
+		doer httpclient.Doer
+	}
+
+	// Option configures a Client
+	Option func(c *Client)
+
+	// TranslatedPokemon is a Pokemon with a translated description and details about the translation
+	TranslatedPokemon struct {
+		pokeapi.Pokemon
+		Translation Translation `json:"translation"`
+	}
+
+	// Translation describes how a description was translated, FallbackReason is set when the
+	// original description was returned
+	Translation struct {
+		Style          string `json:"style"`
+		Succeeded      bool   `json:"succeeded"`
+		FallbackReason string `json:"fallback_reason,omitempty"`
+		Cached         bool   `json:"cached"`
+	}
+
+	// Query filters, sorts and pages the Pokemons of the local store of the service. Min and Max
+	// are keyed by stat: hp, attack, defense, special_attack, special_defense or speed. Sort takes
+	// name, number or a stat, prefixed with - to sort in descending order. Fields selects the
+	// snake case fields returned, e.g. is_legendary, every field is returned when it is empty
+	Query struct {
+		Habitat    string
+		Type       string
+		Generation string
+		Legendary  *bool
+		Mythical   *bool
+		Min        map[string]int
+		Max        map[string]int
+		Sort       []string
+		Fields     []string
+		Limit      int
+		Offset     int
+	}
+
+	// Page is a page of the Pokemons matching a query, Count is the number of matches across all
+	// pages. Fields left out by Query.Fields are zero
+	Page struct {
+		Count   int               `json:"count"`
+		Limit   int               `json:"limit"`
+		Offset  int               `json:"offset"`
+		Results []pokeapi.Pokemon `json:"results"`
+	}
+)
+
+// WithHTTPClient sets the underlying http client, by default one with a 10s timeout is used
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetry sets the number of attempts of a request and the backoff before the first retry,
+// doubled after every attempt. An attempts of 1 disables retries
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.attempts = attempts
+		c.backoff = backoff
+	}
+}
+
+// WithMiddleware wraps every request attempt with the given middleware, e.g. the Logging middleware
+// of pkg/http/client, the first one given is the outermost
+func WithMiddleware(middleware ...httpclient.Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// New creates a new Client for the service running at baseURL, e.g. http://localhost:5000
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, errors.Wrap(ErrInvalidParam, "url")
+	}
+
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		attempts:   defaultAttempts,
+		backoff:    defaultBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.attempts < 1 {
+		return nil, errors.Wrap(ErrInvalidParam, "attempts")
+	}
+
+	var doer httpclient.Doer = c.httpClient
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		doer = c.middleware[i](doer)
+	}
+
+	if c.attempts > 1 {
+		doer = httpclient.Retry(c.attempts, c.backoff)(doer)
+	}
+
+	c.doer = doer
+
+	return c, nil
+}
+
+// GetPokemon fetches a Pokemon by its PokeAPI name
+func (c *Client) GetPokemon(ctx context.Context, name string) (*pokeapi.Pokemon, error) {
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+
+	var pok pokeapi.Pokemon
+	if err := c.get(ctx, "/v1/pokemon/"+url.PathEscape(name), nil, &pok); err != nil {
+		return nil, errors.Wrapf(err, "unable to get pokemon: %s", name)
+	}
+
+	return &pok, nil
+}
+
+// GetTranslatedPokemon fetches a Pokemon by its PokeAPI name with a translated description
+func (c *Client) GetTranslatedPokemon(ctx context.Context, name string) (*TranslatedPokemon, error) {
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+
+	var pok TranslatedPokemon
+	if err := c.get(ctx, "/v1/pokemon/translated/"+url.PathEscape(name), nil, &pok); err != nil {
+		return nil, errors.Wrapf(err, "unable to get translated pokemon: %s", name)
+	}
+
+	return &pok, nil
+}
+
+// QueryPokemon returns a page of the Pokemons matching q, it needs the local store to be enabled
+// on the service
+func (c *Client) QueryPokemon(ctx context.Context, q Query) (*Page, error) {
+	var page Page
+	if err := c.get(ctx, "/v1/pokemon", q.values(), &page); err != nil {
+		return nil, errors.Wrap(err, "unable to query pokemons")
+	}
+
+	return &page, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return errors.Wrap(err, "error creating http request")
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.doer.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error sending request")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return responseError(res)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxBodySize)).Decode(out); err != nil {
+		return errors.Wrap(err, "error unmarshaling response")
+	}
+
+	return nil
+}
+
+// values encodes the query parameters, zero values are left out for the service defaults to apply
+func (q Query) values() url.Values {
+	values := url.Values{}
+
+	set := func(name string, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+
+	set("habitat", q.Habitat)
+	set("type", q.Type)
+	set("generation", q.Generation)
+	set("sort", strings.Join(q.Sort, ","))
+	set("fields", strings.Join(q.Fields, ","))
+
+	if q.Legendary != nil {
+		values.Set("legendary", strconv.FormatBool(*q.Legendary))
+	}
+
+	if q.Mythical != nil {
+		values.Set("mythical", strconv.FormatBool(*q.Mythical))
+	}
+
+	for stat, value := range q.Min {
+		values.Set("min_"+stat, strconv.Itoa(value))
+	}
+
+	for stat, value := range q.Max {
+		values.Set("max_"+stat, strconv.Itoa(value))
+	}
+
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+
+	if q.Offset > 0 {
+		values.Set("offset", strconv.Itoa(q.Offset))
+	}
+
+	return values
+}
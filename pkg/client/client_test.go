@@ -0,0 +1,367 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"pokedex/internal/handler"
+	"pokedex/internal/handler/mocks"
+	"pokedex/internal/router"
+	"pokedex/internal/service/pokemon"
+	"pokedex/pkg/adapter/pokeapi"
+	"pokedex/pkg/adapter/upstream"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+var mewtwo = &pokeapi.Pokemon{
+	Name:        "mewtwo",
+	Number:      150,
+	Description: "It was created by a scientist.",
+	Habitat:     "rare",
+	Generation:  "generation-i",
+	Types:       []string{"psychic"},
+	Stats:       map[string]int{"hp": 106, "attack": 110},
+	IsLegendary: true,
+}
+
+type mocksSet struct {
+	fetcher    *mocks.MockPokemonFetcher
+	translator *mocks.MockDescriptionTranslator
+	repo       *mocks.MockPokemonRepository
+}
+
+// newServer serves the real router with mocked dependencies
+func newServer(t *testing.T, ctrl *gomock.Controller) (*httptest.Server, mocksSet) {
+	t.Helper()
+
+	m := mocksSet{
+		fetcher:    mocks.NewMockPokemonFetcher(ctrl),
+		translator: mocks.NewMockDescriptionTranslator(ctrl),
+		repo:       mocks.NewMockPokemonRepository(ctrl),
+	}
+
+	srv := httptest.NewServer(router.New(router.Handlers{
+		GetPokemonByName:           handler.GetPokemonByName(m.fetcher),
+		GetPokemonByNameTranslated: handler.GetPokemonByNameTranslated(m.fetcher, m.translator),
+		QueryPokemon:               handler.QueryPokemon(m.repo),
+		HealthCheck:                handler.HealthCheck,
+	}, router.Options{
+		PokemonMaxAge:           func() time.Duration { return time.Hour },
+		PokemonTranslatedMaxAge: func() time.Duration { return time.Hour },
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, m
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	legendary := true
+
+	type testcase struct {
+		expect   func(m mocksSet)
+		call     func(ctx context.Context, c *Client) (interface{}, error)
+		expected interface{}
+		err      error
+	}
+
+	tests := map[string]testcase{
+		"GetPokemon": {
+			expect: func(m mocksSet) {
+				m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+			},
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetPokemon(ctx, "mewtwo")
+			},
+			expected: mewtwo,
+		},
+		"GetPokemonNotFound": {
+			expect: func(m mocksSet) {
+				m.fetcher.EXPECT().FetchByName(gomock.Any(), "missingno").Return(nil, nil)
+			},
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetPokemon(ctx, "missingno")
+			},
+			err: ErrPokemonNotFound,
+		},
+		"GetPokemonInvalidName": {
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetPokemon(ctx, "Mew Two")
+			},
+			err: ErrInvalidName,
+		},
+		"GetPokemonEmptyName": {
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetPokemon(ctx, "")
+			},
+			err: ErrInvalidName,
+		},
+		"GetPokemonRetriesUnavailableUpstream": {
+			expect: func(m mocksSet) {
+				gomock.InOrder(
+					m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrUnavailable),
+					m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrTimeout),
+					m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil),
+				)
+			},
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetPokemon(ctx, "mewtwo")
+			},
+			expected: mewtwo,
+		},
+		"GetPokemonUnavailableAfterRetries": {
+			expect: func(m mocksSet) {
+				m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, upstream.ErrUnavailable).Times(3)
+			},
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetPokemon(ctx, "mewtwo")
+			},
+			err: ErrUpstreamUnavailable,
+		},
+		"GetPokemonRateLimitedIsNotRetried": {
+			expect: func(m mocksSet) {
+				m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, &upstream.ResponseError{
+					Kind:       upstream.ErrRateLimited,
+					StatusCode: http.StatusTooManyRequests,
+					RetryAfter: time.Minute,
+				})
+			},
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetPokemon(ctx, "mewtwo")
+			},
+			err: ErrRateLimited,
+		},
+		"GetTranslatedPokemon": {
+			expect: func(m mocksSet) {
+				m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+				m.translator.EXPECT().TranslateDescription(gomock.Any(), mewtwo).Return(pokemon.TranslationResult{
+					Description: "Created by a scientist, it was.",
+					Translation: pokemon.Translation{Style: pokemon.StyleYoda, Succeeded: true},
+				})
+			},
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetTranslatedPokemon(ctx, "mewtwo")
+			},
+			expected: &TranslatedPokemon{
+				Pokemon: func() pokeapi.Pokemon {
+					pok := *mewtwo
+					pok.Description = "Created by a scientist, it was."
+
+					return pok
+				}(),
+				Translation: Translation{Style: "yoda", Succeeded: true},
+			},
+		},
+		"GetTranslatedPokemonNotFound": {
+			expect: func(m mocksSet) {
+				m.fetcher.EXPECT().FetchByName(gomock.Any(), "missingno").Return(nil, nil)
+			},
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.GetTranslatedPokemon(ctx, "missingno")
+			},
+			err: ErrPokemonNotFound,
+		},
+		"QueryPokemon": {
+			expect: func(m mocksSet) {
+				m.repo.EXPECT().Query(gomock.Any(), pokeapi.Query{
+					Habitat:   "rare",
+					Legendary: &legendary,
+					Stats:     []pokeapi.StatRange{{Stat: "hp", Min: 100, Max: math.MaxInt32}},
+				}).Return([]*pokeapi.Pokemon{mewtwo, {Name: "lugia", Stats: map[string]int{"hp": 106}}}, nil)
+			},
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.QueryPokemon(ctx, Query{
+					Habitat:   "rare",
+					Legendary: &legendary,
+					Min:       map[string]int{"hp": 100},
+					Sort:      []string{"-hp", "name"},
+					Fields:    []string{"name", "stats"},
+					Limit:     1,
+					Offset:    1,
+				})
+			},
+			expected: &Page{
+				Count:   2,
+				Limit:   1,
+				Offset:  1,
+				Results: []pokeapi.Pokemon{{Name: "mewtwo", Stats: mewtwo.Stats}},
+			},
+		},
+		"QueryPokemonInvalid": {
+			call: func(ctx context.Context, c *Client) (interface{}, error) {
+				return c.QueryPokemon(ctx, Query{Min: map[string]int{"luck": 1}})
+			},
+			err: ErrInvalidQuery,
+		},
+	}
+
+	for name, testCase := range tests {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			srv, m := newServer(t, ctrl)
+			if tc.expect != nil {
+				tc.expect(m)
+			}
+
+			c, err := New(srv.URL, WithHTTPClient(srv.Client()), WithRetry(3, time.Millisecond))
+			require.NoError(t, err)
+
+			res, err := tc.call(context.Background(), c)
+			if tc.err != nil {
+				require.Error(t, err)
+				require.True(t, errors.Is(err, tc.err), err.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestClient_ResponseError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv, m := newServer(t, ctrl)
+
+	m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(nil, &upstream.ResponseError{
+		Kind:       upstream.ErrRateLimited,
+		StatusCode: http.StatusTooManyRequests,
+		RetryAfter: time.Minute,
+	})
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	_, err = c.GetPokemon(context.Background(), "mewtwo")
+
+	var respErr *ResponseError
+	require.True(t, errors.As(err, &respErr))
+	require.Equal(t, http.StatusTooManyRequests, respErr.StatusCode)
+	require.Equal(t, "rate_limited", respErr.Code)
+	require.Equal(t, "/v1/pokemon/mewtwo", respErr.Instance)
+	require.Equal(t, time.Minute, respErr.RetryAfter)
+	require.True(t, respErr.Temporary())
+	require.False(t, errors.Is(err, ErrUpstreamUnavailable))
+}
+
+func TestClient_NonProblemResponse(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("<html>bad gateway</html>"))
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL, WithRetry(1, 0))
+	require.NoError(t, err)
+
+	_, err = c.GetPokemon(context.Background(), "mewtwo")
+
+	var respErr *ResponseError
+	require.True(t, errors.As(err, &respErr))
+	require.Equal(t, http.StatusBadGateway, respErr.StatusCode)
+	require.Equal(t, "Bad Gateway", respErr.Title)
+	require.Empty(t, respErr.Code)
+	require.Equal(t, "<html>bad gateway</html>", respErr.Body)
+	require.False(t, errors.Is(err, ErrUpstreamBadResponse))
+}
+
+func TestClient_HTTPClient(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv, m := newServer(t, ctrl)
+
+	m.fetcher.EXPECT().FetchByName(gomock.Any(), "mewtwo").Return(mewtwo, nil)
+
+	var requests int32
+
+	transport := srv.Client().Transport
+	httpClient := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+			require.Equal(t, "application/json", req.Header.Get("Accept"))
+
+			return transport.RoundTrip(req)
+		}),
+	}
+
+	c, err := New(srv.URL+"/", WithHTTPClient(httpClient))
+	require.NoError(t, err)
+
+	pok, err := c.GetPokemon(context.Background(), "mewtwo")
+	require.NoError(t, err)
+	require.Equal(t, mewtwo, pok)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestClient_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv, _ := newServer(t, ctrl)
+
+	c, err := New(srv.URL)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = c.GetPokemon(ctx, "mewtwo")
+	require.True(t, errors.Is(err, context.Canceled), err.Error())
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		url  string
+		opts []Option
+	}{
+		"RelativeURL":  {url: "/v1"},
+		"NoScheme":     {url: "localhost:5000"},
+		"ZeroAttempts": {url: "http://localhost:5000", opts: []Option{WithRetry(0, 0)}},
+	}
+
+	for name, testCase := range tests {
+		tc := testCase
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(tc.url, tc.opts...)
+			require.True(t, errors.Is(err, ErrInvalidParam))
+		})
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
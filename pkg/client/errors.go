@@ -0,0 +1,171 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"pokedex/pkg/adapter/upstream"
+	"strings"
+	"time"
+)
+
+const bodySnippetSize = 512
+
+// Sentinel errors matching the code of the problem returned by the service
+const (
+	ErrPokemonNotFound Error = iota + 1
+	ErrInvalidName
+	ErrInvalidQuery
+	ErrRateLimited
+	ErrUpstreamUnavailable
+	ErrUpstreamTimeout
+	ErrUpstreamBadResponse
+	ErrTranslationFailed
+	ErrNotAcceptable
+	ErrInternal
+)
+
+// Error is a sentinel error, a *ResponseError matches it with errors.Is when the service returned
+// its code
+type Error uint
+
+func (e Error) Error() string {
+	switch e {
+	case ErrPokemonNotFound:
+		return "pokemon not found"
+	case ErrInvalidName:
+		return "invalid pokemon name"
+	case ErrInvalidQuery:
+		return "invalid query"
+	case ErrRateLimited:
+		return "rate limit exceeded"
+	case ErrUpstreamUnavailable:
+		return "upstream service unavailable"
+	case ErrUpstreamTimeout:
+		return "upstream service timed out"
+	case ErrUpstreamBadResponse:
+		return "upstream service returned an invalid response"
+	case ErrTranslationFailed:
+		return "translation failed"
+	case ErrNotAcceptable:
+		return "not acceptable"
+	case ErrInternal:
+		return "internal error"
+	}
+
+	return "unknown error"
+}
+
+// Code returns the problem code the service uses for the error
+func (e Error) Code() string {
+	switch e {
+	case ErrPokemonNotFound:
+		return "pokemon_not_found"
+	case ErrInvalidName:
+		return "invalid_name"
+	case ErrInvalidQuery:
+		return "invalid_query"
+	case ErrRateLimited:
+		return "rate_limited"
+	case ErrUpstreamUnavailable:
+		return "upstream_unavailable"
+	case ErrUpstreamTimeout:
+		return "upstream_timeout"
+	case ErrUpstreamBadResponse:
+		return "upstream_bad_response"
+	case ErrTranslationFailed:
+		return "translation_failed"
+	case ErrNotAcceptable:
+		return "not_acceptable"
+	case ErrInternal:
+		return "internal_error"
+	}
+
+	return "unknown_error"
+}
+
+// Problem is the RFC 7807 problem document the service returns with errors
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// ResponseError is returned for non 2xx responses. Problem is decoded from the body when the
+// service returned a problem document, otherwise only its title and status are set and Body holds
+// the beginning of the body
+type ResponseError struct {
+	Problem
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *ResponseError) Error() string {
+	msg := fmt.Sprintf("pokedex: %s (status %d)", e.Title, e.StatusCode)
+
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Code)
+	}
+
+	if e.Detail != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Detail)
+	}
+
+	return msg
+}
+
+// Is reports whether the service returned the code of the given sentinel error
+func (e *ResponseError) Is(target error) bool {
+	sentinel, ok := target.(Error)
+
+	return ok && e.Code != "" && sentinel.Code() == e.Code
+}
+
+// Temporary reports whether the request may succeed when retried later
+func (e *ResponseError) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// responseError builds the error of a non 2xx response
+func responseError(res *http.Response) error {
+	e := &ResponseError{
+		Problem: Problem{
+			Title:  http.StatusText(res.StatusCode),
+			Status: res.StatusCode,
+		},
+		StatusCode: res.StatusCode,
+		RetryAfter: upstream.RetryAfter(res.Header.Get("Retry-After")),
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxBodySize))
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	if mediaType == "application/problem+json" {
+		var p Problem
+		if err := json.Unmarshal(body, &p); err == nil && p.Code != "" {
+			e.Problem = p
+
+			return e
+		}
+	}
+
+	if len(body) > bodySnippetSize {
+		body = body[:bodySnippetSize]
+	}
+
+	e.Body = strings.TrimSpace(string(body))
+
+	return e
+}
@@ -0,0 +1,226 @@
+// Package health runs the dependency checks behind the readiness probe
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFail     = "fail"
+)
+
+var ErrInvalidParam = errors.New("invalid parameter")
+
+type (
+	// Check reports the state of a dependency, an error fails it. Details are included in verbose
+	// reports and may be nil
+	Check func(ctx context.Context) (details interface{}, err error)
+
+	// Options configures a registered check
+	Options struct {
+		// Timeout bounds a single run of the check
+		Timeout time.Duration
+		// TTL is how long a result is reused before the check runs again
+		TTL time.Duration
+		// Optional checks degrade the service without making it unready when they fail
+		Optional bool
+	}
+
+	// Checker runs registered checks concurrently, it is safe for concurrent use
+	Checker struct {
+		mu     sync.RWMutex
+		checks map[string]*check
+		now    func() time.Time
+	}
+
+	// Result is the outcome of a check, Cached is set when it was reused from an earlier run
+	Result struct {
+		Status     string      `json:"status"`
+		Optional   bool        `json:"optional"`
+		Error      string      `json:"error,omitempty"`
+		Details    interface{} `json:"details,omitempty"`
+		DurationMs int64       `json:"duration_ms"`
+		CheckedAt  time.Time   `json:"checked_at"`
+		Cached     bool        `json:"cached"`
+	}
+
+	// Report is the outcome of all checks. Its status fails when a required check fails and is
+	// degraded when only optional ones do
+	Report struct {
+		Status string            `json:"status"`
+		Checks map[string]Result `json:"checks"`
+	}
+
+	check struct {
+		fn   Check
+		opts Options
+
+		mu   sync.Mutex
+		last *Result
+	}
+)
+
+// NewChecker creates a Checker without checks
+func NewChecker() *Checker {
+	return &Checker{
+		checks: map[string]*check{},
+		now:    time.Now,
+	}
+}
+
+// Register adds a check under a unique name
+func (c *Checker) Register(name string, fn Check, opts Options) error {
+	if name == "" {
+		return errors.Wrap(ErrInvalidParam, "name")
+	}
+
+	if fn == nil {
+		return errors.Wrap(ErrInvalidParam, "check")
+	}
+
+	if opts.Timeout <= 0 {
+		return errors.Wrap(ErrInvalidParam, "timeout")
+	}
+
+	if opts.TTL < 0 {
+		return errors.Wrap(ErrInvalidParam, "ttl")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.checks[name]; ok {
+		return errors.Wrapf(ErrInvalidParam, "duplicate check %s", name)
+	}
+
+	c.checks[name] = &check{fn: fn, opts: opts}
+
+	return nil
+}
+
+// Names returns the names of the registered checks, sorted
+func (c *Checker) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Run runs every check whose last result expired, concurrently, and returns the report
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]*check, len(c.checks))
+	for name, chk := range c.checks {
+		checks[name] = chk
+	}
+	c.mu.RUnlock()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		report = Report{Status: StatusOK, Checks: map[string]Result{}}
+	)
+
+	for name, chk := range checks {
+		wg.Add(1)
+
+		go func(name string, chk *check) {
+			defer wg.Done()
+
+			res := chk.result(ctx, c.now)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[name] = res
+
+			switch {
+			case res.Status == StatusOK:
+			case !res.Optional:
+				report.Status = StatusFail
+			case report.Status == StatusOK:
+				report.Status = StatusDegraded
+			}
+		}(name, chk)
+	}
+
+	wg.Wait()
+
+	return report
+}
+
+// result returns the last result while it is fresh and runs the check otherwise, concurrent
+// callers wait for a single run
+func (chk *check) result(ctx context.Context, now func() time.Time) Result {
+	chk.mu.Lock()
+	defer chk.mu.Unlock()
+
+	if chk.last != nil && now().Sub(chk.last.CheckedAt) < chk.opts.TTL {
+		res := *chk.last
+		res.Cached = true
+
+		return res
+	}
+
+	start := now()
+	details, err := chk.run(ctx)
+
+	res := Result{
+		Status:     StatusOK,
+		Optional:   chk.opts.Optional,
+		Details:    details,
+		DurationMs: now().Sub(start).Milliseconds(),
+		CheckedAt:  start,
+	}
+
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+
+	// a run cut short by the caller says nothing about the dependency
+	if ctx.Err() == nil {
+		chk.last = &res
+	}
+
+	return res
+}
+
+// run runs the check within its timeout, a check ignoring its context is abandoned on timeout
+func (chk *check) run(ctx context.Context) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, chk.opts.Timeout)
+	defer cancel()
+
+	type outcome struct {
+		details interface{}
+		err     error
+	}
+
+	done := make(chan outcome, 1)
+
+	go func() {
+		details, err := chk.fn(ctx)
+		done <- outcome{details: details, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.details, o.err
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "check timed out after %s", chk.opts.Timeout)
+	}
+}
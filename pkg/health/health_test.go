@@ -0,0 +1,209 @@
+package health
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func ok(ctx context.Context) (interface{}, error) {
+	return nil, nil
+}
+
+func fail(ctx context.Context) (interface{}, error) {
+	return nil, errors.New("unreachable")
+}
+
+func TestChecker_Register_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name string
+		fn   Check
+		opts Options
+		err  string
+	}{
+		"ReturnsErrorWhenNameEmpty": {
+			fn:   ok,
+			opts: Options{Timeout: time.Second},
+			err:  "name: invalid parameter",
+		},
+		"ReturnsErrorWhenCheckNil": {
+			name: "pokeapi",
+			opts: Options{Timeout: time.Second},
+			err:  "check: invalid parameter",
+		},
+		"ReturnsErrorWhenTimeoutNotPositive": {
+			name: "pokeapi",
+			fn:   ok,
+			err:  "timeout: invalid parameter",
+		},
+		"ReturnsErrorWhenTTLNegative": {
+			name: "pokeapi",
+			fn:   ok,
+			opts: Options{Timeout: time.Second, TTL: -time.Second},
+			err:  "ttl: invalid parameter",
+		},
+		"ReturnsErrorWhenDuplicate": {
+			name: "cache",
+			fn:   ok,
+			opts: Options{Timeout: time.Second},
+			err:  "duplicate check cache: invalid parameter",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewChecker()
+			require.NoError(t, c.Register("cache", ok, Options{Timeout: time.Second}))
+
+			require.EqualError(t, c.Register(tc.name, tc.fn, tc.opts), tc.err)
+			require.Equal(t, []string{"cache"}, c.Names())
+		})
+	}
+}
+
+func TestChecker_Run(t *testing.T) {
+	t.Parallel()
+
+	type check struct {
+		fn   Check
+		opts Options
+	}
+
+	type testcase struct {
+		checks map[string]check
+		check  func(t *testing.T, report Report)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsOKWhenAllChecksPass": {
+			checks: map[string]check{
+				"pokeapi": {fn: ok},
+				"cache": {fn: func(ctx context.Context) (interface{}, error) {
+					return map[string]int{"entries": 1}, nil
+				}},
+			},
+			check: func(t *testing.T, report Report) {
+				require.Equal(t, StatusOK, report.Status)
+				require.Equal(t, StatusOK, report.Checks["pokeapi"].Status)
+				require.Equal(t, map[string]int{"entries": 1}, report.Checks["cache"].Details)
+			},
+		},
+		"ReturnsOKWithoutChecks": {
+			check: func(t *testing.T, report Report) {
+				require.Equal(t, Report{Status: StatusOK, Checks: map[string]Result{}}, report)
+			},
+		},
+		"ReturnsFailWhenRequiredCheckFails": {
+			checks: map[string]check{
+				"pokeapi":         {fn: fail},
+				"funtranslations": {fn: fail, opts: Options{Optional: true}},
+			},
+			check: func(t *testing.T, report Report) {
+				require.Equal(t, StatusFail, report.Status)
+				require.Equal(t, "unreachable", report.Checks["pokeapi"].Error)
+				require.True(t, report.Checks["funtranslations"].Optional)
+			},
+		},
+		"ReturnsDegradedWhenOnlyOptionalChecksFail": {
+			checks: map[string]check{
+				"pokeapi":         {fn: ok},
+				"funtranslations": {fn: fail, opts: Options{Optional: true}},
+			},
+			check: func(t *testing.T, report Report) {
+				require.Equal(t, StatusDegraded, report.Status)
+				require.Equal(t, StatusFail, report.Checks["funtranslations"].Status)
+			},
+		},
+		"FailsCheckOnTimeout": {
+			checks: map[string]check{
+				"pokeapi": {fn: func(ctx context.Context) (interface{}, error) {
+					time.Sleep(time.Second)
+
+					return nil, nil
+				}, opts: Options{Timeout: 10 * time.Millisecond}},
+			},
+			check: func(t *testing.T, report Report) {
+				require.Equal(t, StatusFail, report.Status)
+				require.Equal(t, "check timed out after 10ms: context deadline exceeded", report.Checks["pokeapi"].Error)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			c := NewChecker()
+			for name, chk := range tc.checks {
+				opts := chk.opts
+				if opts.Timeout == 0 {
+					opts.Timeout = time.Second
+				}
+
+				require.NoError(t, c.Register(name, chk.fn, opts))
+			}
+
+			tc.check(t, c.Run(context.Background()))
+		})
+	}
+}
+
+func TestChecker_Run_CachesResults(t *testing.T) {
+	t.Parallel()
+
+	var runs int32
+
+	c := NewChecker()
+	clock := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return clock }
+
+	err := c.Register("pokeapi", func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&runs, 1)
+
+		return nil, nil
+	}, Options{Timeout: time.Second, TTL: 10 * time.Second})
+	require.NoError(t, err)
+
+	require.False(t, c.Run(context.Background()).Checks["pokeapi"].Cached)
+
+	clock = clock.Add(5 * time.Second)
+	res := c.Run(context.Background()).Checks["pokeapi"]
+	require.True(t, res.Cached)
+	require.Equal(t, time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC), res.CheckedAt)
+
+	clock = clock.Add(5 * time.Second)
+	require.False(t, c.Run(context.Background()).Checks["pokeapi"].Cached)
+
+	require.Equal(t, int32(2), atomic.LoadInt32(&runs))
+}
+
+func TestChecker_Run_DoesNotCacheCanceledRuns(t *testing.T) {
+	t.Parallel()
+
+	c := NewChecker()
+	err := c.Register("pokeapi", func(ctx context.Context) (interface{}, error) {
+		return nil, ctx.Err()
+	}, Options{Timeout: time.Second, TTL: time.Hour})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.Equal(t, StatusFail, c.Run(ctx).Status)
+
+	res := c.Run(context.Background()).Checks["pokeapi"]
+	require.Equal(t, StatusOK, res.Status)
+	require.False(t, res.Cached)
+}
@@ -0,0 +1,409 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"pokedex/pkg/adapter/upstream"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultTimeout     = 10 * time.Second
+	defaultMaxBodySize = 1 << 20
+	maxRedirects       = 10
+
+	bodySnippetSize = 512
+)
+
+var ErrInvalidParam = errors.New("invalid parameter")
+
+type (
+	// Doer sends a http request
+	Doer interface {
+		Do(req *http.Request) (*http.Response, error)
+	}
+
+	// DoerFunc allows regular functions to act as Doers
+	DoerFunc func(req *http.Request) (*http.Response, error)
+
+	// Middleware wraps a Doer adding behaviour such as authentication, retries or logging
+	Middleware func(next Doer) Doer
+
+	// Client is a http client for JSON APIs. Requests go through the configured middleware, non 2xx
+	// responses and transport failures are returned as *upstream.ResponseError
+	Client struct {
+		service    string
+		middleware []Middleware
+
+		mu          sync.RWMutex
+		httpClient  *http.Client
+		baseURL     string
+		maxBodySize int64
+		strict      bool
+	}
+
+	// Option configures a Client
+	Option func(c *Client)
+
+	// CallOption customises a single DoJSON call
+	CallOption func(c *call)
+
+	call struct {
+		header         http.Header
+		responseHeader *http.Header
+	}
+
+	// Validator is implemented by response types that can check required fields are present,
+	// it is only used in strict mode
+	Validator interface {
+		Validate() error
+	}
+
+	// localError marks failures that happen before a request is sent, they are not upstream failures
+	localError struct {
+		error
+	}
+)
+
+func (e localError) Unwrap() error {
+	return e.error
+}
+
+// Do calls the wrapped function
+func (fn DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// WithHTTPClient sets the underlying http client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout for requests made by the client
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Timeout = timeout
+
+		c.httpClient = &httpClient
+	}
+}
+
+// WithMiddleware appends middleware, the first one given is the outermost
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithMaxBodySize limits the size of response bodies read by the client
+func WithMaxBodySize(size int64) Option {
+	return func(c *Client) {
+		c.maxBodySize = size
+	}
+}
+
+// WithStrict makes decoding fail when the response does not match the expected schema: a field with
+// an unexpected type, or a response type implementing Validator that fails validation. Schema
+// changes are logged
+func WithStrict(strict bool) Option {
+	return func(c *Client) {
+		c.strict = strict
+	}
+}
+
+// WithRequestHeader sets a header on the request of a single call
+func WithRequestHeader(key string, value string) CallOption {
+	return func(c *call) {
+		c.header.Set(key, value)
+	}
+}
+
+// WithResponseHeader stores the response headers of a single call in dst, including for non 2xx
+// responses
+func WithResponseHeader(dst *http.Header) CallOption {
+	return func(c *call) {
+		c.responseHeader = dst
+	}
+}
+
+// New creates a new Client for the named service
+func New(service string, baseURL string, opts ...Option) (*Client, error) {
+	if baseURL == "" {
+		return nil, errors.Wrap(ErrInvalidParam, "url")
+	}
+
+	c := &Client{
+		service:     service,
+		maxBodySize: defaultMaxBodySize,
+		httpClient:  &http.Client{Timeout: defaultTimeout, CheckRedirect: sameOriginRedirect},
+		baseURL:     strings.TrimRight(baseURL, "/"),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// sameOriginRedirect only follows redirects to the scheme and host of the original request, so
+// headers such as API secrets are never sent elsewhere. Other redirects are returned as responses
+func sameOriginRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	if req.URL.Scheme != via[0].URL.Scheme || req.URL.Host != via[0].URL.Host {
+		return http.ErrUseLastResponse
+	}
+
+	return nil
+}
+
+// Service returns the name of the service the client talks to
+func (c *Client) Service() string {
+	return c.service
+}
+
+// Reconfigure changes the base url and timeout used by subsequent requests, it is safe to call
+// while requests are in flight
+func (c *Client) Reconfigure(baseURL string, timeout time.Duration) error {
+	if baseURL == "" {
+		return errors.Wrap(ErrInvalidParam, "url")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	httpClient := *c.httpClient
+	httpClient.Timeout = timeout
+
+	c.httpClient = &httpClient
+	c.baseURL = strings.TrimRight(baseURL, "/")
+
+	return nil
+}
+
+// SetDecoding changes the response size limit and strict mode used by subsequent requests, see
+// WithMaxBodySize and WithStrict. It is safe to call while requests are in flight
+func (c *Client) SetDecoding(maxBodySize int64, strict bool) error {
+	if maxBodySize <= 0 {
+		return errors.Wrap(ErrInvalidParam, "max body size")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxBodySize = maxBodySize
+	c.strict = strict
+
+	return nil
+}
+
+func (c *Client) decoding() (int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.maxBodySize, c.strict
+}
+
+func (c *Client) endpoint() (*http.Client, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.httpClient, c.baseURL
+}
+
+// Do sends req through the middleware chain, a transport failure is returned as *upstream.ResponseError
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	httpClient, _ := c.endpoint()
+
+	var doer Doer = httpClient
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		doer = c.middleware[i](doer)
+	}
+
+	res, err := doer.Do(req)
+	if err != nil {
+		var (
+			respErr *upstream.ResponseError
+			local   localError
+		)
+		if errors.As(err, &respErr) || errors.As(err, &local) {
+			return nil, err
+		}
+
+		return nil, upstream.FromTransport(c.service, err)
+	}
+
+	return res, nil
+}
+
+// DoJSON sends a request with in encoded as the json body, when in is not nil, to path relative to
+// the base url and decodes the response into out. Non 2xx responses are returned as
+// *upstream.ResponseError carrying the status code and a snippet of the body
+func (c *Client) DoJSON(ctx context.Context, method string, path string, in interface{}, out interface{}, opts ...CallOption) error {
+	_, baseURL := c.endpoint()
+
+	opt := &call{header: http.Header{}}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, "error marshalling request")
+		}
+
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
+	if err != nil {
+		return errors.Wrap(err, "error creating http request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	for key, values := range opt.header {
+		req.Header[key] = values
+	}
+
+	res, err := c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error sending request")
+	}
+	defer res.Body.Close()
+
+	if opt.responseHeader != nil {
+		*opt.responseHeader = res.Header.Clone()
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return c.statusError(res)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return c.decode(res, out)
+}
+
+// Ping checks the service answers on path relative to the base url, any response but a server
+// error counts, the body is discarded. Failures are returned as *upstream.ResponseError
+func (c *Client) Ping(ctx context.Context, path string) error {
+	_, baseURL := c.endpoint()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		return errors.Wrap(err, "error creating http request")
+	}
+
+	res, err := c.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error sending request")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 500 {
+		return c.statusError(res)
+	}
+
+	maxBodySize, _ := c.decoding()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(res.Body, maxBodySize))
+
+	return nil
+}
+
+// decode streams the json body into out. Bodies larger than the size limit or not declared as json
+// are rejected with a distinct *upstream.ResponseError
+func (c *Client) decode(res *http.Response, out interface{}) error {
+	if mediaType, _, err := mime.ParseMediaType(res.Header.Get("Content-Type")); err != nil || !isJSON(mediaType) {
+		return upstream.FromBody(c.service, res, upstream.ErrUnexpectedContentType,
+			errors.Errorf("unexpected content type %q", res.Header.Get("Content-Type")))
+	}
+
+	maxBodySize, strict := c.decoding()
+
+	if res.ContentLength > maxBodySize {
+		return c.tooLarge(res, maxBodySize)
+	}
+
+	limited := &io.LimitedReader{R: res.Body, N: maxBodySize + 1}
+
+	dec := json.NewDecoder(limited)
+
+	err := dec.Decode(out)
+	if err != nil && limited.N <= 0 {
+		return c.tooLarge(res, maxBodySize)
+	}
+
+	if !strict {
+		if err != nil {
+			return upstream.FromDecode(c.service, res, errors.Wrap(err, "error unmarshaling response"))
+		}
+
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if err != nil && !errors.As(err, &typeErr) {
+		return upstream.FromDecode(c.service, res, errors.Wrap(err, "error unmarshaling response"))
+	}
+
+	if v, ok := out.(Validator); ok && err == nil {
+		err = v.Validate()
+	}
+
+	if err != nil {
+		log.Warn().
+			Str("type", "upstream").
+			Str("service", c.service).
+			Str("url", res.Request.URL.Redacted()).
+			Err(err).
+			Msg("upstream response schema changed")
+
+		return upstream.FromBody(c.service, res, upstream.ErrUnexpectedSchema, err)
+	}
+
+	return nil
+}
+
+func (c *Client) tooLarge(res *http.Response, maxBodySize int64) error {
+	return upstream.FromBody(c.service, res, upstream.ErrResponseTooLarge,
+		errors.Errorf("response body exceeds %d bytes", maxBodySize))
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func (c *Client) statusError(res *http.Response) error {
+	snippet, _ := ioutil.ReadAll(io.LimitReader(res.Body, bodySnippetSize))
+
+	err := upstream.FromStatus(c.service, res)
+
+	var respErr *upstream.ResponseError
+	if errors.As(err, &respErr) {
+		respErr.Body = string(snippet)
+	}
+
+	return err
+}
@@ -0,0 +1,335 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"pokedex/pkg/adapter/upstream"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type pokemon struct {
+	Name string `json:"name"`
+}
+
+func (p *pokemon) Validate() error {
+	if p.Name == "" {
+		return errors.New("missing name")
+	}
+
+	return nil
+}
+
+func TestNew_Error(t *testing.T) {
+	t.Parallel()
+
+	c, err := New("pokeapi", "")
+	require.EqualError(t, err, "url: invalid parameter")
+	require.Nil(t, c)
+}
+
+func TestDoJSON_Error(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		handler  http.HandlerFunc
+		opts     []Option
+		checkErr func(t *testing.T, err error)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsStatusErrorWithBodySnippet": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+
+				_, err := w.Write([]byte("Not Found"))
+				require.NoError(t, err)
+			},
+			checkErr: func(t *testing.T, err error) {
+				var respErr *upstream.ResponseError
+				require.True(t, errors.As(err, &respErr))
+				require.Equal(t, http.StatusNotFound, respErr.StatusCode)
+				require.Equal(t, "Not Found", respErr.Body)
+				require.True(t, errors.Is(err, upstream.ErrBadResponse))
+			},
+		},
+		"ReturnsBadResponseWhenInvalidJSON": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				_, err := w.Write([]byte("not-a-json"))
+				require.NoError(t, err)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrBadResponse))
+			},
+		},
+		"ReturnsTooLargeWhenBodyExceedsLimit": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				// flushing forces a chunked response without a Content-Length
+				w.(http.Flusher).Flush()
+
+				_, err := w.Write([]byte(`{"name":"` + strings.Repeat("a", 64) + `"}`))
+				require.NoError(t, err)
+			},
+			opts: []Option{WithMaxBodySize(32)},
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrResponseTooLarge))
+				require.True(t, errors.Is(err, upstream.ErrBadResponse))
+				require.Contains(t, err.Error(), "response body exceeds 32 bytes")
+			},
+		},
+		"ReturnsTooLargeWhenContentLengthExceedsLimit": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				_, err := w.Write([]byte(`{"name":"` + strings.Repeat("a", 64) + `"}`))
+				require.NoError(t, err)
+			},
+			opts: []Option{WithMaxBodySize(32)},
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrResponseTooLarge))
+			},
+		},
+		"ReturnsUnexpectedContentTypeWhenNotJSON": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+
+				_, err := w.Write([]byte(`<html>maintenance</html>`))
+				require.NoError(t, err)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrUnexpectedContentType))
+				require.True(t, errors.Is(err, upstream.ErrBadResponse))
+			},
+		},
+		"ReturnsUnexpectedSchemaWhenStrictAndFieldTypeChanged": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				_, err := w.Write([]byte(`{"name":{"en":"mewtwo"}}`))
+				require.NoError(t, err)
+			},
+			opts: []Option{WithStrict(true)},
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrUnexpectedSchema))
+			},
+		},
+		"ReturnsUnexpectedSchemaWhenStrictAndValidationFails": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				_, err := w.Write([]byte(`{"species_name":"mewtwo"}`))
+				require.NoError(t, err)
+			},
+			opts: []Option{WithStrict(true)},
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrUnexpectedSchema))
+				require.Contains(t, err.Error(), "missing name")
+			},
+		},
+		"ReturnsBadResponseWhenNotStrictAndFieldTypeChanged": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				_, err := w.Write([]byte(`{"name":{"en":"mewtwo"}}`))
+				require.NoError(t, err)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrBadResponse))
+				require.False(t, errors.Is(err, upstream.ErrUnexpectedSchema))
+			},
+		},
+		"ReturnsUnavailableWhenTransportFails": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				conn.Close()
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrUnavailable))
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			c, err := New("pokeapi", server.URL, tc.opts...)
+			require.NoError(t, err)
+
+			var out pokemon
+			err = c.DoJSON(context.Background(), http.MethodGet, "/pokemon/mewtwo", nil, &out)
+
+			require.Error(t, err)
+			tc.checkErr(t, err)
+		})
+	}
+}
+
+func TestDoJSON_Success(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/translate/yoda.json", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		w.Header().Set("Content-Type", "application/problem+json")
+
+		_, err := w.Write([]byte(`{"name":"mewtwo","ignored":true}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	c, err := New("funtranslations", server.URL+"/")
+	require.NoError(t, err)
+
+	var out pokemon
+	err = c.DoJSON(context.Background(), http.MethodPost, "/translate/yoda.json", pokemon{Name: "in"}, &out)
+
+	require.NoError(t, err)
+	require.Equal(t, "mewtwo", out.Name)
+}
+
+func TestDoJSON_CallOptions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_, err := w.Write([]byte(`{"name":"mewtwo"}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	c, err := New("pokeapi", server.URL)
+	require.NoError(t, err)
+
+	var (
+		out    pokemon
+		header http.Header
+	)
+	err = c.DoJSON(context.Background(), http.MethodGet, "/", nil, &out, WithResponseHeader(&header))
+
+	require.NoError(t, err)
+	require.Equal(t, `"v1"`, header.Get("ETag"))
+
+	err = c.DoJSON(context.Background(), http.MethodGet, "/", nil, &out, WithRequestHeader("If-None-Match", `"v1"`))
+
+	require.Equal(t, http.StatusNotModified, upstream.StatusCode(err))
+}
+
+func TestSetDecoding(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		_, err := w.Write([]byte(`{"name":{"en":"mewtwo"}}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	c, err := New("pokeapi", server.URL)
+	require.NoError(t, err)
+
+	require.EqualError(t, c.SetDecoding(0, true), "max body size: invalid parameter")
+
+	require.NoError(t, c.SetDecoding(1024, true))
+
+	var out pokemon
+	err = c.DoJSON(context.Background(), http.MethodGet, "/", nil, &out)
+	require.ErrorIs(t, err, upstream.ErrUnexpectedSchema)
+
+	require.NoError(t, c.SetDecoding(8, false))
+
+	err = c.DoJSON(context.Background(), http.MethodGet, "/", nil, &out)
+	require.ErrorIs(t, err, upstream.ErrResponseTooLarge)
+}
+
+func TestPing(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		status int
+		err    error
+	}
+
+	tests := map[string]testcase{
+		"ReturnsNilWhenOK": {
+			status: http.StatusOK,
+		},
+		"ReturnsNilWhenNotFound": {
+			status: http.StatusNotFound,
+		},
+		"ReturnsNilWhenRateLimited": {
+			status: http.StatusTooManyRequests,
+		},
+		"ReturnsErrorWhenServerError": {
+			status: http.StatusServiceUnavailable,
+			err:    upstream.ErrUnavailable,
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodGet, r.Method)
+				require.Equal(t, "/api/v2/", r.URL.Path)
+
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			c, err := New("pokeapi", server.URL)
+			require.NoError(t, err)
+
+			err = c.Ping(context.Background(), "/api/v2/")
+			if tc.err == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestPing_Unreachable(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	c, err := New("pokeapi", server.URL)
+	require.NoError(t, err)
+
+	err = c.Ping(context.Background(), "/")
+	require.ErrorIs(t, err, upstream.ErrUnavailable)
+}
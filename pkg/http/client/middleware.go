@@ -0,0 +1,159 @@
+package client
+
+import (
+	"net/http"
+	"pokedex/pkg/secret"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	requestIDHeader = "X-Request-Id"
+)
+
+// ObserveFunc receives the outcome of every request, res is nil when err is set
+type ObserveFunc func(req *http.Request, res *http.Response, err error, duration time.Duration)
+
+// Auth sets header to the current value of the secret source on a copy of every request, the secret
+// is redacted from returned errors
+func Auth(header string, source func() secret.Source) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			src := source()
+			if src == nil {
+				return next.Do(req)
+			}
+
+			value, err := src.Secret()
+			if err != nil {
+				return nil, localError{errors.Wrap(err, "error reading api secret")}
+			}
+
+			if value == "" {
+				return next.Do(req)
+			}
+
+			req = req.Clone(req.Context())
+			req.Header.Set(header, value)
+
+			res, err := next.Do(req)
+
+			return res, secret.Redact(err, value)
+		})
+	}
+}
+
+// Retry retries idempotent requests failing with a transport error or a 502, 503 or 504 response,
+// waiting backoff doubled after every attempt. Requests with a body that can not be replayed are
+// not retried
+func Retry(attempts int, backoff time.Duration) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if !retryable(req) {
+				return next.Do(req)
+			}
+
+			wait := backoff
+
+			for attempt := 1; ; attempt++ {
+				res, err := next.Do(req)
+				if attempt >= attempts || !shouldRetry(res, err) {
+					return res, err
+				}
+
+				if res != nil {
+					res.Body.Close()
+				}
+
+				select {
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				case <-time.After(wait):
+				}
+
+				wait *= 2
+
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+
+					req.Body = body
+				}
+			}
+		})
+	}
+}
+
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	}
+
+	return false
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// Logging logs every request at debug level, headers are never logged
+func Logging(logger *zerolog.Logger, service string) Middleware {
+	return Metrics(func(req *http.Request, res *http.Response, err error, duration time.Duration) {
+		event := logger.Debug().
+			Str("type", "upstream").
+			Str("service", service).
+			Str("method", req.Method).
+			Str("url", req.URL.Redacted()).
+			Float64("latency_ms", float64(duration.Nanoseconds())/1000000.0)
+
+		if res != nil {
+			event = event.Int("status", res.StatusCode)
+		}
+
+		event.Err(err).Msg("outgoing_request")
+	})
+}
+
+// Metrics calls observe with the outcome and duration of every request
+func Metrics(observe ObserveFunc) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+
+			res, err := next.Do(req)
+
+			observe(req, res, err, time.Since(start))
+
+			return res, err
+		})
+	}
+}
+
+// Tracing propagates the id of the incoming request, set by chi's RequestID middleware, to upstream
+// requests so they can be correlated
+func Tracing() Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			if id := middleware.GetReqID(req.Context()); id != "" && req.Header.Get(requestIDHeader) == "" {
+				req.Header.Set(requestIDHeader, id)
+			}
+
+			return next.Do(req)
+		})
+	}
+}
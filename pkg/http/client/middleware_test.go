@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"pokedex/pkg/adapter/upstream"
+	"pokedex/pkg/secret"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		method   string
+		statuses []int
+		calls    int32
+		checkErr func(t *testing.T, err error)
+	}
+
+	tests := map[string]testcase{
+		"RetriesUntilSuccess": {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			calls:    3,
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		"StopsAfterMaxAttempts": {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusOK},
+			calls:    3,
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrTimeout))
+			},
+		},
+		"DoesNotRetryClientErrors": {
+			method:   http.MethodGet,
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			calls:    1,
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrRateLimited))
+			},
+		},
+		"DoesNotRetryNonIdempotentRequests": {
+			method:   http.MethodPost,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			calls:    1,
+			checkErr: func(t *testing.T, err error) {
+				require.True(t, errors.Is(err, upstream.ErrUnavailable))
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&calls, 1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.statuses[n-1])
+
+				_, err := w.Write([]byte(`{"name":"mewtwo"}`))
+				require.NoError(t, err)
+			}))
+			defer server.Close()
+
+			c, err := New("pokeapi", server.URL, WithMiddleware(Retry(3, time.Millisecond)))
+			require.NoError(t, err)
+
+			var out pokemon
+			err = c.DoJSON(context.Background(), tc.method, "/", nil, &out)
+
+			tc.checkErr(t, err)
+			require.Equal(t, tc.calls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestAuth(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "s3cr3t", r.Header.Get("X-Api-Secret"))
+
+		_, err := w.Write([]byte(`{}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	source := func() secret.Source { return secret.Static("s3cr3t") }
+
+	c, err := New("funtranslations", server.URL, WithMiddleware(Auth("X-Api-Secret", source)))
+	require.NoError(t, err)
+
+	err = c.DoJSON(context.Background(), http.MethodGet, "/", nil, nil)
+	require.NoError(t, err)
+}
+
+func TestAuth_Redirect(t *testing.T) {
+	t.Parallel()
+
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Fail(t, "redirect to another host followed")
+	}))
+	defer other.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "s3cr3t", r.Header.Get("X-Api-Secret"))
+
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/", http.StatusFound)
+		case "/elsewhere":
+			http.Redirect(w, r, other.URL, http.StatusFound)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	source := func() secret.Source { return secret.Static("s3cr3t") }
+
+	c, err := New("funtranslations", server.URL, WithMiddleware(Auth("X-Api-Secret", source)))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/moved", nil)
+	require.NoError(t, err)
+
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "/", res.Request.URL.Path)
+	require.Empty(t, req.Header.Get("X-Api-Secret"))
+
+	err = c.DoJSON(context.Background(), http.MethodGet, "/elsewhere", nil, nil)
+	require.ErrorIs(t, err, upstream.ErrBadResponse)
+	require.Equal(t, http.StatusFound, upstream.StatusCode(err))
+}
+
+func TestMetricsAndTracing(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "request-id", r.Header.Get("X-Request-Id"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var observed int
+
+	observe := func(req *http.Request, res *http.Response, err error, duration time.Duration) {
+		observed = res.StatusCode
+	}
+
+	c, err := New("pokeapi", server.URL, WithMiddleware(Metrics(observe), Tracing()))
+	require.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "request-id")
+
+	err = c.DoJSON(ctx, http.MethodGet, "/", nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, observed)
+}
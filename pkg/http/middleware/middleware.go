@@ -1,14 +1,15 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"os"
+	"pokedex/pkg/http/response"
 	"runtime/debug"
 	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
-	"github.com/go-chi/render"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -18,11 +19,10 @@ func Common(router *chi.Mux) {
 	// using console writer only for pretty human-friendly, colorized output
 	// as it's inefficient we won't be using that on prod
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 
+	router.Use(middleware.RequestID)
 	router.Use(LoggerMiddleware(&log.Logger))
 	router.Use(middleware.StripSlashes)
-	router.Use(render.SetContentType(render.ContentTypeJSON))
 }
 
 // LoggerMiddleware basic middleware logger for chi using zerolog
@@ -69,3 +69,45 @@ func LoggerMiddleware(logger *zerolog.Logger) func(next http.Handler) http.Handl
 		return http.HandlerFunc(fn)
 	}
 }
+
+// CacheControl sets a public Cache-Control max-age for the wrapped routes, a zero max age sends
+// no-cache so clients revalidate every time. Renderers replace the header with no-store for responses
+// that must not be cached
+func CacheControl(maxAge time.Duration) func(next http.Handler) http.Handler {
+	return CacheControlFunc(func() time.Duration { return maxAge })
+}
+
+// CacheControlFunc works like CacheControl but reads the max age on every request, allowing it to
+// change at runtime
+func CacheControlFunc(maxAge func() time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			value := "no-cache"
+			if d := maxAge(); d > 0 {
+				value = fmt.Sprintf("public, max-age=%d", int(d.Seconds()))
+			}
+
+			w.Header().Set("Cache-Control", value)
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// LastModifiedFunc sets the last modification time of the responses of the wrapped routes to the one
+// returned by lastModified, read on every request. Responses get no Last-Modified while it is zero
+func LastModifiedFunc(lastModified func() time.Time) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if t := lastModified(); !t.IsZero() {
+				r = r.WithContext(response.WithLastModified(r.Context(), t))
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,136 @@
+package response
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	headerCacheControl    = "Cache-Control"
+	headerETag            = "ETag"
+	headerLastModified    = "Last-Modified"
+	headerIfNoneMatch     = "If-None-Match"
+	headerIfModifiedSince = "If-Modified-Since"
+
+	noStore = "no-store"
+)
+
+// LastModifier can be implemented by response bodies that know when their content last changed,
+// allowing renderers to set the Last-Modified header and answer If-Modified-Since requests
+type LastModifier interface {
+	LastModified() time.Time
+}
+
+type lastModifiedKey struct{}
+
+// WithLastModified returns a copy of ctx in which responses whose body is not a LastModifier were
+// last modified at t, e.g. when they are served from a dataset built at t
+func WithLastModified(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, lastModifiedKey{}, t)
+}
+
+// ETag returns a strong entity tag for the given encoded body
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// write sends an already encoded body, adding cache validators to successful GET and HEAD responses
+// and answering conditional requests with 304 Not Modified when the client copy is still fresh
+func write(w http.ResponseWriter, r *http.Request, status int, contentType string, body []byte, lastModified time.Time) error {
+	if !cacheable(r, status) {
+		if w.Header().Get(headerCacheControl) != "" {
+			w.Header().Set(headerCacheControl, noStore)
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+
+		_, err := w.Write(body)
+
+		return err
+	}
+
+	etag := ETag(body)
+	w.Header().Set(headerETag, etag)
+
+	if !lastModified.IsZero() {
+		w.Header().Set(headerLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return nil
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+
+	if r.Method == http.MethodHead {
+		return nil
+	}
+
+	_, err := w.Write(body)
+
+	return err
+}
+
+func cacheable(r *http.Request, status int) bool {
+	if r.Method != "" && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	return status == http.StatusOK
+}
+
+// notModified evaluates conditional request headers, If-None-Match takes precedence over
+// If-Modified-Since as per RFC 7232
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get(headerIfNoneMatch); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	ims := r.Header.Get(headerIfModifiedSince)
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(t)
+}
+
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+
+		// weak comparison is used for If-None-Match
+		if strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lastModifiedOf(r *http.Request, body interface{}) time.Time {
+	if lm, ok := body.(LastModifier); ok {
+		return lm.LastModified()
+	}
+
+	t, _ := r.Context().Value(lastModifiedKey{}).(time.Time)
+
+	return t
+}
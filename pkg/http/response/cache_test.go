@@ -0,0 +1,168 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type modifiedBody struct {
+	Name string `json:"name"`
+}
+
+func (modifiedBody) LastModified() time.Time {
+	return time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+}
+
+func TestJSON_CacheValidators(t *testing.T) {
+	t.Parallel()
+
+	body := modifiedBody{Name: "mewtwo"}
+
+	etag := func(t *testing.T) string {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		require.NoError(t, JSON(http.StatusOK, body).Render(rec, req))
+
+		return rec.Header().Get("ETag")
+	}
+
+	type testcase struct {
+		method        string
+		status        int
+		headers       func(t *testing.T) map[string]string
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder)
+	}
+
+	tests := map[string]testcase{
+		"SetsValidatorsOnSuccessfulGet": {
+			method: http.MethodGet,
+			status: http.StatusOK,
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.NotEmpty(t, rec.Header().Get("ETag"))
+				require.Equal(t, "Mon, 01 Mar 2021 10:00:00 GMT", rec.Header().Get("Last-Modified"))
+				require.JSONEq(t, `{"name":"mewtwo"}`, rec.Body.String())
+			},
+		},
+		"ReturnsNotModifiedWhenETagMatches": {
+			method: http.MethodGet,
+			status: http.StatusOK,
+			headers: func(t *testing.T) map[string]string {
+				return map[string]string{"If-None-Match": `"foo", ` + etag(t)}
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, rec.Code)
+				require.Empty(t, rec.Body.Bytes())
+			},
+		},
+		"ReturnsOKWhenETagDiffers": {
+			method: http.MethodGet,
+			status: http.StatusOK,
+			headers: func(t *testing.T) map[string]string {
+				return map[string]string{"If-None-Match": `"foo"`}
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		"ReturnsNotModifiedWhenNotModifiedSince": {
+			method: http.MethodGet,
+			status: http.StatusOK,
+			headers: func(t *testing.T) map[string]string {
+				return map[string]string{"If-Modified-Since": "Tue, 02 Mar 2021 10:00:00 GMT"}
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, rec.Code)
+			},
+		},
+		"ReturnsOKWhenModifiedSince": {
+			method: http.MethodGet,
+			status: http.StatusOK,
+			headers: func(t *testing.T) map[string]string {
+				return map[string]string{"If-Modified-Since": "Sun, 28 Feb 2021 10:00:00 GMT"}
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		"SkipsValidatorsAndCachingOnErrors": {
+			method: http.MethodGet,
+			status: http.StatusNotFound,
+			headers: func(t *testing.T) map[string]string {
+				return map[string]string{"If-None-Match": "*"}
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, rec.Code)
+				require.Empty(t, rec.Header().Get("ETag"))
+				require.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set("Cache-Control", "public, max-age=60")
+
+			req := httptest.NewRequest(tc.method, "/", nil)
+			if tc.headers != nil {
+				for k, v := range tc.headers(t) {
+					req.Header.Set(k, v)
+				}
+			}
+
+			err := JSON(tc.status, body).Render(rec, req)
+			require.NoError(t, err)
+
+			tc.checkResponse(t, rec)
+		})
+	}
+}
+
+func TestJSON_LastModifiedFromContext(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		ifModifiedSince string
+		status          int
+	}{
+		"SetsLastModified": {
+			status: http.StatusOK,
+		},
+		"ReturnsNotModifiedWhenNotModifiedSince": {
+			ifModifiedSince: "Tue, 02 Mar 2021 10:00:00 GMT",
+			status:          http.StatusNotModified,
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req = req.WithContext(WithLastModified(req.Context(), modified))
+
+			if tc.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tc.ifModifiedSince)
+			}
+
+			require.NoError(t, JSON(http.StatusOK, map[string]string{"name": "mewtwo"}).Render(rec, req))
+			require.Equal(t, tc.status, rec.Code)
+			require.Equal(t, "Mon, 01 Mar 2021 10:00:00 GMT", rec.Header().Get("Last-Modified"))
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// encodeJSON encodes body the same way for every renderer, a nil body produces no data
+func encodeJSON(body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	var buff bytes.Buffer
+
+	enc := json.NewEncoder(&buff)
+	enc.SetEscapeHTML(true)
+
+	if err := enc.Encode(body); err != nil {
+		return nil, err
+	}
+
+	return buff.Bytes(), nil
+}
+
+// generic converts body into maps, slices and scalars through its json representation so
+// every format uses the same field names as the json renderer
+func generic(body interface{}) (interface{}, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return normalise(v), nil
+}
+
+func normalise(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = normalise(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalise(item)
+		}
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+
+		f, _ := val.Float64()
+
+		return f
+	}
+
+	return v
+}
+
+func encodeYAML(body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	v, err := generic(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(v)
+}
+
+// encodeCSV writes a header row followed by one row per element, a single object is written as
+// a single row. Nested values are written as json
+func encodeCSV(body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	v, err := generic(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]interface{}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		rows = append(rows, val)
+	case []interface{}:
+		for _, item := range val {
+			row, ok := item.(map[string]interface{})
+			if !ok {
+				row = map[string]interface{}{"value": item}
+			}
+
+			rows = append(rows, row)
+		}
+	default:
+		rows = append(rows, map[string]interface{}{"value": val})
+	}
+
+	columns := csvColumns(rows)
+
+	var buff bytes.Buffer
+	w := csv.NewWriter(&buff)
+
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(columns))
+
+		for i, column := range columns {
+			cell, err := csvCell(row[column])
+			if err != nil {
+				return nil, err
+			}
+
+			record[i] = cell
+		}
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+
+	return buff.Bytes(), w.Error()
+}
+
+func csvColumns(rows []map[string]interface{}) []string {
+	seen := map[string]bool{}
+	columns := []string{}
+
+	for _, row := range rows {
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+
+	sort.Strings(columns)
+
+	return columns
+}
+
+func csvCell(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64), nil
+	}
+
+	data, err := json.Marshal(v)
+
+	return string(data), err
+}
+
+// encodeMsgPack encodes body as MessagePack, only the types produced by generic are supported
+func encodeMsgPack(body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+
+	v, err := generic(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var buff bytes.Buffer
+	if err := writeMsgPack(&buff, v); err != nil {
+		return nil, err
+	}
+
+	return buff.Bytes(), nil
+}
+
+func writeMsgPack(buff *bytes.Buffer, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		buff.WriteByte(0xc0)
+	case bool:
+		if val {
+			buff.WriteByte(0xc3)
+		} else {
+			buff.WriteByte(0xc2)
+		}
+	case int64:
+		writeMsgPackInt(buff, val)
+	case float64:
+		buff.WriteByte(0xcb)
+		writeUint(buff, math.Float64bits(val), 8)
+	case string:
+		writeMsgPackHeader(buff, len(val), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		buff.WriteString(val)
+	case []interface{}:
+		writeMsgPackHeader(buff, len(val), 0x90, 15, 0, 0xdc, 0xdd)
+
+		for _, item := range val {
+			if err := writeMsgPack(buff, item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		writeMsgPackHeader(buff, len(val), 0x80, 15, 0, 0xde, 0xdf)
+
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if err := writeMsgPack(buff, k); err != nil {
+				return err
+			}
+
+			if err := writeMsgPack(buff, val[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return errors.Errorf("unsupported msgpack type: %s", reflect.TypeOf(v))
+	}
+
+	return nil
+}
+
+func writeMsgPackInt(buff *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i <= 127:
+		buff.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buff.WriteByte(byte(int8(i)))
+	default:
+		buff.WriteByte(0xd3)
+		writeUint(buff, uint64(i), 8)
+	}
+}
+
+// writeMsgPackHeader writes a length prefixed header using the fix, 8, 16 or 32 bit form; a zero
+// code8 means the type has no 8 bit form
+func writeMsgPackHeader(buff *bytes.Buffer, n int, fix byte, fixMax int, code8, code16, code32 byte) {
+	switch {
+	case n <= fixMax:
+		buff.WriteByte(fix | byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		buff.WriteByte(code8)
+		writeUint(buff, uint64(n), 1)
+	case n <= math.MaxUint16:
+		buff.WriteByte(code16)
+		writeUint(buff, uint64(n), 2)
+	default:
+		buff.WriteByte(code32)
+		writeUint(buff, uint64(n), 4)
+	}
+}
+
+func writeUint(buff *bytes.Buffer, v uint64, size int) {
+	for i := size - 1; i >= 0; i-- {
+		buff.WriteByte(byte(v >> (8 * uint(i))))
+	}
+}
@@ -0,0 +1,154 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	formatParam = "format"
+)
+
+// Content types produced by the renderers
+const (
+	ContentTypeJSON    = "application/json; charset=utf-8"
+	ContentTypeYAML    = "application/yaml; charset=utf-8"
+	ContentTypeMsgPack = "application/msgpack"
+	ContentTypeCSV     = "text/csv; charset=utf-8"
+)
+
+// ErrNotAcceptable is returned when none of the supported formats satisfies the request
+var ErrNotAcceptable error = codedError{code: "not_acceptable", msg: "none of the requested formats is supported"}
+
+type encoder struct {
+	contentType string
+	encode      func(body interface{}) ([]byte, error)
+}
+
+var (
+	jsonEncoder    = encoder{contentType: ContentTypeJSON, encode: encodeJSON}
+	yamlEncoder    = encoder{contentType: ContentTypeYAML, encode: encodeYAML}
+	msgPackEncoder = encoder{contentType: ContentTypeMsgPack, encode: encodeMsgPack}
+	csvEncoder     = encoder{contentType: ContentTypeCSV, encode: encodeCSV}
+
+	// formats maps the ?format= override values to encoders
+	formats = map[string]encoder{
+		"json":    jsonEncoder,
+		"yaml":    yamlEncoder,
+		"msgpack": msgPackEncoder,
+		"csv":     csvEncoder,
+	}
+
+	// mediaTypes maps accepted media types, including common aliases, to encoders
+	mediaTypes = map[string]encoder{
+		"application/json":        jsonEncoder,
+		"application/yaml":        yamlEncoder,
+		"application/x-yaml":      yamlEncoder,
+		"text/yaml":               yamlEncoder,
+		"application/msgpack":     msgPackEncoder,
+		"application/x-msgpack":   msgPackEncoder,
+		"application/vnd.msgpack": msgPackEncoder,
+		"text/csv":                csvEncoder,
+	}
+)
+
+// Negotiate provides a renderer that encodes the body in the format picked from the ?format= query
+// parameter or the Accept header, falling back to json when the client accepts anything.
+// A 406 is rendered when no supported format is acceptable
+func Negotiate(status int, body interface{}) Renderer {
+	return RendererFunc(func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Add("Vary", "Accept")
+
+		enc, ok := negotiate(r)
+		if !ok {
+			return Error(http.StatusNotAcceptable, ErrNotAcceptable).Render(w, r)
+		}
+
+		data, err := enc.encode(body)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode %s body", enc.contentType)
+		}
+
+		if err := write(w, r, status, enc.contentType, data, lastModifiedOf(r, body)); err != nil {
+			return errors.Wrapf(err, "failed to write %s body", enc.contentType)
+		}
+
+		return nil
+	})
+}
+
+func negotiate(r *http.Request) (encoder, bool) {
+	if r.URL != nil {
+		if format := r.URL.Query().Get(formatParam); format != "" {
+			enc, ok := formats[strings.ToLower(format)]
+
+			return enc, ok
+		}
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return jsonEncoder, true
+	}
+
+	for _, mediaType := range parseAccept(accept) {
+		switch {
+		case mediaType == "*/*" || mediaType == "application/*":
+			return jsonEncoder, true
+		case mediaType == "text/*":
+			return csvEncoder, true
+		}
+
+		if enc, ok := mediaTypes[mediaType]; ok {
+			return enc, true
+		}
+	}
+
+	return encoder{}, false
+}
+
+// parseAccept returns the acceptable media types ordered by preference, entries with q=0 are dropped
+func parseAccept(header string) []string {
+	type acceptable struct {
+		mediaType string
+		q         float64
+	}
+
+	var candidates []acceptable
+
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if q <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, acceptable{mediaType: mediaType, q: q})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	mediaTypes := make([]string, len(candidates))
+	for i, c := range candidates {
+		mediaTypes[i] = c.mediaType
+	}
+
+	return mediaTypes
+}
@@ -0,0 +1,125 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type negotiatedBody struct {
+	Name        string `json:"name"`
+	BaseHP      int    `json:"base_hp"`
+	IsLegendary bool   `json:"is_legendary"`
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		target        string
+		accept        string
+		body          interface{}
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder)
+	}
+
+	tests := map[string]testcase{
+		"ReturnsJSONWhenNoAcceptHeader": {
+			target: "/",
+			body:   negotiatedBody{Name: "mewtwo", BaseHP: 106, IsLegendary: true},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.Equal(t, ContentTypeJSON, rec.Header().Get("Content-Type"))
+				require.JSONEq(t, `{"name":"mewtwo","base_hp":106,"is_legendary":true}`, rec.Body.String())
+			},
+		},
+		"ReturnsJSONWhenAnyTypeAccepted": {
+			target: "/",
+			accept: "*/*",
+			body:   negotiatedBody{Name: "mewtwo"},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, ContentTypeJSON, rec.Header().Get("Content-Type"))
+			},
+		},
+		"ReturnsYAMLWhenPreferred": {
+			target: "/",
+			accept: "application/json;q=0.5, application/yaml",
+			body:   negotiatedBody{Name: "mewtwo", BaseHP: 106, IsLegendary: true},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, ContentTypeYAML, rec.Header().Get("Content-Type"))
+				require.Equal(t, "base_hp: 106\nis_legendary: true\nname: mewtwo\n", rec.Body.String())
+			},
+		},
+		"ReturnsMsgPackWhenAccepted": {
+			target: "/",
+			accept: "application/x-msgpack",
+			body:   negotiatedBody{Name: "mew", BaseHP: 100, IsLegendary: false},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, ContentTypeMsgPack, rec.Header().Get("Content-Type"))
+
+				expected := []byte{0x83,
+					0xa7, 'b', 'a', 's', 'e', '_', 'h', 'p', 0x64,
+					0xac, 'i', 's', '_', 'l', 'e', 'g', 'e', 'n', 'd', 'a', 'r', 'y', 0xc2,
+					0xa4, 'n', 'a', 'm', 'e', 0xa3, 'm', 'e', 'w',
+				}
+				require.Equal(t, expected, rec.Body.Bytes())
+			},
+		},
+		"ReturnsCSVRowsForLists": {
+			target: "/",
+			accept: "text/csv",
+			body: []negotiatedBody{
+				{Name: "mewtwo", BaseHP: 106, IsLegendary: true},
+				{Name: "zubat, the bat", BaseHP: 40},
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, ContentTypeCSV, rec.Header().Get("Content-Type"))
+				require.Equal(t, "base_hp,is_legendary,name\n106,true,mewtwo\n40,false,\"zubat, the bat\"\n", rec.Body.String())
+			},
+		},
+		"FormatParamOverridesAcceptHeader": {
+			target: "/?format=csv",
+			accept: "application/json",
+			body:   negotiatedBody{Name: "mewtwo"},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, ContentTypeCSV, rec.Header().Get("Content-Type"))
+			},
+		},
+		"ReturnsNotAcceptableWhenFormatUnsupported": {
+			target: "/?format=xml",
+			body:   negotiatedBody{Name: "mewtwo"},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotAcceptable, rec.Code)
+			},
+		},
+		"ReturnsNotAcceptableWhenAcceptUnsupported": {
+			target: "/",
+			accept: "application/xml, text/html;q=0.9, application/json;q=0",
+			body:   negotiatedBody{Name: "mewtwo"},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotAcceptable, rec.Code)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			err := Negotiate(http.StatusOK, tc.body).Render(rec, req)
+			require.NoError(t, err)
+
+			tc.checkResponse(t, rec)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package response
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ContentTypeProblem is the RFC 7807 problem details media type
+	ContentTypeProblem = "application/problem+json; charset=utf-8"
+
+	problemTypePrefix = "urn:pokedex:problem:"
+
+	codeInternal = "internal_error"
+)
+
+// Coder is implemented by errors that are safe to expose to clients, the code is a stable machine
+// readable identifier and the error message becomes the problem detail
+type Coder interface {
+	error
+	Code() string
+}
+
+// Problem is an RFC 7807 problem details body extended with a stable machine readable code
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+}
+
+// codedError is a client safe error defined by this package
+type codedError struct {
+	code string
+	msg  string
+}
+
+func (e codedError) Error() string { return e.msg }
+
+func (e codedError) Code() string { return e.code }
+
+// ProblemJSON provides a renderer that writes the given problem as application/problem+json,
+// type, title and instance are filled in when missing
+func ProblemJSON(p Problem) Renderer {
+	return RendererFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if p.Type == "" {
+			p.Type = problemTypePrefix + p.Code
+		}
+
+		if p.Title == "" {
+			p.Title = http.StatusText(p.Status)
+		}
+
+		if p.Instance == "" && r.URL != nil {
+			p.Instance = r.URL.Path
+		}
+
+		data, err := encodeJSON(p)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode problem body")
+		}
+
+		if err := write(w, r, p.Status, ContentTypeProblem, data, time.Time{}); err != nil {
+			return errors.Wrap(err, "failed to write problem body")
+		}
+
+		return nil
+	})
+}
+
+// problemFor builds a problem for err, only errors implementing Coder expose their message
+func problemFor(status int, err error) Problem {
+	p := Problem{
+		Status: status,
+		Code:   codeInternal,
+		Detail: http.StatusText(status),
+	}
+
+	var coder Coder
+	if errors.As(err, &coder) {
+		p.Code = coder.Code()
+		p.Detail = coder.Error()
+	}
+
+	return p
+}
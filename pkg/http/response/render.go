@@ -1,8 +1,6 @@
 package response
 
 import (
-	"bytes"
-	"encoding/json"
 	"net/http"
 
 	"github.com/pkg/errors"
@@ -36,49 +34,59 @@ func Render(fn RenderFunc) http.HandlerFunc {
 }
 
 // JSON provides a renderer that write the given json body
-// if a nil body is given no data will be written on the response body
+// if a nil body is given no data will be written on the response body.
+// Successful GET responses carry a strong ETag and honour conditional request headers
 func JSON(status int, body interface{}) Renderer {
 	return RendererFunc(func(w http.ResponseWriter, r *http.Request) error {
-		w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		w.WriteHeader(status)
-
-		if body != nil {
-			var buff bytes.Buffer
-
-			enc := json.NewEncoder(&buff)
-			enc.SetEscapeHTML(true)
-
-			if err := enc.Encode(body); err != nil {
-				return errors.Wrap(err, "failed to encode json body")
-			}
+		data, err := encodeJSON(body)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode json body")
+		}
 
-			if _, err := w.Write(buff.Bytes()); err != nil {
-				return errors.Wrap(err, "failed to write json body")
-			}
+		if err := write(w, r, status, ContentTypeJSON, data, lastModifiedOf(r, body)); err != nil {
+			return errors.Wrap(err, "failed to write json body")
 		}
 
 		return nil
 	})
 }
 
-// Error returns a renderer for given error
+// Error returns a problem renderer for given error. The error message is only exposed when the
+// error implements Coder, anything else is replaced by a generic detail and logged
 func Error(status int, err error) Renderer {
-	return JSON(status, map[string]string{
-		"Error": err.Error(),
+	return RendererFunc(func(w http.ResponseWriter, r *http.Request) error {
+		var coder Coder
+		if !errors.As(err, &coder) {
+			log.Info().Err(err).Int("status", status).Msg("rendering error without client safe detail")
+		}
+
+		return ProblemJSON(problemFor(status, err)).Render(w, r)
 	})
 }
 
-// InternalServerError returns a 500 renderer, the given error is logged
+// InternalServerError returns a 500 renderer, the given error is logged and never exposed
 func InternalServerError(err error) Renderer {
 	return RendererFunc(func(w http.ResponseWriter, r *http.Request) error {
-		log.Info().Err(err).Send()
-		return Error(http.StatusInternalServerError, err).Render(w, r)
+		log.Error().Err(err).Send()
+
+		return ProblemJSON(Problem{
+			Status: http.StatusInternalServerError,
+			Code:   codeInternal,
+			Detail: "an internal error occurred",
+		}).Render(w, r)
 	})
 }
 
 // NotFound returns a 404 renderer
 func NotFound(err error) Renderer {
+	return Error(http.StatusNotFound, err)
+}
+
+// WithHeader returns a renderer that sets a response header before delegating to next
+func WithHeader(key string, value string, next Renderer) Renderer {
 	return RendererFunc(func(w http.ResponseWriter, r *http.Request) error {
-		return Error(http.StatusNotFound, err).Render(w, r)
+		w.Header().Set(key, value)
+
+		return next.Render(w, r)
 	})
 }
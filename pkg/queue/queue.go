@@ -0,0 +1,451 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	openTimeout = time.Second
+
+	// idSize is the number of random bytes of a task ID
+	idSize = 16
+)
+
+// Task states
+const (
+	StatePending = "pending"
+	StateRunning = "running"
+	StateDone    = "done"
+)
+
+var (
+	ErrInvalidParam = errors.New("invalid parameter")
+	ErrNotFound     = errors.New("task not found")
+	ErrFull         = errors.New("queue full")
+
+	taskBucket = []byte("task")
+	dueBucket  = []byte("due")
+)
+
+type (
+	// Queue is a persistent task queue in an embedded bbolt database. Tasks are kept with their state
+	// and result until purged so their progress can be looked up, pending tasks are claimed in the
+	// order they are due. Tasks claimed but not completed when the process stopped are pending again
+	// once the queue is reopened
+	Queue struct {
+		db   *bolt.DB
+		wake chan struct{}
+		now  func() time.Time
+	}
+
+	// Task is a unit of work, Result is set once it is done
+	Task struct {
+		ID        string          `json:"id"`
+		State     string          `json:"state"`
+		Payload   json.RawMessage `json:"payload"`
+		Result    json.RawMessage `json:"result,omitempty"`
+		Attempts  int             `json:"attempts"`
+		CreatedAt time.Time       `json:"created_at"`
+		UpdatedAt time.Time       `json:"updated_at"`
+		DueAt     time.Time       `json:"due_at"`
+	}
+)
+
+// Open opens or creates the queue at the given path
+func Open(path string) (*Queue, error) {
+	if path == "" {
+		return nil, errors.Wrap(ErrInvalidParam, "path")
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open queue %s", path)
+	}
+
+	q := &Queue{
+		db:   db,
+		wake: make(chan struct{}, 1),
+		now:  time.Now,
+	}
+
+	if err := db.Update(q.recover); err != nil {
+		db.Close()
+
+		return nil, errors.Wrap(err, "failed to recover queue")
+	}
+
+	return q, nil
+}
+
+// recover creates the buckets and makes the tasks left running by a previous process pending again
+func (q *Queue) recover(tx *bolt.Tx) error {
+	tasks, err := tx.CreateBucketIfNotExists(taskBucket)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(dueBucket); err != nil {
+		return err
+	}
+
+	var running []Task
+
+	err = tasks.ForEach(func(k, v []byte) error {
+		var task Task
+		if err := json.Unmarshal(v, &task); err != nil {
+			return errors.Wrapf(err, "failed to decode task %s", k)
+		}
+
+		if task.State == StateRunning {
+			running = append(running, task)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, task := range running {
+		task.State = StatePending
+		task.DueAt = q.now()
+
+		if err := put(tx, task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close closes the database
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// Push queues a task with the given payload, due now
+func (q *Queue) Push(ctx context.Context, payload interface{}) (Task, error) {
+	return q.push(payload, 0)
+}
+
+// PushBounded queues a task like Push unless limit tasks are already pending, ErrFull is returned
+// then. Tasks waiting to be retried are pending
+func (q *Queue) PushBounded(ctx context.Context, payload interface{}, limit int) (Task, error) {
+	if limit < 1 {
+		return Task{}, errors.Wrap(ErrInvalidParam, "limit")
+	}
+
+	return q.push(payload, limit)
+}
+
+// push queues a task, the number of pending tasks is not limited when limit is 0
+func (q *Queue) push(payload interface{}, limit int) (Task, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Task{}, errors.Wrap(err, "failed to encode payload")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return Task{}, err
+	}
+
+	now := q.now()
+	task := Task{
+		ID:        id,
+		State:     StatePending,
+		Payload:   data,
+		CreatedAt: now,
+		UpdatedAt: now,
+		DueAt:     now,
+	}
+
+	err = q.db.Update(func(tx *bolt.Tx) error {
+		if limit > 0 && tx.Bucket(dueBucket).Stats().KeyN >= limit {
+			return ErrFull
+		}
+
+		return put(tx, task)
+	})
+	if err != nil {
+		return Task{}, err
+	}
+
+	q.signal()
+
+	return task, nil
+}
+
+// Get returns the task with the given ID, false when there is none
+func (q *Queue) Get(ctx context.Context, id string) (Task, bool, error) {
+	var (
+		task Task
+		ok   bool
+	)
+
+	err := q.db.View(func(tx *bolt.Tx) error {
+		var err error
+
+		task, ok, err = get(tx, id)
+
+		return err
+	})
+
+	return task, ok, err
+}
+
+// Claim waits until a pending task is due and returns it, marked running with one more attempt,
+// it returns an error when ctx is done first
+func (q *Queue) Claim(ctx context.Context) (Task, error) {
+	for {
+		task, next, err := q.claim()
+		if err != nil || task.ID != "" {
+			return task, err
+		}
+
+		if err := q.wait(ctx, next); err != nil {
+			return Task{}, err
+		}
+	}
+}
+
+// wait returns once a task may be due, when a task is pushed or retried or at next unless it is zero
+func (q *Queue) wait(ctx context.Context, next time.Time) error {
+	var timer <-chan time.Time
+	if !next.IsZero() {
+		t := time.NewTimer(next.Sub(q.now()))
+		defer t.Stop()
+
+		timer = t.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-q.wake:
+	case <-timer:
+	}
+
+	return nil
+}
+
+// claim marks the first due task running, or returns when the next pending task is due
+func (q *Queue) claim() (Task, time.Time, error) {
+	var (
+		task Task
+		next time.Time
+		more bool
+	)
+
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(dueBucket).Cursor()
+
+		k, v := c.First()
+		if k == nil {
+			return nil
+		}
+
+		now := q.now()
+		if due := dueOf(k); due.After(now) {
+			next = due
+
+			return nil
+		}
+
+		claimed, ok, err := get(tx, string(v))
+		if err != nil {
+			return err
+		}
+
+		if err := c.Delete(); err != nil {
+			return err
+		}
+
+		// an index entry without its task is dropped
+		if !ok {
+			more = true
+
+			return nil
+		}
+
+		claimed.State = StateRunning
+		claimed.Attempts++
+		claimed.UpdatedAt = now
+		task = claimed
+
+		k, _ = c.First()
+		more = k != nil && !dueOf(k).After(now)
+
+		return put(tx, task)
+	})
+	if err != nil {
+		return Task{}, time.Time{}, errors.Wrap(err, "failed to claim task")
+	}
+
+	// other workers may claim the remaining due tasks
+	if more {
+		q.signal()
+	}
+
+	return task, next, nil
+}
+
+// Complete marks the task with the given ID done with result
+func (q *Queue) Complete(ctx context.Context, id string, result interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode result")
+	}
+
+	return q.update(id, func(task *Task) {
+		task.State = StateDone
+		task.Result = data
+	})
+}
+
+// Retry makes the task with the given ID pending again, due at the given time
+func (q *Queue) Retry(ctx context.Context, id string, due time.Time) error {
+	err := q.update(id, func(task *Task) {
+		task.State = StatePending
+		task.DueAt = due
+	})
+	if err != nil {
+		return err
+	}
+
+	q.signal()
+
+	return nil
+}
+
+// Purge removes the tasks done before the given time and returns the number removed
+func (q *Queue) Purge(ctx context.Context, before time.Time) (int, error) {
+	removed := 0
+
+	err := q.db.Update(func(tx *bolt.Tx) error {
+		tasks := tx.Bucket(taskBucket)
+
+		var ids [][]byte
+
+		err := tasks.ForEach(func(k, v []byte) error {
+			var task Task
+			if err := json.Unmarshal(v, &task); err != nil {
+				return errors.Wrapf(err, "failed to decode task %s", k)
+			}
+
+			if task.State == StateDone && task.UpdatedAt.Before(before) {
+				ids = append(ids, k)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := tasks.Delete(id); err != nil {
+				return err
+			}
+		}
+
+		removed = len(ids)
+
+		return nil
+	})
+
+	return removed, err
+}
+
+func (q *Queue) update(id string, fn func(task *Task)) error {
+	return q.db.Update(func(tx *bolt.Tx) error {
+		task, ok, err := get(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return errors.Wrap(ErrNotFound, id)
+		}
+
+		if task.State == StatePending {
+			if err := tx.Bucket(dueBucket).Delete(dueKey(task)); err != nil {
+				return err
+			}
+		}
+
+		fn(&task)
+		task.UpdatedAt = q.now()
+
+		return put(tx, task)
+	})
+}
+
+// signal wakes up a waiting Claim, if any
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func get(tx *bolt.Tx, id string) (Task, bool, error) {
+	data := tx.Bucket(taskBucket).Get([]byte(id))
+	if data == nil {
+		return Task{}, false, nil
+	}
+
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return Task{}, false, errors.Wrapf(err, "failed to decode task %s", id)
+	}
+
+	return task, true, nil
+}
+
+// put stores the task, pending tasks are indexed by the time they are due
+func put(tx *bolt.Tx, task Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode task %s", task.ID)
+	}
+
+	if err := tx.Bucket(taskBucket).Put([]byte(task.ID), data); err != nil {
+		return err
+	}
+
+	if task.State != StatePending {
+		return nil
+	}
+
+	return tx.Bucket(dueBucket).Put(dueKey(task), []byte(task.ID))
+}
+
+// dueKey is the due time in big endian nanoseconds followed by the ID, so keys sort by due time
+func dueKey(task Task) []byte {
+	key := make([]byte, 8, 8+len(task.ID))
+	binary.BigEndian.PutUint64(key, uint64(task.DueAt.UnixNano()))
+
+	return append(key, task.ID...)
+}
+
+func dueOf(key []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[:8])))
+}
+
+func newID() (string, error) {
+	b := make([]byte, idSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate task id")
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,265 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func openQueue(t *testing.T, path string) *Queue {
+	t.Helper()
+
+	q, err := Open(path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = q.Close() })
+
+	return q
+}
+
+func push(t *testing.T, q *Queue, payload string) Task {
+	t.Helper()
+
+	task, err := q.Push(context.Background(), payload)
+	require.NoError(t, err)
+
+	return task
+}
+
+func claim(t *testing.T, q *Queue, timeout time.Duration) (Task, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return q.Claim(ctx)
+}
+
+func payload(t *testing.T, task Task) string {
+	t.Helper()
+
+	var s string
+	require.NoError(t, json.Unmarshal(task.Payload, &s))
+
+	return s
+}
+
+func TestOpen_Error(t *testing.T) {
+	t.Parallel()
+
+	q, err := Open("")
+	require.EqualError(t, err, "path: invalid parameter")
+	require.Nil(t, q)
+}
+
+func TestQueue(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		run func(t *testing.T, q *Queue)
+	}
+
+	tests := map[string]testcase{
+		"ClaimsAndCompletesTasks": {
+			run: func(t *testing.T, q *Queue) {
+				pushed := push(t, q, "mewtwo")
+				require.Len(t, pushed.ID, 2*idSize)
+				require.Equal(t, StatePending, pushed.State)
+
+				task, err := claim(t, q, time.Second)
+				require.NoError(t, err)
+				require.Equal(t, pushed.ID, task.ID)
+				require.Equal(t, StateRunning, task.State)
+				require.Equal(t, 1, task.Attempts)
+				require.Equal(t, "mewtwo", payload(t, task))
+
+				require.NoError(t, q.Complete(context.Background(), task.ID, map[string]string{"description": "translated"}))
+
+				task, ok, err := q.Get(context.Background(), pushed.ID)
+				require.NoError(t, err)
+				require.True(t, ok)
+				require.Equal(t, StateDone, task.State)
+				require.JSONEq(t, `{"description":"translated"}`, string(task.Result))
+
+				_, err = claim(t, q, 10*time.Millisecond)
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+			},
+		},
+		"ClaimsTasksInOrder": {
+			run: func(t *testing.T, q *Queue) {
+				for _, name := range []string{"mew", "mewtwo", "ditto"} {
+					push(t, q, name)
+					time.Sleep(time.Millisecond)
+				}
+
+				for _, name := range []string{"mew", "mewtwo", "ditto"} {
+					task, err := claim(t, q, time.Second)
+					require.NoError(t, err)
+					require.Equal(t, name, payload(t, task))
+				}
+			},
+		},
+		"RejectsTasksOverLimitUntilClaimed": {
+			run: func(t *testing.T, q *Queue) {
+				for _, name := range []string{"mew", "mewtwo"} {
+					_, err := q.PushBounded(context.Background(), name, 2)
+					require.NoError(t, err)
+				}
+
+				_, err := q.PushBounded(context.Background(), "ditto", 2)
+				require.ErrorIs(t, err, ErrFull)
+
+				_, err = claim(t, q, time.Second)
+				require.NoError(t, err)
+
+				_, err = q.PushBounded(context.Background(), "ditto", 2)
+				require.NoError(t, err)
+
+				_, err = q.PushBounded(context.Background(), "eevee", 0)
+				require.EqualError(t, err, "limit: invalid parameter")
+			},
+		},
+		"WaitsForPushedTasks": {
+			run: func(t *testing.T, q *Queue) {
+				go func() {
+					time.Sleep(20 * time.Millisecond)
+					_, _ = q.Push(context.Background(), "mewtwo")
+				}()
+
+				task, err := claim(t, q, time.Second)
+				require.NoError(t, err)
+				require.Equal(t, "mewtwo", payload(t, task))
+			},
+		},
+		"RetriesTasksOnceDue": {
+			run: func(t *testing.T, q *Queue) {
+				pushed := push(t, q, "mewtwo")
+
+				task, err := claim(t, q, time.Second)
+				require.NoError(t, err)
+
+				due := time.Now().Add(50 * time.Millisecond)
+				require.NoError(t, q.Retry(context.Background(), task.ID, due))
+
+				_, err = claim(t, q, 10*time.Millisecond)
+				require.ErrorIs(t, err, context.DeadlineExceeded)
+
+				task, err = claim(t, q, time.Second)
+				require.NoError(t, err)
+				require.Equal(t, pushed.ID, task.ID)
+				require.Equal(t, 2, task.Attempts)
+				require.False(t, time.Now().Before(due))
+			},
+		},
+		"ClaimsEveryTaskOnce": {
+			run: func(t *testing.T, q *Queue) {
+				pushed := map[string]int{}
+				for i := 0; i < 20; i++ {
+					pushed[push(t, q, "ditto").ID] = 1
+				}
+
+				var (
+					mu      sync.Mutex
+					claimed = map[string]int{}
+					wg      sync.WaitGroup
+				)
+
+				for i := 0; i < 4; i++ {
+					wg.Add(1)
+
+					go func() {
+						defer wg.Done()
+
+						for {
+							task, err := claim(t, q, 50*time.Millisecond)
+							if err != nil {
+								return
+							}
+
+							mu.Lock()
+							claimed[task.ID]++
+							mu.Unlock()
+						}
+					}()
+				}
+
+				wg.Wait()
+				require.Equal(t, pushed, claimed)
+			},
+		},
+		"PurgesTasksDoneBefore": {
+			run: func(t *testing.T, q *Queue) {
+				done := push(t, q, "mew")
+				pending := push(t, q, "mewtwo")
+
+				task, err := claim(t, q, time.Second)
+				require.NoError(t, err)
+				require.Equal(t, done.ID, task.ID)
+				require.NoError(t, q.Complete(context.Background(), task.ID, nil))
+
+				n, err := q.Purge(context.Background(), time.Now().Add(-time.Hour))
+				require.NoError(t, err)
+				require.Equal(t, 0, n)
+
+				n, err = q.Purge(context.Background(), time.Now().Add(time.Hour))
+				require.NoError(t, err)
+				require.Equal(t, 1, n)
+
+				_, ok, err := q.Get(context.Background(), done.ID)
+				require.NoError(t, err)
+				require.False(t, ok)
+
+				_, ok, err = q.Get(context.Background(), pending.ID)
+				require.NoError(t, err)
+				require.True(t, ok)
+			},
+		},
+		"ReturnsErrorWhenTaskUnknown": {
+			run: func(t *testing.T, q *Queue) {
+				require.ErrorIs(t, q.Complete(context.Background(), "missing", nil), ErrNotFound)
+				require.ErrorIs(t, q.Retry(context.Background(), "missing", time.Now()), ErrNotFound)
+
+				_, ok, err := q.Get(context.Background(), "missing")
+				require.NoError(t, err)
+				require.False(t, ok)
+			},
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			tc.run(t, openQueue(t, filepath.Join(t.TempDir(), "jobs.db")))
+		})
+	}
+}
+
+func TestQueue_RecoversRunningTasks(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "jobs.db")
+
+	q, err := Open(path)
+	require.NoError(t, err)
+
+	pushed := push(t, q, "mewtwo")
+
+	_, err = claim(t, q, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, q.Close())
+
+	q = openQueue(t, path)
+
+	task, err := claim(t, q, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, pushed.ID, task.ID)
+	require.Equal(t, 2, task.Attempts)
+}
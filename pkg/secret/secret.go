@@ -0,0 +1,114 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	redacted = "[REDACTED]"
+)
+
+// Source provides a secret value, e.g. an API key
+type Source interface {
+	// Secret returns the current secret value
+	Secret() (string, error)
+}
+
+// Static is a Source with a fixed value
+type Static string
+
+// Secret returns the static value
+func (s Static) Secret() (string, error) {
+	return string(s), nil
+}
+
+// File is a Source reading the secret from a file, e.g. a mounted Kubernetes secret. The file is read
+// again whenever its modification time changes so rotated secrets apply without a restart
+type File struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	value   string
+}
+
+// NewFile creates a new File source for the given path
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Secret returns the secret stored in the file, surrounding whitespace is trimmed
+func (f *File) Secret() (string, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to stat secret file")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if info.ModTime().Equal(f.modTime) && f.value != "" {
+		return f.value, nil
+	}
+
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read secret file")
+	}
+
+	f.value = strings.TrimSpace(string(data))
+	f.modTime = info.ModTime()
+
+	return f.value, nil
+}
+
+// Var holds a Source that can be replaced while in use, e.g. when the configuration is reloaded
+type Var struct {
+	mu     sync.RWMutex
+	source Source
+}
+
+// Set replaces the source, nil when none is configured
+func (v *Var) Set(source Source) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.source = source
+}
+
+// Get returns the current source, nil when none is configured
+func (v *Var) Get() Source {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return v.source
+}
+
+// Redact returns err with every occurrence of value masked in its message, the original error is
+// still reachable with errors.Is and errors.As
+func Redact(err error, value string) error {
+	if err == nil || value == "" || !strings.Contains(err.Error(), value) {
+		return err
+	}
+
+	return &redactedError{err: err, value: value}
+}
+
+type redactedError struct {
+	err   error
+	value string
+}
+
+func (e *redactedError) Error() string {
+	return strings.ReplaceAll(e.err.Error(), e.value, redacted)
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,91 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Secret(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "api-secret")
+	require.NoError(t, ioutil.WriteFile(path, []byte("first-secret\n"), 0600))
+
+	f := NewFile(path)
+
+	s, err := f.Secret()
+	require.NoError(t, err)
+	require.Equal(t, "first-secret", s)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("rotated-secret"), 0600))
+	modified := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, modified, modified))
+
+	s, err = f.Secret()
+	require.NoError(t, err)
+	require.Equal(t, "rotated-secret", s)
+
+	require.NoError(t, os.Remove(path))
+
+	_, err = f.Secret()
+	require.Error(t, err)
+}
+
+func TestVar(t *testing.T) {
+	t.Parallel()
+
+	var v Var
+	require.Nil(t, v.Get())
+
+	v.Set(Static("s3cr3t"))
+	require.Equal(t, Static("s3cr3t"), v.Get())
+
+	v.Set(nil)
+	require.Nil(t, v.Get())
+}
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	type testcase struct {
+		err   error
+		value string
+		msg   string
+	}
+
+	tests := map[string]testcase{
+		"MasksSecretInMessage": {
+			err:   errors.New("request with s3cr3t failed"),
+			value: "s3cr3t",
+			msg:   "request with [REDACTED] failed",
+		},
+		"KeepsMessageWithoutSecret": {
+			err:   errors.New("request failed"),
+			value: "s3cr3t",
+			msg:   "request failed",
+		},
+		"KeepsMessageWhenNoSecret": {
+			err:   errors.New("request failed"),
+			value: "",
+			msg:   "request failed",
+		},
+	}
+
+	for description, testCase := range tests {
+		tc := testCase
+
+		t.Run(description, func(t *testing.T) {
+			t.Parallel()
+
+			err := Redact(tc.err, tc.value)
+			require.EqualError(t, err, tc.msg)
+			require.True(t, errors.Is(err, tc.err))
+		})
+	}
+}